@@ -2,77 +2,493 @@ package billing
 
 import (
 	"context"
+	"net/http"
 
+	"fees-api/internal/handlers"
 	"fees-api/internal/model"
+	"fees-api/internal/presentation"
+	"fees-api/internal/service"
+	billingerrors "fees-api/pkg/errors"
 )
 
-//encore:api public method=POST path=/bills
-func CreateBill(ctx context.Context, req *model.CreateBillRequest) (*model.CreateBillResponse, error) {
+//encore:api public raw method=POST path=/bills
+func CreateBill(w http.ResponseWriter, req *http.Request) {
 	svc := GetService()
-	
-	bill, err := svc.svc.CreateBill(req)
-	if err != nil {
-		return nil, err
-	}
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CreateBillRequest) (*presentation.CreateBillResponse, error) {
+		bill, err := svc.svc.CreateBill(ctx, req)
+		if err != nil {
+			return nil, err
+		}
 
-	// Default to 30 days if not specified
-	billingPeriodDays := req.BillingPeriodDays
-	if billingPeriodDays <= 0 {
-		billingPeriodDays = 30
-	}
+		// Draft bills aren't accruing yet; their workflow starts on activation
+		if bill.Status == model.BillStatusOpen {
+			_ = svc.startWorkflow(ctx, bill)
+			// Line items from a template are part of the bill from the start
+			for _, item := range bill.LineItems {
+				_ = svc.signalAddItem(ctx, bill.ID, item.ID, float64(item.NetAmount())/100, string(bill.Currency))
+			}
+		}
 
-	// Automatically start the billing workflow
-	_ = svc.startWorkflow(ctx, bill.ID, string(bill.Currency), billingPeriodDays)
+		return &presentation.CreateBillResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/bills/:billID/activate
+func ActivateBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/activate")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.ActivateBillRequest) (*presentation.ActivateBillResponse, error) {
+		bill, err := svc.svc.ActivateBill(ctx, billID, req)
+		if err != nil {
+			return nil, err
+		}
 
-	return &model.CreateBillResponse{Bill: *bill}, nil
+		// The billing period starts once the bill goes live
+		_ = svc.startWorkflow(ctx, bill)
+
+		return &presentation.ActivateBillResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
 }
 
-//encore:api public method=POST path=/bills/:billID/items
-func AddLineItem(ctx context.Context, billID string, req *model.AddLineItemRequest) (*model.AddLineItemResponse, error) {
+//encore:api public raw method=POST path=/bills/:billID/items
+func AddLineItem(w http.ResponseWriter, req *http.Request) {
 	svc := GetService()
-	
-	bill, err := svc.svc.AddLineItem(billID, req)
-	if err != nil {
-		return nil, err
-	}
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/items")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.AddLineItemRequest) (*presentation.AddLineItemResponse, error) {
+		if err := svc.limiter.AllowCaller(ctx); err != nil {
+			return nil, err
+		}
+
+		bill, err := svc.svc.AddLineItem(ctx, billID, req)
+		if err != nil {
+			return nil, err
+		}
 
-	// Automatically signal the workflow
-	_ = svc.signalAddItem(ctx, billID, req.Amount, string(req.Currency))
+		// Automatically signal the workflow with the amount as charged in the bill's
+		// currency, so its running total stays comparable with the bill's
+		added := bill.LineItems[len(bill.LineItems)-1]
+		_ = svc.signalAddItem(ctx, billID, added.ID, float64(added.NetAmount())/100, string(bill.Currency))
 
-	return &model.AddLineItemResponse{Bill: *bill}, nil
+		return &presentation.AddLineItemResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
 }
 
-//encore:api public method=POST path=/bills/:billID/close
-func CloseBill(ctx context.Context, billID string) (*model.CloseBillResponse, error) {
+//encore:api public raw method=PUT path=/bills/:billID/items
+func ReplaceLineItems(w http.ResponseWriter, req *http.Request) {
 	svc := GetService()
-	
-	bill, err := svc.svc.CloseBill(billID)
-	if err != nil {
-		return nil, err
-	}
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/items")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.ReplaceLineItemsRequest) (*presentation.ReplaceLineItemsResponse, error) {
+		if err := svc.limiter.AllowCaller(ctx); err != nil {
+			return nil, err
+		}
+
+		// The workflow's running state only tracks incremental adds, so a full
+		// replacement isn't signalled; the bill record stays authoritative
+		bill, err := svc.svc.ReplaceLineItems(ctx, billID, req.LineItems)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.ReplaceLineItemsResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+// UpdateLineItem changes only the fields the request sets. The line_item_updated
+// event it publishes is forwarded to the bill's workflow, which moves its running
+// total by the change.
+//
+//encore:api public raw method=PATCH path=/bills/:billID/items/:lineItemID
+func UpdateLineItem(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	params := handlers.PathParams(req.URL.Path, "/bills/:billID/items/:lineItemID")
+	billID, lineItemID := params["billID"], params["lineItemID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.UpdateLineItemRequest) (*presentation.UpdateLineItemResponse, error) {
+		if err := svc.limiter.AllowCaller(ctx); err != nil {
+			return nil, err
+		}
+
+		bill, err := svc.svc.UpdateLineItem(ctx, billID, lineItemID, req)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.UpdateLineItemResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/bills/:billID/close
+func CloseBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/close")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CloseBillRequest) (*presentation.CloseBillResponse, error) {
+		bill, err := svc.svc.CloseBill(ctx, billID, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// Automatically signal the workflow to close
+		_ = svc.signalCloseBill(ctx, billID, req.Reason, false)
+
+		return handlers.NewCloseBillResponse(ctx, svc.svc, bill), nil
+	})).ServeHTTP(w, req)
+}
+
+// ForceCloseBill closes a bill at once for ops, even one awaiting approval, and
+// ends its billing period workflow without waiting for the period-end close to
+// succeed. It needs an admin key, recorded as the close approval, and the orgId
+// query parameter naming the bill's org.
+//
+//encore:api public raw method=POST path=/admin/bills/:billID/force-close
+func ForceCloseBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/admin/bills/:billID/force-close")["billID"]
+	svc.auth.RequireAdmin(handlers.RequireQueryOrg(handlers.Typed(func(ctx context.Context, req *model.ForceCloseBillRequest) (*presentation.CloseBillResponse, error) {
+		bill, err := svc.svc.ForceCloseBill(ctx, billID)
+		if err != nil {
+			return nil, err
+		}
+
+		_ = svc.signalCloseBill(ctx, billID, req.Reason, true)
+
+		return handlers.NewCloseBillResponse(ctx, svc.svc, bill), nil
+	}))).ServeHTTP(w, req)
+}
+
+// GetReceipt returns the receipt issued by the bill's most recent close
+//
+//encore:api public raw method=GET path=/bills/:billID/receipt
+func GetReceipt(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/receipt")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*presentation.GetReceiptResponse, error) {
+		return handlers.NewBillingHandler(svc.svc).GetReceipt(ctx, billID)
+	})).ServeHTTP(w, req)
+}
+
+// ListReceipts returns every receipt issued for the bill, one per close
+//
+//encore:api public raw method=GET path=/bills/:billID/receipts
+func ListReceipts(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/receipts")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*presentation.ListReceiptsResponse, error) {
+		return handlers.NewBillingHandler(svc.svc).ListReceipts(ctx, billID)
+	})).ServeHTTP(w, req)
+}
+
+// PreviewClose returns the final invoice closing the bill would produce, without
+// closing it or signalling its workflow
+//
+//encore:api public raw method=POST path=/bills/:billID/close/preview
+func PreviewClose(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/close/preview")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CloseBillRequest) (*presentation.PreviewCloseResponse, error) {
+		bill, err := svc.svc.PreviewClose(ctx, billID, req)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.PreviewCloseResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/batch/bills/close
+func CloseBills(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CloseBillsRequest) (*presentation.CloseBillsResponse, error) {
+		results, err := svc.svc.CloseBills(ctx, req.BillIDs, &model.CloseBillRequest{AllowEmpty: req.AllowEmpty, ApprovedBy: req.ApprovedBy})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			if result.Outcome == model.CloseOutcomeClosed {
+				_ = svc.signalCloseBill(ctx, result.BillID, req.Reason, false)
+			}
+		}
+
+		return presentation.NewCloseBillsResponse(results), nil
+	})).ServeHTTP(w, req)
+}
+
+// ApproveBill approves closing a bill whose total is above the auto-close limit
+//
+//encore:api public raw method=POST path=/bills/:billID/approve
+func ApproveBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/approve")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.ApproveBillRequest) (*presentation.ApproveBillResponse, error) {
+		bill, err := svc.svc.ApproveBill(ctx, billID, req.Approver)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.ApproveBillResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+// SplitBill moves line items off an open bill onto a new one, e.g. to invoice
+// disputed charges separately
+//
+//encore:api public raw method=POST path=/bills/:billID/split
+func SplitBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/split")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.SplitBillRequest) (*presentation.SplitBillResponse, error) {
+		source, split, err := svc.svc.SplitBill(ctx, billID, req.LineItemIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		// The new bill gets its own billing period workflow, seeded with the moved
+		// items. As with a replacement, the source's workflow isn't told of removals;
+		// the bill record stays authoritative.
+		_ = svc.startWorkflow(ctx, split)
+		for _, item := range split.LineItems {
+			_ = svc.signalAddItem(ctx, split.ID, item.ID, float64(item.NetAmount())/100, string(split.Currency))
+		}
 
-	// Automatically signal the workflow to close
-	_ = svc.signalCloseBill(ctx, billID)
+		return &presentation.SplitBillResponse{Source: presentation.NewBillView(source), Bill: presentation.NewBillView(split)}, nil
+	})).ServeHTTP(w, req)
+}
+
+// MergeBills combines open bills of the same currency into the target bill,
+// soft-deleting the sources
+//
+//encore:api public raw method=POST path=/bills/:billID/merge
+func MergeBills(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/merge")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.MergeBillsRequest) (*presentation.MergeBillsResponse, error) {
+		// As with a replacement, the target's workflow isn't told of the items it
+		// gained; the bill record stays authoritative
+		bill, err := svc.svc.MergeBills(ctx, billID, req.SourceBillIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		return &presentation.MergeBillsResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/bills/:billID/reopen
+func ReopenBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/reopen")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*presentation.ReopenBillResponse, error) {
+		// The billing period workflow completed when the bill closed, so reopened
+		// bills are closed manually rather than by the period timer
+		bill, err := svc.svc.ReopenBill(ctx, billID)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.ReopenBillResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=PUT path=/bills/:billID/note
+func UpdateNote(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/note")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.UpdateNoteRequest) (*presentation.UpdateNoteResponse, error) {
+		bill, err := svc.svc.UpdateNote(ctx, billID, req.Note)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.UpdateNoteResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+// RecordPayment records a payment received against a closed bill
+//
+//encore:api public raw method=POST path=/bills/:billID/payments
+func RecordPayment(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/payments")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.RecordPaymentRequest) (*presentation.RecordPaymentResponse, error) {
+		return handlers.NewBillingHandler(svc.svc).RecordPayment(ctx, billID, req)
+	})).ServeHTTP(w, req)
+}
 
-	return &model.CloseBillResponse{Bill: *bill}, nil
+//encore:api public raw method=PUT path=/bills/:billID/currency
+func ChangeCurrency(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/currency")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.ChangeCurrencyRequest) (*presentation.ChangeCurrencyResponse, error) {
+		// Only empty bills change currency and the workflow tracks no amounts until
+		// items arrive, so its state doesn't need a signal
+		bill, err := svc.svc.ChangeCurrency(ctx, billID, req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.ChangeCurrencyResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
 }
 
-//encore:api public method=GET path=/bills/:billID
-func GetBill(ctx context.Context, billID string) (*model.GetBillResponse, error) {
+// RecalculateTotal recomputes a bill's total from its line items. It needs an admin
+// key and the orgId query parameter naming the bill's org.
+//
+//encore:api private raw method=POST path=/admin/bills/:billID/recalculate
+func RecalculateTotal(w http.ResponseWriter, req *http.Request) {
 	svc := GetService()
-	bill, err := svc.svc.GetBill(billID)
+	billID := handlers.PathParams(req.URL.Path, "/admin/bills/:billID/recalculate")["billID"]
+	svc.auth.RequireAdmin(handlers.RequireQueryOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*presentation.RecalculateTotalResponse, error) {
+		return handlers.NewBillingHandler(svc.svc).RecalculateTotal(ctx, billID)
+	}))).ServeHTTP(w, req)
+}
+
+// RecalculateOpenBills re-converts the cross-currency line items of every bill that
+// isn't closed after the currency's rate changed. Like SetExchangeRate it needs an
+// admin key, so the run is audit logged with the operator.
+//
+//encore:api private raw method=POST path=/admin/rates/:currency/recalculate
+func RecalculateOpenBills(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	currency := handlers.PathParams(req.URL.Path, "/admin/rates/:currency/recalculate")["currency"]
+	svc.auth.RequireAdmin(handlers.Typed(func(ctx context.Context, _ *struct{}) (*presentation.RecalculateOpenBillsResponse, error) {
+		return handlers.NewBillingHandler(svc.svc).RecalculateOpenBills(ctx, currency)
+	})).ServeHTTP(w, req)
+}
+
+// ReconcileBill compares a bill with its billing period workflow's state to catch
+// missed signals. Like RecalculateTotal it needs an admin key and the bill's orgId.
+//
+//encore:api private raw method=GET path=/admin/bills/:billID/reconcile
+func ReconcileBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/admin/bills/:billID/reconcile")["billID"]
+	svc.auth.RequireAdmin(handlers.RequireQueryOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.ReconciliationReport, error) {
+		bill, err := svc.svc.GetBill(ctx, billID, false)
+		if err != nil {
+			return nil, err
+		}
+		state, err := svc.queryBillState(ctx, billID)
+		if err != nil {
+			return nil, billingerrors.Unavailable("query billing period workflow: %v", err)
+		}
+		report := service.Reconcile(bill, state)
+		return &report, nil
+	}))).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=DELETE path=/bills/:billID
+func DeleteBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*presentation.DeleteBillResponse, error) {
+		bill, err := svc.svc.SoftDeleteBill(ctx, billID)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.DeleteBillResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/bills/:billID/restore
+func RestoreBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/restore")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*presentation.RestoreBillResponse, error) {
+		bill, err := svc.svc.RestoreBill(ctx, billID)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.RestoreBillResponse{Bill: presentation.NewBillView(bill)}, nil
+	})).ServeHTTP(w, req)
+}
+
+// GetBill is a raw endpoint because conditional responses need header access:
+// it returns an ETag and answers 304 Not Modified for a matching If-None-Match.
+// Being raw, it authenticates the caller's org itself.
+//
+//encore:api public raw method=GET path=/bills/:billID
+func GetBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID")["billID"]
+	svc.auth.RequireOrg(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlers.NewBillingHandler(svc.svc, handlers.WithEventLog(svc.eventLog)).ServeGetBill(w, req, billID)
+	})).ServeHTTP(w, req)
+}
+
+// WatchBill is a raw endpoint because it answers 304 Not Modified when the bill
+// doesn't change before the long-poll times out.
+//
+//encore:api public raw method=GET path=/bills/:billID/watch
+func WatchBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/watch")["billID"]
+	svc.auth.RequireOrg(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlers.NewBillingHandler(svc.svc).ServeWatchBill(w, req, billID)
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=GET path=/bills
+func ListBills(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.ListBillsRequest) (*presentation.ListBillsResponse, error) {
+		return handlers.NewBillingHandler(svc.svc).ListBills(ctx, req)
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public method=GET path=/convert
+func ConvertCurrency(ctx context.Context, req *model.ConvertCurrencyRequest) (*presentation.ConvertCurrencyResponse, error) {
+	svc := GetService()
+	conversion, err := svc.svc.ConvertCurrency(req)
 	if err != nil {
 		return nil, err
 	}
-	return &model.GetBillResponse{Bill: *bill}, nil
+	return presentation.NewConvertCurrencyResponse(conversion), nil
 }
 
-//encore:api public method=GET path=/bills
-func ListBills(ctx context.Context, req *model.ListBillsRequest) (*model.ListBillsResponse, error) {
+//encore:api public method=GET path=/currencies
+func ListCurrencies(ctx context.Context) (*presentation.ListCurrenciesResponse, error) {
 	svc := GetService()
-	bills, err := svc.svc.ListBills(req.Status)
+	currencies, err := svc.svc.ListCurrencies()
 	if err != nil {
 		return nil, err
 	}
-	return &model.ListBillsResponse{Bills: bills}, nil
+	return presentation.NewListCurrenciesResponse(currencies), nil
+}
+
+// SetExchangeRate overrides a currency's USD rate for new conversions. It is a raw
+// endpoint so it can require an admin key and audit log the operator.
+//
+//encore:api public raw method=PUT path=/admin/rates/:currency
+func SetExchangeRate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	currency := model.Currency(handlers.PathParams(req.URL.Path, "/admin/rates/:currency")["currency"]).Normalize()
+	svc.auth.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlers.NewBillingHandler(svc.svc).ServeSetExchangeRate(w, req, currency)
+	})).ServeHTTP(w, req)
+}
+
+// ImportBill stores a historical bill that is already closed, for migrations. Like
+// SetExchangeRate it is raw so it can require an admin key.
+//
+//encore:api public raw method=POST path=/admin/bill-imports
+func ImportBill(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlers.NewBillingHandler(svc.svc).ServeImportBill(w, req)
+	})).ServeHTTP(w, req)
+}
+
+// ImportLineItemsCSV adds line items from an uploaded CSV of external charges across
+// many bills, returning a result per row. Raw so it can require an admin key and read
+// the CSV body as is.
+//
+//encore:api public raw method=POST path=/admin/line-item-imports
+func ImportLineItemsCSV(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlers.NewBillingHandler(svc.svc).ServeImportLineItemsCSV(w, req)
+	})).ServeHTTP(w, req)
+}
+
+// OverdueSummary totals each customer's overdue bills for the collections team. It
+// spans every org, so like the other admin endpoints it is raw and needs an admin key.
+//
+//encore:api public raw method=GET path=/admin/overdue-summary
+func OverdueSummary(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlers.NewBillingHandler(svc.svc).ServeOverdueSummary(w, req)
+	})).ServeHTTP(w, req)
 }
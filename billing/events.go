@@ -0,0 +1,30 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+
+	"fees-api/internal/handlers"
+	"fees-api/internal/presentation"
+	billingerrors "fees-api/pkg/errors"
+)
+
+//encore:api public raw method=GET path=/bills/:billID/events
+func GetBillEvents(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	billID := handlers.PathParams(req.URL.Path, "/bills/:billID/events")["billID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*presentation.GetBillEventsResponse, error) {
+		exists, err := svc.svc.BillExists(ctx, billID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, billingerrors.BillNotFound(billID)
+		}
+		billEvents, err := svc.eventLog.GetBillEvents(ctx, billID)
+		if err != nil {
+			return nil, err
+		}
+		return &presentation.GetBillEventsResponse{Events: billEvents}, nil
+	})).ServeHTTP(w, req)
+}
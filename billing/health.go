@@ -0,0 +1,13 @@
+package billing
+
+import (
+	"context"
+
+	"fees-api/internal/model"
+)
+
+//encore:api public method=GET path=/health
+func Health(ctx context.Context) (*model.HealthResponse, error) {
+	svc := GetService()
+	return svc.svc.CheckHealth(ctx)
+}
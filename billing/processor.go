@@ -0,0 +1,39 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"fees-api/internal/handlers"
+	"fees-api/internal/model"
+)
+
+// ProcessorWebhook ingests fee notifications from the payment processor as line
+// items. It is raw because the processor's signature covers the exact body; the
+// signature, not an API key, authenticates the call.
+//
+//encore:api public raw method=POST path=/processor/webhooks
+func ProcessorWebhook(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	handlers.NewProcessorWebhookHandler(svc.processor, func(ctx context.Context, bill *model.Bill, lineItemID string) {
+		// Signal the bill's workflow as for any added line item
+		for _, item := range bill.LineItems {
+			if item.ID == lineItemID {
+				_ = svc.signalAddItem(ctx, bill.ID, item.ID, float64(item.NetAmount())/100, string(bill.Currency))
+			}
+		}
+	}).ServeWebhook(w, req)
+}
+
+// LinkProcessorRef routes the processor's fees for an external reference to a bill.
+// Raw so it can require an admin key.
+//
+//encore:api public raw method=PUT path=/admin/processor-refs/:ref
+func LinkProcessorRef(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	ref := strings.TrimPrefix(req.URL.Path, "/admin/processor-refs/")
+	svc.auth.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handlers.NewProcessorWebhookHandler(svc.processor, nil).ServeLinkRef(w, req, ref)
+	})).ServeHTTP(w, req)
+}
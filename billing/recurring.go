@@ -0,0 +1,108 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+
+	"fees-api/internal/handlers"
+	"fees-api/internal/model"
+)
+
+//encore:api public raw method=POST path=/recurring-templates
+func CreateRecurringTemplate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CreateRecurringTemplateRequest) (*model.RecurringTemplateResponse, error) {
+		template, err := svc.recurring.CreateTemplate(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// Schedule renewals; the first bill is materialized right away
+		_ = svc.startRecurringWorkflow(ctx, template.ID, template.OrgID, template.IntervalDays)
+
+		return &model.RecurringTemplateResponse{Template: *template}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=GET path=/recurring-templates/:templateID
+func GetRecurringTemplate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	templateID := handlers.PathParams(req.URL.Path, "/recurring-templates/:templateID")["templateID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.RecurringTemplateResponse, error) {
+		template, err := svc.recurring.GetTemplate(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.RecurringTemplateResponse{Template: *template}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/recurring-templates/:templateID/pause
+func PauseRecurringTemplate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	templateID := handlers.PathParams(req.URL.Path, "/recurring-templates/:templateID/pause")["templateID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.RecurringTemplateResponse, error) {
+		template, err := svc.recurring.PauseTemplate(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.RecurringTemplateResponse{Template: *template}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/recurring-templates/:templateID/resume
+func ResumeRecurringTemplate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	templateID := handlers.PathParams(req.URL.Path, "/recurring-templates/:templateID/resume")["templateID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.RecurringTemplateResponse, error) {
+		template, err := svc.recurring.ResumeTemplate(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.RecurringTemplateResponse{Template: *template}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/recurring-templates/:templateID/cancel
+func CancelRecurringTemplate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	templateID := handlers.PathParams(req.URL.Path, "/recurring-templates/:templateID/cancel")["templateID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.RecurringTemplateResponse, error) {
+		template, err := svc.recurring.CancelTemplate(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Stop the renewal timer instead of waiting for the next interval
+		_ = svc.signalCancelRecurring(ctx, templateID)
+
+		return &model.RecurringTemplateResponse{Template: *template}, nil
+	})).ServeHTTP(w, req)
+}
+
+// MaterializeRecurringTemplate is called by the recurring bill workflow on each renewal
+//
+//encore:api private raw method=POST path=/internal/recurring-templates/:templateID/materialize
+func MaterializeRecurringTemplate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	templateID := handlers.PathParams(req.URL.Path, "/internal/recurring-templates/:templateID/materialize")["templateID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.MaterializeRecurringTemplateResponse, error) {
+		template, err := svc.recurring.GetTemplate(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		if template.Status == model.RecurringTemplateCancelled {
+			return &model.MaterializeRecurringTemplateResponse{Cancelled: true}, nil
+		}
+
+		bill, err := svc.recurring.Materialize(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		if bill != nil {
+			// The bill's billing period lasts until the next renewal
+			_ = svc.startWorkflow(ctx, bill)
+		}
+		return &model.MaterializeRecurringTemplateResponse{Bill: bill}, nil
+	})).ServeHTTP(w, req)
+}
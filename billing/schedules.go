@@ -0,0 +1,121 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+
+	"fees-api/internal/handlers"
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+//encore:api public raw method=POST path=/billing-schedules
+func CreateBillingSchedule(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CreateBillingScheduleRequest) (*model.BillingScheduleResponse, error) {
+		schedule, err := svc.schedules.CreateSchedule(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// The first period starts on the next 1st of the month. Without its Temporal
+		// schedule the stored one would never fire, so it's removed again.
+		if err := svc.createBillingSchedule(ctx, schedule); err != nil {
+			_, _ = svc.schedules.DeleteSchedule(ctx, schedule.ID)
+			return nil, billingerrors.Unavailable("create Temporal schedule: %v", err)
+		}
+
+		return &model.BillingScheduleResponse{Schedule: *schedule}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=GET path=/billing-schedules/:scheduleID
+func GetBillingSchedule(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	scheduleID := handlers.PathParams(req.URL.Path, "/billing-schedules/:scheduleID")["scheduleID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.BillingScheduleResponse, error) {
+		schedule, err := svc.schedules.GetSchedule(ctx, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BillingScheduleResponse{Schedule: *schedule}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/billing-schedules/:scheduleID/pause
+func PauseBillingSchedule(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	scheduleID := handlers.PathParams(req.URL.Path, "/billing-schedules/:scheduleID/pause")["scheduleID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.BillingScheduleResponse, error) {
+		// The Temporal schedule changes first, so a failure leaves both as they were
+		if _, err := svc.schedules.GetSchedule(ctx, scheduleID); err != nil {
+			return nil, err
+		}
+		if err := svc.pauseBillingSchedule(ctx, scheduleID, true); err != nil {
+			return nil, billingerrors.Unavailable("pause Temporal schedule: %v", err)
+		}
+		schedule, err := svc.schedules.PauseSchedule(ctx, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BillingScheduleResponse{Schedule: *schedule}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=POST path=/billing-schedules/:scheduleID/resume
+func ResumeBillingSchedule(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	scheduleID := handlers.PathParams(req.URL.Path, "/billing-schedules/:scheduleID/resume")["scheduleID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.BillingScheduleResponse, error) {
+		if _, err := svc.schedules.GetSchedule(ctx, scheduleID); err != nil {
+			return nil, err
+		}
+		if err := svc.pauseBillingSchedule(ctx, scheduleID, false); err != nil {
+			return nil, billingerrors.Unavailable("unpause Temporal schedule: %v", err)
+		}
+		schedule, err := svc.schedules.ResumeSchedule(ctx, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BillingScheduleResponse{Schedule: *schedule}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=DELETE path=/billing-schedules/:scheduleID
+func DeleteBillingSchedule(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	scheduleID := handlers.PathParams(req.URL.Path, "/billing-schedules/:scheduleID")["scheduleID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.BillingScheduleResponse, error) {
+		if _, err := svc.schedules.GetSchedule(ctx, scheduleID); err != nil {
+			return nil, err
+		}
+		if err := svc.deleteBillingSchedule(ctx, scheduleID); err != nil {
+			return nil, billingerrors.Unavailable("delete Temporal schedule: %v", err)
+		}
+		schedule, err := svc.schedules.DeleteSchedule(ctx, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BillingScheduleResponse{Schedule: *schedule}, nil
+	})).ServeHTTP(w, req)
+}
+
+// StartScheduledPeriod is called by the scheduled billing workflow when a schedule fires
+//
+//encore:api private raw method=POST path=/internal/billing-schedules/:scheduleID/start
+func StartScheduledPeriod(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	scheduleID := handlers.PathParams(req.URL.Path, "/internal/billing-schedules/:scheduleID/start")["scheduleID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.StartScheduledPeriodResponse, error) {
+		bill, err := svc.schedules.StartPeriod(ctx, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		if bill == nil {
+			return &model.StartScheduledPeriodResponse{Skipped: true}, nil
+		}
+
+		_ = svc.startWorkflow(ctx, bill)
+		return &model.StartScheduledPeriodResponse{Bill: bill}, nil
+	})).ServeHTTP(w, req)
+}
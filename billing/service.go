@@ -3,7 +3,18 @@ package billing
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"fees-api/internal/events"
+	"fees-api/internal/handlers"
+	"fees-api/internal/lifecycle"
+	"fees-api/internal/model"
 	"fees-api/internal/repository"
 	"fees-api/internal/service"
 	"fees-api/workflow"
@@ -14,19 +25,109 @@ import (
 
 // Service represents the billing service with Temporal integration
 type Service struct {
-	client client.Client
-	worker worker.Worker
-	svc    *service.BillingService
+	client    client.Client
+	worker    worker.Worker
+	svc       *service.BillingService
+	webhooks  *service.WebhookService
+	eventLog  *service.EventService
+	recurring *service.RecurringService
+	schedules *service.BillingScheduleService
+	processor *service.ProcessorWebhookService
+	topic     *events.Topic
+	limiter   *handlers.RateLimiter
+	auth      *handlers.Authenticator
+	lifecycle *lifecycle.Lifecycle
 }
 
 var (
 	billingService *Service
+	serviceMu      sync.Mutex // guards lazy initialization of billingService
 )
 
 // Task queue name
 const taskQueueName = "billing-task-queue"
 
+// abandonedBillSweepInterval is how often empty bills are checked against their TTL
+const abandonedBillSweepInterval = 15 * time.Minute
+
 func initService() (*Service, error) {
+	delivery, err := eventDeliveryConfig()
+	if err != nil {
+		return nil, fmt.Errorf("configure event delivery: %v", err)
+	}
+
+	// Record bill events and fan them out to registered webhooks
+	topic := events.NewTopic()
+	eventSvc := service.NewEventService(repository.NewInMemoryEventStore())
+	if err := topic.SubscribeWithDelivery(eventSvc.HandleEvent, delivery); err != nil {
+		return nil, fmt.Errorf("subscribe event log: %v", err)
+	}
+	webhooks := service.NewWebhookService(repository.NewInMemoryWebhookRepository(), service.DefaultWebhookConfig())
+	if err := topic.SubscribeWithDelivery(webhooks.HandleEvent, delivery); err != nil {
+		return nil, fmt.Errorf("subscribe webhooks: %v", err)
+	}
+
+	// Long-polls on a bill wake when it publishes an event
+	watcher := service.NewBillWatcher()
+	topic.Subscribe(watcher.HandleEvent)
+
+	// Events go out one per change unless BILLING_EVENT_BATCH_WINDOW (e.g. "200ms")
+	// coalesces them into batches
+	var publisher events.Publisher = topic
+	var batcher *events.BufferedPublisher
+	if window, err := time.ParseDuration(os.Getenv("BILLING_EVENT_BATCH_WINDOW")); err == nil && window > 0 {
+		batcher = events.NewBufferedPublisher(topic, window)
+		publisher = batcher
+	}
+
+	// Create billing service
+	repo := repository.NewInMemoryBillRepository()
+	// Fails fast on a bad exchange rate configuration, before connecting to Temporal
+	svc, err := service.NewBillingService(repo,
+		service.WithPublisher(publisher),
+		service.WithMetrics(service.NewExpvarMetrics("billing")),
+		service.WithLogger(slog.Default()),
+		service.WithDedupWindow(10*time.Second),
+		service.WithBillWatcher(watcher),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create billing service: %v", err)
+	}
+
+	// Empty bills older than BILLING_ABANDONED_BILL_TTL (e.g. "72h") are voided in the
+	// background. Drafts only, unless BILLING_ABANDONED_BILL_STATUSES says "draft,open".
+	var sweeper *service.AbandonedBillSweeper
+	if ttl, err := time.ParseDuration(os.Getenv("BILLING_ABANDONED_BILL_TTL")); err == nil && ttl > 0 {
+		policy := service.AbandonedBillPolicy{TTL: ttl}
+		if raw := os.Getenv("BILLING_ABANDONED_BILL_STATUSES"); raw != "" {
+			for _, status := range strings.Split(raw, ",") {
+				policy.Statuses = append(policy.Statuses, model.BillStatus(strings.TrimSpace(status)))
+			}
+		}
+		if sweeper, err = service.NewAbandonedBillSweeper(svc, policy, abandonedBillSweepInterval); err != nil {
+			return nil, fmt.Errorf("configure abandoned bill sweeper: %v", err)
+		}
+	}
+
+	// Drain in order on shutdown: stop the sweeper, flush pending batches, stop
+	// publishing and let in-flight handlers finish, then let the webhook deliveries
+	// they started finish their retries
+	lc := lifecycle.New()
+	if sweeper != nil {
+		lc.OnShutdown("abandoned bill sweeper", sweeper.Shutdown)
+	}
+	if batcher != nil {
+		lc.OnShutdown("event batches", batcher.Close)
+	}
+	lc.OnShutdown("event topic", topic.Close)
+	lc.OnShutdown("webhooks", webhooks.Shutdown)
+
+	auth := handlers.NewAuthenticator(
+		handlers.ParseAPIKeys(os.Getenv("BILLING_API_KEYS")),
+		handlers.ParseAPIKeys(os.Getenv("BILLING_ADMIN_KEYS")),
+		os.Getenv("BILLING_INTERNAL_TOKEN"),
+	)
+
 	// Create Temporal client
 	c, err := client.Dial(client.Options{})
 	if err != nil {
@@ -39,6 +140,10 @@ func initService() (*Service, error) {
 	// Register workflow and activities
 	w.RegisterWorkflow(workflow.BillingPeriodWorkflow)
 	w.RegisterActivity(workflow.CloseBillActivity)
+	w.RegisterWorkflow(workflow.RecurringBillWorkflow)
+	w.RegisterActivity(workflow.MaterializeRecurringBillActivity)
+	w.RegisterWorkflow(workflow.ScheduledBillingWorkflow)
+	w.RegisterActivity(workflow.StartScheduledPeriodActivity)
 
 	// Start the worker
 	err = w.Start()
@@ -46,25 +151,85 @@ func initService() (*Service, error) {
 		c.Close()
 		return nil, fmt.Errorf("start temporal worker: %v", err)
 	}
+	if sweeper != nil {
+		sweeper.Start()
+	}
 
-	// Create billing service
-	repo := repository.NewInMemoryBillRepository()
-	svc := service.NewBillingService(repo)
+	s := &Service{
+		client:    c,
+		worker:    w,
+		svc:       svc,
+		webhooks:  webhooks,
+		eventLog:  eventSvc,
+		recurring: service.NewRecurringService(repository.NewInMemoryRecurringTemplateRepository(), svc),
+		schedules: service.NewBillingScheduleService(repository.NewInMemoryBillingScheduleRepository(), svc),
+		// Processor webhooks are refused until BILLING_PROCESSOR_WEBHOOK_SECRET is set
+		processor: service.NewProcessorWebhookService(repository.NewInMemoryProcessorRefRepository(), svc,
+			os.Getenv("BILLING_PROCESSOR_WEBHOOK_SECRET")),
+		topic:     topic,
+		limiter:   handlers.NewRateLimiter(handlers.DefaultRateLimitConfig(), handlers.NewInMemoryBucketStore()),
+		auth:      auth,
+		lifecycle: lc,
+	}
+	// Subscribed once the Temporal client exists; earlier events had no workflow
+	// to correct
+	topic.Subscribe(s.forwardLineItemUpdate)
+	return s, nil
+}
 
-	return &Service{
-		client: c,
-		worker: w,
-		svc:    svc,
-	}, nil
+// eventDeliveryConfig reads the bill event subscribers' delivery settings from the
+// environment, starting from events.DefaultDeliveryConfig:
+//   - BILLING_EVENT_DELIVERY: "at_least_once" or "best_effort"
+//   - BILLING_EVENT_ACK_DEADLINE: how long one delivery attempt may take, e.g. "5s"
+//   - BILLING_EVENT_MAX_ATTEMPTS: deliveries per event, including the first
+//   - BILLING_EVENT_DEAD_LETTER: whether to keep events whose retries ran out
+//
+// Choosing best_effort also turns dead letters off, as it has no retries to run out.
+func eventDeliveryConfig() (events.DeliveryConfig, error) {
+	config := events.DefaultDeliveryConfig()
+	if raw := os.Getenv("BILLING_EVENT_DELIVERY"); raw != "" {
+		config.Guarantee = events.DeliveryGuarantee(raw)
+		config.DeadLetter = config.Guarantee == events.DeliveryAtLeastOnce
+	}
+	if raw := os.Getenv("BILLING_EVENT_ACK_DEADLINE"); raw != "" {
+		deadline, err := time.ParseDuration(raw)
+		if err != nil {
+			return config, fmt.Errorf("BILLING_EVENT_ACK_DEADLINE: %v", err)
+		}
+		config.AckDeadline = deadline
+	}
+	if raw := os.Getenv("BILLING_EVENT_MAX_ATTEMPTS"); raw != "" {
+		attempts, err := strconv.Atoi(raw)
+		if err != nil {
+			return config, fmt.Errorf("BILLING_EVENT_MAX_ATTEMPTS: %v", err)
+		}
+		config.Retry.MaxAttempts = attempts
+	}
+	if raw := os.Getenv("BILLING_EVENT_DEAD_LETTER"); raw != "" {
+		deadLetter, err := strconv.ParseBool(raw)
+		if err != nil {
+			return config, fmt.Errorf("BILLING_EVENT_DEAD_LETTER: %v", err)
+		}
+		config.DeadLetter = deadLetter
+	}
+	return config, config.Validate()
 }
 
-func (s *Service) Shutdown(ctx context.Context) {
-	s.client.Close()
+// Shutdown is called by Encore when the service stops. It stops the worker so no new
+// activities start, drains in-flight events and webhook deliveries until force is
+// done, then closes the Temporal client.
+func (s *Service) Shutdown(force context.Context) {
 	s.worker.Stop()
+	if err := s.lifecycle.Shutdown(force); err != nil {
+		slog.Error("billing service shutdown did not drain cleanly", "error", err)
+	}
+	s.client.Close()
 }
 
 // GetService returns the billing service (lazy initialization)
 func GetService() *Service {
+	serviceMu.Lock()
+	defer serviceMu.Unlock()
 	if billingService == nil {
 		svc, err := initService()
 		if err != nil {
@@ -75,37 +240,152 @@ func GetService() *Service {
 	return billingService
 }
 
-// startWorkflow starts a billing period workflow for a bill
-func (s *Service) startWorkflow(ctx context.Context, billID, currency string, billingPeriodDays int) error {
+// startWorkflow starts a billing period workflow for a bill, timed to end with the
+// bill's stored billing period. The workflow may run until a grace period after
+// that, rather than indefinitely.
+func (s *Service) startWorkflow(ctx context.Context, bill *model.Bill) error {
 	input := workflow.BillingPeriodInput{
-		BillID:            billID,
-		Currency:          currency,
-		BillingPeriodDays: billingPeriodDays,
+		BillID:    bill.ID,
+		OrgID:     bill.OrgID,
+		Currency:  string(bill.Currency),
+		PeriodEnd: bill.PeriodEnd,
+	}
+	if err := input.Validate(); err != nil {
+		return err
 	}
 
-	workflowID := "billing-period-" + billID
+	workflowID := "billing-period-" + bill.ID
 
 	_, err := s.client.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
-		ID:        workflowID,
-		TaskQueue: taskQueueName,
+		ID:                       workflowID,
+		TaskQueue:                taskQueueName,
+		WorkflowExecutionTimeout: input.ExecutionTimeout(time.Now()),
 	}, workflow.BillingPeriodWorkflow, input)
 
 	return err
 }
 
 // signalAddItem signals the workflow to add a line item
-func (s *Service) signalAddItem(ctx context.Context, billID string, amount float64, currency string) error {
+func (s *Service) signalAddItem(ctx context.Context, billID, lineItemID string, amount float64, currency string) error {
 	workflowID := "billing-period-" + billID
 
 	return s.client.SignalWorkflow(ctx, workflowID, "", "add-line-item", workflow.AddLineItemSignalInput{
-		Amount:   amount,
-		Currency: currency,
+		LineItemID: lineItemID,
+		Amount:     amount,
+		Currency:   currency,
 	})
 }
 
-// signalCloseBill signals the workflow to close the bill
-func (s *Service) signalCloseBill(ctx context.Context, billID string) error {
+// signalUpdateItem signals the workflow that a line item's amount may have changed
+func (s *Service) signalUpdateItem(ctx context.Context, billID, lineItemID string, amount float64, currency string) error {
+	workflowID := "billing-period-" + billID
+
+	return s.client.SignalWorkflow(ctx, workflowID, "", workflow.UpdateLineItemSignalName, workflow.UpdateLineItemSignalInput{
+		LineItemID: lineItemID,
+		Amount:     amount,
+		Currency:   currency,
+	})
+}
+
+// forwardLineItemUpdate signals a bill's workflow for each line_item_updated event,
+// so the running total follows corrections made through the API
+func (s *Service) forwardLineItemUpdate(ctx context.Context, event events.BillEvent) error {
+	if event.Type != events.EventLineItemUpdated {
+		return nil
+	}
+	for _, item := range event.Bill.LineItems {
+		if item.ID == event.LineItemID {
+			// Bills without a running workflow, e.g. drafts, have nothing to correct
+			_ = s.signalUpdateItem(ctx, event.BillID, item.ID, float64(item.NetAmount())/100, string(event.Bill.Currency))
+		}
+	}
+	return nil
+}
+
+// signalCloseBill signals the workflow to close the bill, recording why. Force
+// closes it even while its period-end close is pending.
+func (s *Service) signalCloseBill(ctx context.Context, billID, reason string, force bool) error {
+	workflowID := "billing-period-" + billID
+
+	return s.client.SignalWorkflow(ctx, workflowID, "", "close-bill", workflow.CloseBillSignal{Reason: reason, Force: force})
+}
+
+// queryBillState asks a bill's billing period workflow for its running state
+func (s *Service) queryBillState(ctx context.Context, billID string) (model.WorkflowBillState, error) {
 	workflowID := "billing-period-" + billID
 
-	return s.client.SignalWorkflow(ctx, workflowID, "", "close-bill", nil)
+	value, err := s.client.QueryWorkflow(ctx, workflowID, "", workflow.BillStateQuery)
+	if err != nil {
+		return model.WorkflowBillState{}, err
+	}
+	var state workflow.BillState
+	if err := value.Get(&state); err != nil {
+		return model.WorkflowBillState{}, err
+	}
+	return model.WorkflowBillState{
+		Status:        state.Status,
+		TotalAmount:   int64(math.Round(state.TotalAmount * 100)),
+		LineItemCount: state.LineItemCount,
+	}, nil
+}
+
+// startRecurringWorkflow starts the renewal workflow for a recurring bill template
+func (s *Service) startRecurringWorkflow(ctx context.Context, templateID, orgID string, intervalDays int) error {
+	input := workflow.RecurringBillInput{
+		TemplateID:   templateID,
+		OrgID:        orgID,
+		IntervalDays: intervalDays,
+	}
+
+	workflowID := "recurring-bill-" + templateID
+
+	_, err := s.client.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:        workflowID,
+		TaskQueue: taskQueueName,
+	}, workflow.RecurringBillWorkflow, input)
+
+	return err
+}
+
+// signalCancelRecurring signals the renewal workflow that its template was cancelled
+func (s *Service) signalCancelRecurring(ctx context.Context, templateID string) error {
+	workflowID := "recurring-bill-" + templateID
+
+	return s.client.SignalWorkflow(ctx, workflowID, "", "cancel-template", nil)
+}
+
+// createBillingSchedule creates the Temporal schedule that runs the scheduled billing
+// workflow for a billing schedule at midnight UTC on the 1st of every month. Each
+// run returns once the month's bill is open; the previous month's bill closes
+// through its own billing period workflow.
+func (s *Service) createBillingSchedule(ctx context.Context, schedule *model.BillingSchedule) error {
+	_, err := s.client.ScheduleClient().Create(ctx, client.ScheduleOptions{
+		ID: "billing-schedule-" + schedule.ID,
+		Spec: client.ScheduleSpec{
+			Calendars: []client.ScheduleCalendarSpec{{
+				DayOfMonth: []client.ScheduleRange{{Start: 1}},
+			}},
+		},
+		Action: &client.ScheduleWorkflowAction{
+			ID:        "scheduled-billing-" + schedule.ID,
+			Workflow:  workflow.ScheduledBillingWorkflow,
+			Args:      []interface{}{workflow.ScheduledBillingInput{ScheduleID: schedule.ID, OrgID: schedule.OrgID}},
+			TaskQueue: taskQueueName,
+		},
+	})
+	return err
+}
+
+// pauseBillingSchedule pauses or unpauses a billing schedule's Temporal schedule
+func (s *Service) pauseBillingSchedule(ctx context.Context, scheduleID string, paused bool) error {
+	handle := s.client.ScheduleClient().GetHandle(ctx, "billing-schedule-"+scheduleID)
+	if paused {
+		return handle.Pause(ctx, client.SchedulePauseOptions{})
+	}
+	return handle.Unpause(ctx, client.ScheduleUnpauseOptions{})
+}
+
+// deleteBillingSchedule deletes a billing schedule's Temporal schedule
+func (s *Service) deleteBillingSchedule(ctx context.Context, scheduleID string) error {
+	return s.client.ScheduleClient().GetHandle(ctx, "billing-schedule-"+scheduleID).Delete(ctx)
 }
@@ -0,0 +1,34 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+
+	"fees-api/internal/handlers"
+	"fees-api/internal/model"
+)
+
+//encore:api public raw method=POST path=/bill-templates
+func CreateBillTemplate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CreateBillTemplateRequest) (*model.BillTemplateResponse, error) {
+		template, err := svc.svc.CreateBillTemplate(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BillTemplateResponse{Template: *template}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=GET path=/bill-templates/:templateID
+func GetBillTemplate(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	templateID := handlers.PathParams(req.URL.Path, "/bill-templates/:templateID")["templateID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.BillTemplateResponse, error) {
+		template, err := svc.svc.GetBillTemplate(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BillTemplateResponse{Template: *template}, nil
+	})).ServeHTTP(w, req)
+}
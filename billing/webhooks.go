@@ -0,0 +1,42 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+
+	"fees-api/internal/handlers"
+	"fees-api/internal/model"
+)
+
+//encore:api public raw method=POST path=/webhooks
+func RegisterWebhook(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.RegisterWebhookRequest) (*model.RegisterWebhookResponse, error) {
+		endpoint, err := svc.webhooks.Register(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &model.RegisterWebhookResponse{Webhook: *endpoint}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=GET path=/webhooks
+func ListWebhooks(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*model.ListWebhooksResponse, error) {
+		endpoints, err := svc.webhooks.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &model.ListWebhooksResponse{Webhooks: endpoints}, nil
+	})).ServeHTTP(w, req)
+}
+
+//encore:api public raw method=DELETE path=/webhooks/:webhookID
+func DeleteWebhook(w http.ResponseWriter, req *http.Request) {
+	svc := GetService()
+	webhookID := handlers.PathParams(req.URL.Path, "/webhooks/:webhookID")["webhookID"]
+	svc.auth.RequireOrg(handlers.Typed(func(ctx context.Context, _ *struct{}) (*struct{}, error) {
+		return nil, svc.webhooks.Delete(ctx, webhookID)
+	})).ServeHTTP(w, req)
+}
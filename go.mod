@@ -2,7 +2,10 @@ module fees-api
 
 go 1.24.0
 
-require go.temporal.io/sdk v1.40.0
+require (
+	github.com/stretchr/testify v1.11.1
+	go.temporal.io/sdk v1.40.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -17,7 +20,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/robfig/cron v1.2.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
-	github.com/stretchr/testify v1.11.1 // indirect
 	go.temporal.io/api v1.62.1 // indirect
 	golang.org/x/net v0.39.0 // indirect
 	golang.org/x/sync v0.17.0 // indirect
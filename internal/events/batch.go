@@ -0,0 +1,128 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"fees-api/internal/lifecycle"
+	"fees-api/internal/logging"
+)
+
+// BillEventsBatch carries several bill events as a single message
+type BillEventsBatch struct {
+	Events []BillEvent `json:"events"`
+}
+
+// LineItemIDs returns the line item IDs the batch's events refer to, in order
+func (b BillEventsBatch) LineItemIDs() []string {
+	var ids []string
+	for _, event := range b.Events {
+		if event.LineItemID != "" {
+			ids = append(ids, event.LineItemID)
+		}
+	}
+	return ids
+}
+
+// BatchHandler processes a batch of bill events
+type BatchHandler func(ctx context.Context, batch BillEventsBatch) error
+
+// BatchPublisher publishes a batch of bill events as a single message
+type BatchPublisher interface {
+	PublishBatch(ctx context.Context, batch BillEventsBatch) error
+}
+
+// ErrPublisherClosed is returned when publishing to a buffered publisher that has
+// been closed
+var ErrPublisherClosed = errors.New("buffered publisher is closed")
+
+// BufferedPublisher is a Publisher that coalesces the events published within a
+// window into one batch for the downstream publisher, so bulk operations send one
+// message instead of one per change. The window starts with the first buffered
+// event; Flush sends the pending batch early and Close flushes it before returning.
+type BufferedPublisher struct {
+	mu       sync.Mutex
+	next     BatchPublisher
+	window   time.Duration
+	pending  []BillEvent
+	timer    *time.Timer
+	closed   bool
+	logger   logging.Logger
+	inFlight sync.WaitGroup // window flushes scheduled or still publishing
+}
+
+// NewBufferedPublisher creates a publisher batching events over window before
+// handing them to next. A window of zero or less publishes every event on its own.
+func NewBufferedPublisher(next BatchPublisher, window time.Duration) *BufferedPublisher {
+	return &BufferedPublisher{next: next, window: window, logger: slog.Default()}
+}
+
+// Publish buffers the event until the window ends or the publisher is flushed
+func (p *BufferedPublisher) Publish(ctx context.Context, event BillEvent) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPublisherClosed
+	}
+	p.pending = append(p.pending, event)
+	if p.window <= 0 {
+		p.mu.Unlock()
+		return p.Flush(ctx)
+	}
+	if p.timer == nil {
+		p.inFlight.Add(1)
+		p.timer = time.AfterFunc(p.window, p.flushWindow)
+	}
+	p.mu.Unlock()
+	return nil
+}
+
+// Flush publishes the pending events as one batch, if there are any
+func (p *BufferedPublisher) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	batch := BillEventsBatch{Events: p.pending}
+	p.pending = nil
+	if p.timer != nil && p.timer.Stop() {
+		p.inFlight.Done()
+	}
+	p.timer = nil
+	p.mu.Unlock()
+
+	if len(batch.Events) == 0 {
+		return nil
+	}
+	return p.next.PublishBatch(ctx, batch)
+}
+
+// Close stops the publisher accepting events, flushes the pending ones and waits,
+// up to ctx's deadline, for window flushes already under way
+func (p *BufferedPublisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	err := p.Flush(ctx)
+	return errors.Join(err, lifecycle.Wait(ctx, &p.inFlight))
+}
+
+// flushWindow flushes the batch whose window just ended. Nobody waits on it, so
+// failures are only logged.
+func (p *BufferedPublisher) flushWindow() {
+	defer p.inFlight.Done()
+
+	p.mu.Lock()
+	batch := BillEventsBatch{Events: p.pending}
+	p.pending = nil
+	p.timer = nil
+	p.mu.Unlock()
+
+	if len(batch.Events) == 0 {
+		return
+	}
+	if err := p.next.PublishBatch(context.Background(), batch); err != nil {
+		p.logger.Error("publishing bill event batch failed", "events", len(batch.Events), "error", err)
+	}
+}
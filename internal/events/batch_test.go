@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+)
+
+// recordingBatches is a BatchPublisher that keeps every batch it receives
+type recordingBatches struct {
+	mu      sync.Mutex
+	batches []BillEventsBatch
+}
+
+func (r *recordingBatches) PublishBatch(ctx context.Context, batch BillEventsBatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+func (r *recordingBatches) published() []BillEventsBatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]BillEventsBatch(nil), r.batches...)
+}
+
+func lineItemEvent(lineItemID string) BillEvent {
+	event := NewBillEvent(EventLineItemAdded, &model.Bill{ID: "bill_1"})
+	event.LineItemID = lineItemID
+	return event
+}
+
+func TestBufferedPublisherCoalescesWithinWindow(t *testing.T) {
+	next := &recordingBatches{}
+	publisher := NewBufferedPublisher(next, 20*time.Millisecond)
+
+	for _, id := range []string{"li_1", "li_2", "li_3"} {
+		if err := publisher.Publish(context.Background(), lineItemEvent(id)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if got := next.published(); len(got) != 0 {
+		t.Fatalf("expected nothing published before the window ends, got %d batches", len(got))
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(next.published()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	got := next.published()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 batch, got %d", len(got))
+	}
+	if ids := got[0].LineItemIDs(); len(ids) != 3 || ids[0] != "li_1" || ids[2] != "li_3" {
+		t.Errorf("expected the batch to carry li_1..li_3 in order, got %v", ids)
+	}
+}
+
+func TestBufferedPublisherFlushesOnClose(t *testing.T) {
+	next := &recordingBatches{}
+	publisher := NewBufferedPublisher(next, time.Hour)
+
+	publisher.Publish(context.Background(), lineItemEvent("li_1"))
+	publisher.Publish(context.Background(), lineItemEvent("li_2"))
+
+	if err := publisher.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := next.published(); len(got) != 1 || len(got[0].Events) != 2 {
+		t.Fatalf("expected one batch of 2 events on close, got %+v", got)
+	}
+	if err := publisher.Publish(context.Background(), lineItemEvent("li_3")); !errors.Is(err, ErrPublisherClosed) {
+		t.Errorf("expected ErrPublisherClosed after close, got %v", err)
+	}
+}
+
+func TestTopicPublishBatch(t *testing.T) {
+	topic := NewTopic()
+	var batches []BillEventsBatch
+	topic.SubscribeBatch(func(ctx context.Context, batch BillEventsBatch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	var single []BillEvent
+	topic.Subscribe(func(ctx context.Context, event BillEvent) error {
+		single = append(single, event)
+		return nil
+	})
+
+	batch := BillEventsBatch{Events: []BillEvent{lineItemEvent("li_1"), lineItemEvent("li_2")}}
+	if err := topic.PublishBatch(context.Background(), batch); err != nil {
+		t.Fatalf("PublishBatch() error = %v", err)
+	}
+	topic.Publish(context.Background(), lineItemEvent("li_3"))
+
+	if len(batches) != 2 || len(batches[0].Events) != 2 || len(batches[1].Events) != 1 {
+		t.Errorf("expected batch subscribers to get the batch whole and the single event as a batch of one, got %+v", batches)
+	}
+	if len(single) != 3 {
+		t.Errorf("expected event subscribers to get all 3 events, got %d", len(single))
+	}
+}
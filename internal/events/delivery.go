@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DeliveryGuarantee selects how hard a subscription tries to deliver an event
+type DeliveryGuarantee string
+
+const (
+	// DeliveryAtLeastOnce retries failed deliveries and can dead-letter the events
+	// still failing once the retries run out
+	DeliveryAtLeastOnce DeliveryGuarantee = "at_least_once"
+	// DeliveryBestEffort delivers each event once; failures are logged and dropped,
+	// so a slow or broken subscriber never holds up the publisher
+	DeliveryBestEffort DeliveryGuarantee = "best_effort"
+)
+
+// DeliveryConfig configures a subscription's delivery, trading reliability for
+// publish latency
+type DeliveryConfig struct {
+	Guarantee   DeliveryGuarantee
+	AckDeadline time.Duration // how long one delivery attempt may take; zero means no limit
+	Retry       RetryPolicy   // at-least-once only
+	DeadLetter  bool          // keep events whose retries ran out; at-least-once only
+}
+
+// DefaultDeliveryConfig returns the at-least-once delivery used for bill event subscribers
+func DefaultDeliveryConfig() DeliveryConfig {
+	return DeliveryConfig{
+		Guarantee:   DeliveryAtLeastOnce,
+		AckDeadline: 30 * time.Second,
+		Retry:       DefaultRetryPolicy(),
+		DeadLetter:  true,
+	}
+}
+
+// Validate checks the configuration is consistent
+func (c DeliveryConfig) Validate() error {
+	if c.AckDeadline < 0 {
+		return fmt.Errorf("ack deadline must not be negative, got %s", c.AckDeadline)
+	}
+	switch c.Guarantee {
+	case DeliveryAtLeastOnce:
+		if c.Retry.MaxAttempts < 1 {
+			return fmt.Errorf("at-least-once delivery needs at least 1 attempt, got %d", c.Retry.MaxAttempts)
+		}
+		if c.Retry.InitialBackoff < 0 || c.Retry.MaxBackoff < 0 {
+			return fmt.Errorf("retry backoff must not be negative")
+		}
+	case DeliveryBestEffort:
+		if c.DeadLetter {
+			return fmt.Errorf("dead letters need at-least-once delivery")
+		}
+	default:
+		return fmt.Errorf("unknown delivery guarantee %q, want %q or %q", c.Guarantee, DeliveryAtLeastOnce, DeliveryBestEffort)
+	}
+	return nil
+}
+
+// SubscribeWithDelivery registers a handler delivered to as config says. It fails,
+// registering nothing, when the config is invalid.
+func (t *Topic) SubscribeWithDelivery(handler Handler, config DeliveryConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	if config.AckDeadline > 0 {
+		handler = withAckDeadline(handler, config.AckDeadline)
+	}
+	if config.Guarantee == DeliveryBestEffort {
+		t.Subscribe(func(ctx context.Context, event BillEvent) error {
+			if err := handler(ctx, event); err != nil {
+				t.logger.Warn("dropping bill event after a best-effort delivery failed",
+					"event_type", event.Type, "bill_id", event.BillID, "error", err)
+			}
+			return nil
+		})
+		return nil
+	}
+
+	t.Subscribe(func(ctx context.Context, event BillEvent) error {
+		attempts, err := deliverWithRetry(ctx, handler, event, config.Retry)
+		if err == nil {
+			return nil
+		}
+		if config.DeadLetter {
+			t.deadLetter(event, attempts, err)
+		} else {
+			t.logger.Error("dropping bill event after its retries ran out",
+				"event_type", event.Type, "bill_id", event.BillID, "attempts", attempts, "error", err)
+		}
+		return err
+	})
+	return nil
+}
+
+// withAckDeadline bounds each call to handler by deadline
+func withAckDeadline(handler Handler, deadline time.Duration) Handler {
+	return func(ctx context.Context, event BillEvent) error {
+		ctx, cancel := context.WithTimeout(ctx, deadline)
+		defer cancel()
+		return handler(ctx, event)
+	}
+}
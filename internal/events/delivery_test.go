@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+)
+
+func TestSubscribeWithDeliveryUsesConfiguredPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         DeliveryConfig
+		wantAttempts   int
+		wantErr        bool
+		wantDeadLetter bool
+	}{
+		{
+			name:           "at least once with dead letters",
+			config:         DeliveryConfig{Guarantee: DeliveryAtLeastOnce, Retry: testRetryPolicy(3), DeadLetter: true},
+			wantAttempts:   3,
+			wantErr:        true,
+			wantDeadLetter: true,
+		},
+		{
+			name:         "at least once without dead letters",
+			config:       DeliveryConfig{Guarantee: DeliveryAtLeastOnce, Retry: testRetryPolicy(2)},
+			wantAttempts: 2,
+			wantErr:      true,
+		},
+		{
+			name:         "best effort",
+			config:       DeliveryConfig{Guarantee: DeliveryBestEffort, Retry: testRetryPolicy(5)},
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topic := NewTopic()
+			calls := 0
+			err := topic.SubscribeWithDelivery(func(ctx context.Context, event BillEvent) error {
+				calls++
+				return errors.New("subscriber unavailable")
+			}, tt.config)
+			if err != nil {
+				t.Fatalf("SubscribeWithDelivery() error = %v", err)
+			}
+
+			err = topic.Publish(context.Background(), NewBillEvent(EventBillClosed, &model.Bill{ID: "bill_1"}))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, calls)
+			}
+			if dead := topic.DeadLetters(); (len(dead) == 1) != tt.wantDeadLetter {
+				t.Errorf("expected dead letter %v, got %d", tt.wantDeadLetter, len(dead))
+			}
+		})
+	}
+}
+
+func TestSubscribeWithDeliveryAckDeadline(t *testing.T) {
+	topic := NewTopic()
+	config := DeliveryConfig{Guarantee: DeliveryBestEffort, AckDeadline: time.Minute}
+
+	var deadline time.Time
+	var hasDeadline bool
+	if err := topic.SubscribeWithDelivery(func(ctx context.Context, event BillEvent) error {
+		deadline, hasDeadline = ctx.Deadline()
+		return nil
+	}, config); err != nil {
+		t.Fatalf("SubscribeWithDelivery() error = %v", err)
+	}
+
+	topic.Publish(context.Background(), NewBillEvent(EventBillCreated, &model.Bill{ID: "bill_1"}))
+	if !hasDeadline || deadline.After(time.Now().Add(time.Minute)) {
+		t.Errorf("expected the handler to run within a 1m deadline, got %v (set %v)", deadline, hasDeadline)
+	}
+}
+
+func TestDeliveryConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  DeliveryConfig
+		wantErr bool
+	}{
+		{name: "default", config: DefaultDeliveryConfig()},
+		{name: "best effort", config: DeliveryConfig{Guarantee: DeliveryBestEffort}},
+		{name: "unknown guarantee", config: DeliveryConfig{Guarantee: "exactly_once"}, wantErr: true},
+		{name: "no attempts", config: DeliveryConfig{Guarantee: DeliveryAtLeastOnce}, wantErr: true},
+		{name: "best effort dead letters", config: DeliveryConfig{Guarantee: DeliveryBestEffort, DeadLetter: true}, wantErr: true},
+		{name: "negative ack deadline", config: DeliveryConfig{Guarantee: DeliveryBestEffort, AckDeadline: -time.Second}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	topic := NewTopic()
+	if err := topic.SubscribeWithDelivery(func(ctx context.Context, event BillEvent) error { return nil }, DeliveryConfig{}); err == nil {
+		t.Error("expected an invalid config to be refused")
+	}
+}
@@ -0,0 +1,189 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"fees-api/internal/lifecycle"
+	"fees-api/internal/logging"
+	"fees-api/internal/model"
+)
+
+// EventType represents the kind of change that happened to a bill
+type EventType string
+
+const (
+	EventBillCreated       EventType = "created"
+	EventBillActivated     EventType = "activated"
+	EventLineItemAdded     EventType = "line_item_added"
+	EventLineItemsReplaced EventType = "line_items_replaced"
+	EventLineItemUpdated   EventType = "line_item_updated"
+	EventCurrencyChanged   EventType = "currency_changed"
+	EventBillApproved      EventType = "approved" // approved to close above the auto-close threshold
+	EventBillClosed        EventType = "closed"
+	EventBillReopened      EventType = "reopened"
+	EventBillDeleted       EventType = "deleted"
+	EventBillRestored      EventType = "restored"
+	EventBillImported      EventType = "imported"    // a historical bill, added already closed
+	EventBillSplit         EventType = "split"       // line items moved to a new bill; both bills get one
+	EventBillsMerged       EventType = "merged"      // bills combined into one; the target and each source get one
+	EventBillAutoVoided    EventType = "auto_voided" // an empty bill left past its TTL, soft-deleted by the sweeper
+	EventPaymentRecorded   EventType = "payment_recorded"
+)
+
+// KnownEventTypes lists every event type emitted by the billing service
+var KnownEventTypes = []EventType{
+	EventBillCreated,
+	EventBillActivated,
+	EventLineItemAdded,
+	EventLineItemsReplaced,
+	EventLineItemUpdated,
+	EventCurrencyChanged,
+	EventBillApproved,
+	EventBillClosed,
+	EventBillReopened,
+	EventBillDeleted,
+	EventBillRestored,
+	EventBillImported,
+	EventBillSplit,
+	EventBillsMerged,
+	EventBillAutoVoided,
+	EventPaymentRecorded,
+}
+
+// IsKnown reports whether the event type is emitted by the billing service
+func (t EventType) IsKnown() bool {
+	for _, known := range KnownEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// BillEvent is published whenever a bill changes
+type BillEvent struct {
+	Type           EventType  `json:"type"`
+	BillID         string     `json:"billId"`
+	LineItemID     string     `json:"lineItemId,omitempty"`
+	RelatedBillIDs []string   `json:"relatedBillIds,omitempty"` // other bills the change spanned, e.g. the other side of a split
+	Bill           model.Bill `json:"bill"`
+	OccurredAt     time.Time  `json:"occurredAt"`
+}
+
+// NewBillEvent creates an event carrying a snapshot of the bill
+func NewBillEvent(eventType EventType, bill *model.Bill) BillEvent {
+	return BillEvent{
+		Type:       eventType,
+		BillID:     bill.ID,
+		Bill:       *bill,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+// Handler processes a bill event
+type Handler func(ctx context.Context, event BillEvent) error
+
+// Publisher publishes bill events
+type Publisher interface {
+	Publish(ctx context.Context, event BillEvent) error
+}
+
+// NopPublisher discards every event
+type NopPublisher struct{}
+
+// Publish discards the event
+func (NopPublisher) Publish(ctx context.Context, event BillEvent) error {
+	return nil
+}
+
+// ErrTopicClosed is returned when publishing to a topic that has been closed
+var ErrTopicClosed = errors.New("event topic is closed")
+
+// Topic is an in-process publisher that fans events out to its subscribers
+type Topic struct {
+	mu          sync.RWMutex
+	handlers    []Handler
+	batches     []BatchHandler
+	deadLetters []DeadLetter
+	logger      logging.Logger
+	closed      bool
+	inFlight    sync.WaitGroup // Publish calls still delivering to handlers
+}
+
+// NewTopic creates a new topic with no subscribers, logging through slog's default logger
+func NewTopic() *Topic {
+	return &Topic{logger: slog.Default()}
+}
+
+// SetLogger sets the logger used to report dead-lettered events
+func (t *Topic) SetLogger(logger logging.Logger) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.logger = logger
+}
+
+// Subscribe registers a handler that receives every published event
+func (t *Topic) Subscribe(handler Handler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers = append(t.handlers, handler)
+}
+
+// SubscribeBatch registers a handler that receives published events as batches:
+// each PublishBatch call as one message, and each Publish call as a batch of one
+func (t *Topic) SubscribeBatch(handler BatchHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.batches = append(t.batches, handler)
+}
+
+// Publish delivers the event to every subscriber, returning the first error
+func (t *Topic) Publish(ctx context.Context, event BillEvent) error {
+	return t.PublishBatch(ctx, BillEventsBatch{Events: []BillEvent{event}})
+}
+
+// PublishBatch delivers the batch to every batch subscriber as a single message
+// and each of its events, in order, to every event subscriber, returning the
+// first error
+func (t *Topic) PublishBatch(ctx context.Context, batch BillEventsBatch) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return ErrTopicClosed
+	}
+	t.inFlight.Add(1)
+	handlers := make([]Handler, len(t.handlers))
+	copy(handlers, t.handlers)
+	batches := make([]BatchHandler, len(t.batches))
+	copy(batches, t.batches)
+	t.mu.Unlock()
+	defer t.inFlight.Done()
+
+	var firstErr error
+	for _, handler := range batches {
+		if err := handler(ctx, batch); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, event := range batch.Events {
+		for _, handler := range handlers {
+			if err := handler(ctx, event); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// Close stops the topic accepting events and waits, up to ctx's deadline, for
+// in-flight deliveries (including their retries) to finish
+func (t *Topic) Close(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+	return lifecycle.Wait(ctx, &t.inFlight)
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryPolicy controls how a subscription redelivers events its handler fails on
+type RetryPolicy struct {
+	MaxAttempts    int           // total deliveries, including the first
+	InitialBackoff time.Duration // doubled after each failed attempt
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used for bill event subscribers
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// DeadLetter records an event a subscriber permanently failed to handle
+type DeadLetter struct {
+	Event    BillEvent `json:"event"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// permanentError marks a handler error that retrying won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps a handler error so the event is dead-lettered without retrying
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// SubscribeWithRetry registers a handler whose failures are retried with
+// exponential backoff. Events still failing after policy.MaxAttempts, or failing
+// with a Permanent error, are logged and kept as dead letters.
+func (t *Topic) SubscribeWithRetry(handler Handler, policy RetryPolicy) {
+	t.Subscribe(func(ctx context.Context, event BillEvent) error {
+		attempts, err := deliverWithRetry(ctx, handler, event, policy)
+		if err != nil {
+			t.deadLetter(event, attempts, err)
+		}
+		return err
+	})
+}
+
+// DeadLetters returns the events retrying subscribers permanently failed to handle
+func (t *Topic) DeadLetters() []DeadLetter {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	result := make([]DeadLetter, len(t.deadLetters))
+	copy(result, t.deadLetters)
+	return result
+}
+
+func (t *Topic) deadLetter(event BillEvent, attempts int, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.logger.Error("dead-lettering bill event",
+		"event_type", event.Type, "bill_id", event.BillID, "attempts", attempts, "error", err)
+	t.deadLetters = append(t.deadLetters, DeadLetter{
+		Event:    event,
+		Attempts: attempts,
+		Error:    err.Error(),
+		FailedAt: time.Now().UTC(),
+	})
+}
+
+// deliverWithRetry runs the handler until it succeeds or the policy is exhausted,
+// returning the number of attempts made and the last error
+func deliverWithRetry(ctx context.Context, handler Handler, event BillEvent, policy RetryPolicy) (int, error) {
+	backoff := policy.InitialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = handler(ctx, event); err == nil {
+			return attempt, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= policy.MaxAttempts {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+)
+
+func testRetryPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{MaxAttempts: maxAttempts, InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}
+}
+
+func TestSubscribeWithRetryEventuallyDelivers(t *testing.T) {
+	topic := NewTopic()
+
+	calls := 0
+	var delivered []BillEvent
+	topic.SubscribeWithRetry(func(ctx context.Context, event BillEvent) error {
+		calls++
+		if calls <= 2 {
+			return errors.New("temporal unavailable")
+		}
+		delivered = append(delivered, event)
+		return nil
+	}, testRetryPolicy(5))
+
+	event := NewBillEvent(EventBillCreated, &model.Bill{ID: "bill_1"})
+	if err := topic.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	if len(delivered) != 1 || delivered[0].BillID != "bill_1" {
+		t.Errorf("expected the event to be delivered once, got %+v", delivered)
+	}
+	if dead := topic.DeadLetters(); len(dead) != 0 {
+		t.Errorf("expected no dead letters, got %d", len(dead))
+	}
+}
+
+func TestSubscribeWithRetryDeadLetters(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantAttempts int
+	}{
+		{name: "exhausts retries", err: errors.New("temporal unavailable"), wantAttempts: 3},
+		{name: "permanent error skips retries", err: Permanent(errors.New("malformed event")), wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topic := NewTopic()
+
+			calls := 0
+			topic.SubscribeWithRetry(func(ctx context.Context, event BillEvent) error {
+				calls++
+				return tt.err
+			}, testRetryPolicy(3))
+
+			err := topic.Publish(context.Background(), NewBillEvent(EventBillClosed, &model.Bill{ID: "bill_1"}))
+			if err == nil {
+				t.Fatal("expected Publish() to return the handler error")
+			}
+			if calls != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, calls)
+			}
+
+			dead := topic.DeadLetters()
+			if len(dead) != 1 {
+				t.Fatalf("expected 1 dead letter, got %d", len(dead))
+			}
+			if dead[0].Event.Type != EventBillClosed || dead[0].Attempts != tt.wantAttempts {
+				t.Errorf("unexpected dead letter %+v", dead[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/presentation"
+	"fees-api/internal/tenant"
+	"fees-api/internal/validation"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// Admin endpoints are raw so Authenticator.RequireAdmin can attribute each call to
+// an operator before it reaches these handlers.
+
+// ServeSetExchangeRate is the raw HTTP form of SetExchangeRate
+func (h *BillingHandler) ServeSetExchangeRate(w http.ResponseWriter, r *http.Request, currency model.Currency) {
+	var req model.SetExchangeRateRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	info, err := h.svc.SetExchangeRate(r.Context(), currency, req.RateToUSD)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, presentation.NewCurrencyView(info))
+}
+
+// ServeImportBill is the raw HTTP form of ImportBill
+func (h *BillingHandler) ServeImportBill(w http.ResponseWriter, r *http.Request) {
+	var req model.ImportBillRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	bill, err := h.svc.ImportBill(r.Context(), &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, presentation.CreateBillResponse{Bill: presentation.NewBillView(bill)})
+}
+
+// ServeImportLineItemsCSV is the raw HTTP form of ImportLineItemsCSV. The body is the
+// CSV itself; the orgId query parameter names the org whose bills it charges.
+func (h *BillingHandler) ServeImportLineItemsCSV(w http.ResponseWriter, r *http.Request) {
+	orgID, err := queryOrgID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	report, err := h.svc.ImportLineItemsCSV(tenant.WithOrgID(r.Context(), orgID), r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// RequireQueryOrg wraps an admin handler that acts on one org's bills, putting the
+// org named by the orgId query parameter on the request context. Admin keys belong
+// to no org, so the operator names it.
+func RequireQueryOrg(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgID, err := queryOrgID(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.WithOrgID(r.Context(), orgID)))
+	})
+}
+
+// queryOrgID returns the required orgId query parameter
+func queryOrgID(r *http.Request) (string, error) {
+	orgID := r.URL.Query().Get("orgId")
+	if orgID == "" {
+		return "", billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "orgId", Description: "is required"}})
+	}
+	return orgID, nil
+}
+
+// ServeOverdueSummary is the raw HTTP form of OverdueSummary. The optional asOf
+// query parameter, an RFC 3339 time, defaults to now.
+func (h *BillingHandler) ServeOverdueSummary(w http.ResponseWriter, r *http.Request) {
+	asOf := h.svc.Now()
+	if raw := r.URL.Query().Get("asOf"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeError(w, billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "asOf", Description: "must be an RFC 3339 time"}}))
+			return
+		}
+		asOf = parsed
+	}
+
+	summary, err := h.svc.OverdueSummary(r.Context(), asOf)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, presentation.NewOverdueSummaryResponse(asOf, summary))
+}
+
+// decodeBody reads a raw request's JSON body into dst and validates it. Bodies over
+// model.MaxRequestBodyBytes are rejected before they're decoded.
+func decodeBody(r *http.Request, dst any) error {
+	body, err := readBody(r, model.MaxRequestBodyBytes)
+	if err != nil {
+		return err
+	}
+	return validation.DecodeJSON(body, dst)
+}
+
+// readBody reads a raw request's body, failing with an invalid_argument error once
+// it passes limit bytes
+func readBody(r *http.Request, limit int64) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, limit))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "body", Description: fmt.Sprintf("must be at most %d bytes", limit)}})
+	}
+	if err != nil {
+		return nil, billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "body", Description: err.Error()}})
+	}
+	return body, nil
+}
+
+// writeJSON writes v as a JSON response body with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fees-api/internal/model"
+	"fees-api/internal/presentation"
+	"fees-api/internal/repository"
+	"fees-api/internal/service"
+	"fees-api/internal/tenant"
+)
+
+func TestDecodeBodyRejectsOversizedBodies(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+
+	body := `{"rateToUSD": 0.5, "padding": "` + strings.Repeat("x", model.MaxRequestBodyBytes) + `"}`
+	req := httptest.NewRequest(http.MethodPut, "/admin/rates/GEL", strings.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.ServeSetExchangeRate(rec, req, model.CurrencyGEL)
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"field":"body"`) {
+		t.Errorf("expected 400 with a body violation, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRequireQueryOrgScopesAdminCalls(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	bill, _ := svc.CreateBill(tenant.WithOrgID(context.Background(), "org_a"), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+	auth := NewAuthenticator(ParseAPIKeys("key_a:org_a"), ParseAPIKeys("admin_key:alice"), "")
+	handler := auth.RequireAdmin(RequireQueryOrg(Typed(func(ctx context.Context, _ *struct{}) (*presentation.RecalculateTotalResponse, error) {
+		return h.RecalculateTotal(ctx, bill.ID)
+	})))
+
+	recalculate := func(token, query string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/bills/"+bill.ID+"/recalculate"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := recalculate("admin_key", "?orgId=org_a"); code != http.StatusOK {
+		t.Errorf("expected 200 recalculating the org's bill, got %d", code)
+	}
+	if code := recalculate("admin_key", ""); code != http.StatusBadRequest {
+		t.Errorf("expected 400 without orgId, got %d", code)
+	}
+	if code := recalculate("admin_key", "?orgId=org_b"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for another org's bill, got %d", code)
+	}
+	if code := recalculate("key_a", "?orgId=org_a"); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with an org API key, got %d", code)
+	}
+}
+
+func TestRecalculateOpenBillsRequiresAdmin(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+
+	auth := NewAuthenticator(ParseAPIKeys("key_a:org_a"), ParseAPIKeys("admin_key:alice"), "")
+	handler := auth.RequireAdmin(Typed(func(ctx context.Context, _ *struct{}) (*presentation.RecalculateOpenBillsResponse, error) {
+		return h.RecalculateOpenBills(ctx, "GEL")
+	}))
+
+	recalculate := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/rates/GEL/recalculate", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := recalculate("admin_key"); code != http.StatusOK {
+		t.Errorf("expected 200 with an admin key, got %d", code)
+	}
+	if code := recalculate("key_a"); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 with an org API key, got %d", code)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fees-api/internal/tenant"
+)
+
+func TestAuthenticatorRequireOrg(t *testing.T) {
+	auth := NewAuthenticator(ParseAPIKeys("key_a:org_a, key_b:org_b"), nil, "internal")
+
+	tests := []struct {
+		name       string
+		token      string
+		orgHeader  string
+		wantStatus int
+		wantOrg    string
+	}{
+		{name: "API key", token: "key_a", wantStatus: http.StatusOK, wantOrg: "org_a"},
+		{name: "API key ignores org header", token: "key_b", orgHeader: "org_a", wantStatus: http.StatusOK, wantOrg: "org_b"},
+		{name: "internal token acts for named org", token: "internal", orgHeader: "org_a", wantStatus: http.StatusOK, wantOrg: "org_a"},
+		{name: "internal token without org", token: "internal", wantStatus: http.StatusUnauthorized},
+		{name: "unknown key", token: "nope", wantStatus: http.StatusUnauthorized},
+		{name: "missing token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOrg string
+			handler := auth.RequireOrg(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotOrg, _ = tenant.OrgIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/bills/bill_1", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.orgHeader != "" {
+				req.Header.Set(OrgHeader, tt.orgHeader)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if gotOrg != tt.wantOrg {
+				t.Errorf("expected org %q, got %q", tt.wantOrg, gotOrg)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorRequireAdmin(t *testing.T) {
+	auth := NewAuthenticator(ParseAPIKeys("key_a:org_a"), ParseAPIKeys("admin_key:alice"), "internal")
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{name: "admin key", token: "admin_key", wantStatus: http.StatusOK},
+		{name: "org API key", token: "key_a", wantStatus: http.StatusUnauthorized},
+		{name: "internal token", token: "internal", wantStatus: http.StatusUnauthorized},
+		{name: "missing token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotActor string
+			handler := auth.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotActor, _ = tenant.ActorFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/rates/GEL", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus == http.StatusOK && gotActor != "alice" {
+				t.Errorf("expected actor alice, got %q", gotActor)
+			}
+		})
+	}
+}
@@ -4,60 +4,395 @@ import (
 	"context"
 
 	"fees-api/internal/model"
+	"fees-api/internal/presentation"
 	"fees-api/internal/service"
 )
 
 // BillingHandler handles HTTP requests for billing
 type BillingHandler struct {
-	svc *service.BillingService
+	svc      *service.BillingService
+	limiter  *RateLimiter
+	eventLog *service.EventService
 }
 
-// NewBillingHandler creates a new billing handler
-func NewBillingHandler(svc *service.BillingService) *BillingHandler {
-	return &BillingHandler{svc: svc}
+// Option configures optional BillingHandler behaviour
+type Option func(*BillingHandler)
+
+// WithRateLimiter limits how often a caller may add line items
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(h *BillingHandler) {
+		h.limiter = limiter
+	}
+}
+
+// WithEventLog lets GetBill attach a bill's recent events when asked to
+func WithEventLog(eventLog *service.EventService) Option {
+	return func(h *BillingHandler) {
+		h.eventLog = eventLog
+	}
+}
+
+// NewBillingHandler creates a new billing handler. Handlers run each request's
+// Validate before calling the service, as Encore does for typed endpoints.
+func NewBillingHandler(svc *service.BillingService, opts ...Option) *BillingHandler {
+	h := &BillingHandler{svc: svc}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // CreateBill handles the CreateBill API
-func (h *BillingHandler) CreateBill(ctx context.Context, req *model.CreateBillRequest) (*model.CreateBillResponse, error) {
-	bill, err := h.svc.CreateBill(req)
+func (h *BillingHandler) CreateBill(ctx context.Context, req *model.CreateBillRequest) (*presentation.CreateBillResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	bill, err := h.svc.CreateBill(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.CreateBillResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// ActivateBill handles the ActivateBill API
+func (h *BillingHandler) ActivateBill(ctx context.Context, billID string, req *model.ActivateBillRequest) (*presentation.ActivateBillResponse, error) {
+	bill, err := h.svc.ActivateBill(ctx, billID, req)
 	if err != nil {
 		return nil, err
 	}
-	return &model.CreateBillResponse{Bill: *bill}, nil
+	return &presentation.ActivateBillResponse{Bill: presentation.NewBillView(bill)}, nil
 }
 
 // AddLineItem handles the AddLineItem API
-func (h *BillingHandler) AddLineItem(ctx context.Context, billID string, req *model.AddLineItemRequest) (*model.AddLineItemResponse, error) {
-	bill, err := h.svc.AddLineItem(billID, req)
+func (h *BillingHandler) AddLineItem(ctx context.Context, billID string, req *model.AddLineItemRequest) (*presentation.AddLineItemResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if h.limiter != nil {
+		if err := h.limiter.AllowCaller(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	bill, err := h.svc.AddLineItem(ctx, billID, req)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.AddLineItemResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// ReplaceLineItems handles the ReplaceLineItems API
+func (h *BillingHandler) ReplaceLineItems(ctx context.Context, billID string, req *model.ReplaceLineItemsRequest) (*presentation.ReplaceLineItemsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if h.limiter != nil {
+		if err := h.limiter.AllowCaller(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	bill, err := h.svc.ReplaceLineItems(ctx, billID, req.LineItems)
 	if err != nil {
 		return nil, err
 	}
-	return &model.AddLineItemResponse{Bill: *bill}, nil
+	return &presentation.ReplaceLineItemsResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// UpdateLineItem handles the UpdateLineItem API
+func (h *BillingHandler) UpdateLineItem(ctx context.Context, billID, lineItemID string, req *model.UpdateLineItemRequest) (*presentation.UpdateLineItemResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if h.limiter != nil {
+		if err := h.limiter.AllowCaller(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	bill, err := h.svc.UpdateLineItem(ctx, billID, lineItemID, req)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.UpdateLineItemResponse{Bill: presentation.NewBillView(bill)}, nil
 }
 
 // CloseBill handles the CloseBill API
-func (h *BillingHandler) CloseBill(ctx context.Context, billID string) (*model.CloseBillResponse, error) {
-	bill, err := h.svc.CloseBill(billID)
+func (h *BillingHandler) CloseBill(ctx context.Context, billID string, req *model.CloseBillRequest) (*presentation.CloseBillResponse, error) {
+	bill, err := h.svc.CloseBill(ctx, billID, req)
+	if err != nil {
+		return nil, err
+	}
+	return NewCloseBillResponse(ctx, h.svc, bill), nil
+}
+
+// NewCloseBillResponse builds the response to a close, with the receipt it issued
+// (or, for an idempotent repeat, issued earlier)
+func NewCloseBillResponse(ctx context.Context, svc *service.BillingService, bill *model.Bill) *presentation.CloseBillResponse {
+	resp := &presentation.CloseBillResponse{Bill: presentation.NewBillView(bill)}
+	if receipt, err := svc.GetReceipt(ctx, bill.ID); err == nil {
+		view := presentation.NewReceiptView(receipt)
+		resp.Receipt = &view
+	}
+	return resp
+}
+
+// GetReceipt handles the GetReceipt API
+func (h *BillingHandler) GetReceipt(ctx context.Context, billID string) (*presentation.GetReceiptResponse, error) {
+	receipt, err := h.svc.GetReceipt(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.GetReceiptResponse{Receipt: presentation.NewReceiptView(receipt)}, nil
+}
+
+// ListReceipts handles the ListReceipts API
+func (h *BillingHandler) ListReceipts(ctx context.Context, billID string) (*presentation.ListReceiptsResponse, error) {
+	receipts, err := h.svc.ListReceipts(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.ListReceiptsResponse{Receipts: presentation.NewReceiptViews(receipts)}, nil
+}
+
+// PreviewClose handles the PreviewClose API
+func (h *BillingHandler) PreviewClose(ctx context.Context, billID string, req *model.CloseBillRequest) (*presentation.PreviewCloseResponse, error) {
+	bill, err := h.svc.PreviewClose(ctx, billID, req)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.PreviewCloseResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// CloseBills handles the CloseBills API
+func (h *BillingHandler) CloseBills(ctx context.Context, req *model.CloseBillsRequest) (*presentation.CloseBillsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	results, err := h.svc.CloseBills(ctx, req.BillIDs, &model.CloseBillRequest{AllowEmpty: req.AllowEmpty, ApprovedBy: req.ApprovedBy})
+	if err != nil {
+		return nil, err
+	}
+	return presentation.NewCloseBillsResponse(results), nil
+}
+
+// UpdateNote handles the UpdateNote API
+func (h *BillingHandler) UpdateNote(ctx context.Context, billID string, req *model.UpdateNoteRequest) (*presentation.UpdateNoteResponse, error) {
+	bill, err := h.svc.UpdateNote(ctx, billID, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.UpdateNoteResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// RecordPayment handles the RecordPayment API
+func (h *BillingHandler) RecordPayment(ctx context.Context, billID string, req *model.RecordPaymentRequest) (*presentation.RecordPaymentResponse, error) {
+	bill, err := h.svc.RecordPayment(ctx, billID, req)
+	if err != nil {
+		return nil, err
+	}
+	view := presentation.NewBillView(bill)
+	view.SetPaymentStatus(bill, h.svc.Now())
+	return &presentation.RecordPaymentResponse{Bill: view}, nil
+}
+
+// ChangeCurrency handles the ChangeCurrency API
+func (h *BillingHandler) ChangeCurrency(ctx context.Context, billID string, req *model.ChangeCurrencyRequest) (*presentation.ChangeCurrencyResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	bill, err := h.svc.ChangeCurrency(ctx, billID, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.ChangeCurrencyResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// ApproveBill handles the ApproveBill API
+func (h *BillingHandler) ApproveBill(ctx context.Context, billID string, req *model.ApproveBillRequest) (*presentation.ApproveBillResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	bill, err := h.svc.ApproveBill(ctx, billID, req.Approver)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.ApproveBillResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// SplitBill handles the SplitBill API
+func (h *BillingHandler) SplitBill(ctx context.Context, billID string, req *model.SplitBillRequest) (*presentation.SplitBillResponse, error) {
+	source, split, err := h.svc.SplitBill(ctx, billID, req.LineItemIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.SplitBillResponse{Source: presentation.NewBillView(source), Bill: presentation.NewBillView(split)}, nil
+}
+
+// MergeBills handles the MergeBills API
+func (h *BillingHandler) MergeBills(ctx context.Context, billID string, req *model.MergeBillsRequest) (*presentation.MergeBillsResponse, error) {
+	bill, err := h.svc.MergeBills(ctx, billID, req.SourceBillIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.MergeBillsResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// ReopenBill handles the ReopenBill API
+func (h *BillingHandler) ReopenBill(ctx context.Context, billID string) (*presentation.ReopenBillResponse, error) {
+	bill, err := h.svc.ReopenBill(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.ReopenBillResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// RecalculateTotal handles the admin RecalculateTotal API
+func (h *BillingHandler) RecalculateTotal(ctx context.Context, billID string) (*presentation.RecalculateTotalResponse, error) {
+	bill, oldTotal, err := h.svc.RecalculateTotal(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.RecalculateTotalResponse{Bill: presentation.NewBillView(bill), OldTotal: oldTotal, NewTotal: bill.TotalAmount}, nil
+}
+
+// RecalculateOpenBills handles the admin RecalculateOpenBills API
+func (h *BillingHandler) RecalculateOpenBills(ctx context.Context, currency string) (*presentation.RecalculateOpenBillsResponse, error) {
+	updated, err := h.svc.RecalculateOpenBills(ctx, model.Currency(currency).Normalize())
 	if err != nil {
 		return nil, err
 	}
-	return &model.CloseBillResponse{Bill: *bill}, nil
+	return &presentation.RecalculateOpenBillsResponse{Currency: currency, Updated: updated}, nil
+}
+
+// DeleteBill handles the DeleteBill API
+func (h *BillingHandler) DeleteBill(ctx context.Context, billID string) (*presentation.DeleteBillResponse, error) {
+	bill, err := h.svc.SoftDeleteBill(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.DeleteBillResponse{Bill: presentation.NewBillView(bill)}, nil
+}
+
+// RestoreBill handles the RestoreBill API
+func (h *BillingHandler) RestoreBill(ctx context.Context, billID string) (*presentation.RestoreBillResponse, error) {
+	bill, err := h.svc.RestoreBill(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.RestoreBillResponse{Bill: presentation.NewBillView(bill)}, nil
 }
 
 // GetBill handles the GetBill API
-func (h *BillingHandler) GetBill(ctx context.Context, billID string) (*model.GetBillResponse, error) {
-	bill, err := h.svc.GetBill(billID)
+func (h *BillingHandler) GetBill(ctx context.Context, billID string, req *model.GetBillRequest) (*presentation.GetBillResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	bill, err := h.svc.GetBill(ctx, billID, req.IncludeDeleted)
 	if err != nil {
 		return nil, err
 	}
-	return &model.GetBillResponse{Bill: *bill}, nil
+	return h.newGetBillResponse(ctx, bill, req)
+}
+
+// newGetBillResponse builds the GetBill response from a single read of the bill.
+// Events are only fetched when the request includes them.
+func (h *BillingHandler) newGetBillResponse(ctx context.Context, bill *model.Bill, req *model.GetBillRequest) (*presentation.GetBillResponse, error) {
+	resp := &presentation.GetBillResponse{Bill: presentation.NewBillView(bill), CategoryTotals: service.CategoryTotals(bill)}
+	resp.Bill.SetPaymentStatus(bill, h.svc.Now())
+	if req.IncludeBreakdown {
+		resp.Breakdown = service.Breakdown(bill)
+	}
+	if req.IncludeActions {
+		resp.Actions = presentation.BillActions(bill)
+	}
+	if req.DisplayCurrency != "" {
+		var err error
+		if resp.Bill.Display, err = h.svc.DisplayAmounts(bill, req.DisplayCurrency); err != nil {
+			return nil, err
+		}
+	}
+	if req.Verify {
+		var err error
+		if resp.TotalCheck, err = h.svc.VerifyTotal(bill); err != nil {
+			return nil, err
+		}
+	}
+	if req.IncludeEvents && h.eventLog != nil {
+		var err error
+		if resp.Events, err = h.eventLog.RecentBillEvents(ctx, bill.ID, req.EventLimit); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
 }
 
 // ListBills handles the ListBills API
-func (h *BillingHandler) ListBills(ctx context.Context, req *model.ListBillsRequest) (*model.ListBillsResponse, error) {
-	bills, err := h.svc.ListBills(req.Status)
+func (h *BillingHandler) ListBills(ctx context.Context, req *model.ListBillsRequest) (*presentation.ListBillsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	totals, err := h.svc.ListBillTotals(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := &presentation.ListBillsResponse{Bills: []presentation.BillView{}, Totals: totals}
+	if req.Summary {
+		summaries, next, err := h.svc.ListBillSummaries(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Summaries, resp.NextCursor = presentation.NewBillSummaryViews(summaries), next
+		if req.DisplayCurrency != "" {
+			for i := range summaries {
+				if resp.Summaries[i].Display, err = h.svc.DisplaySummaryAmounts(&summaries[i], req.DisplayCurrency); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return resp, nil
+	}
+	bills, next, err := h.svc.ListBills(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Bills, resp.NextCursor = presentation.NewBillViews(bills), next
+	if req.DisplayCurrency != "" {
+		for i := range bills {
+			if resp.Bills[i].Display, err = h.svc.DisplayAmounts(&bills[i], req.DisplayCurrency); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resp, nil
+}
+
+// ListCurrencies handles the ListCurrencies API
+func (h *BillingHandler) ListCurrencies(ctx context.Context) (*presentation.ListCurrenciesResponse, error) {
+	currencies, err := h.svc.ListCurrencies()
+	if err != nil {
+		return nil, err
+	}
+	return presentation.NewListCurrenciesResponse(currencies), nil
+}
+
+// ConvertCurrency handles the ConvertCurrency API
+func (h *BillingHandler) ConvertCurrency(ctx context.Context, req *model.ConvertCurrencyRequest) (*presentation.ConvertCurrencyResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	conversion, err := h.svc.ConvertCurrency(req)
 	if err != nil {
 		return nil, err
 	}
-	return &model.ListBillsResponse{Bills: bills}, nil
+	return presentation.NewConvertCurrencyResponse(conversion), nil
 }
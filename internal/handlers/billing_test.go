@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/presentation"
+	"fees-api/internal/repository"
+	"fees-api/internal/service"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestListBillsSummary(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	h.AddLineItem(ctx, created.Bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+
+	full, err := h.ListBills(ctx, &model.ListBillsRequest{})
+	if err != nil {
+		t.Fatalf("ListBills() error = %v", err)
+	}
+	if len(full.Bills) != 1 || len(full.Bills[0].LineItems) != 1 {
+		t.Fatalf("expected the full response to include the line item, got %+v", full.Bills)
+	}
+
+	summary, err := h.ListBills(ctx, &model.ListBillsRequest{Summary: true})
+	if err != nil {
+		t.Fatalf("ListBills(summary) error = %v", err)
+	}
+	if len(summary.Summaries) != 1 || summary.Summaries[0].LineItemCount != 1 || summary.Summaries[0].TotalAmount != 1000 {
+		t.Fatalf("unexpected summaries %+v", summary.Summaries)
+	}
+	raw, _ := json.Marshal(summary)
+	if strings.Contains(string(raw), "lineItems") {
+		t.Errorf("expected the summary response to omit line items, got %s", raw)
+	}
+}
+
+func TestGetBillActions(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+	plain, err := h.GetBill(ctx, created.Bill.ID, &model.GetBillRequest{})
+	if err != nil {
+		t.Fatalf("GetBill() error = %v", err)
+	}
+	if plain.Actions != nil {
+		t.Errorf("expected no actions unless requested, got %v", plain.Actions)
+	}
+
+	resp, err := h.GetBill(ctx, created.Bill.ID, &model.GetBillRequest{IncludeActions: true})
+	if err != nil {
+		t.Fatalf("GetBill(includeActions) error = %v", err)
+	}
+	if len(resp.Actions) != 2 || resp.Actions[0] != presentation.ActionAddLineItem || resp.Actions[1] != presentation.ActionClose {
+		t.Errorf("expected add_line_item and close on an open bill, got %v", resp.Actions)
+	}
+}
+
+func TestGetBillVerify(t *testing.T) {
+	repo := repository.NewInMemoryBillRepository()
+	svc, err := service.NewBillingService(repo)
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	h.AddLineItem(ctx, created.Bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	h.AddLineItem(ctx, created.Bill.ID, &model.AddLineItemRequest{Description: "Usage", Amount: 100.00, Currency: model.CurrencyGEL})
+
+	plain, _ := h.GetBill(ctx, created.Bill.ID, &model.GetBillRequest{})
+	if plain.TotalCheck != nil {
+		t.Errorf("expected no total check unless requested, got %+v", plain.TotalCheck)
+	}
+	resp, err := h.GetBill(ctx, created.Bill.ID, &model.GetBillRequest{Verify: true})
+	if err != nil {
+		t.Fatalf("GetBill(verify) error = %v", err)
+	}
+	if check := resp.TotalCheck; check == nil || !check.Consistent || check.StoredTotal != 4700 || check.ComputedTotal != 4700 {
+		t.Errorf("expected a consistent total of 4700, got %+v", check)
+	}
+
+	// Store the total a 1:1 GEL conversion would have produced
+	stored, _ := repo.Get(ctx, "org_test", created.Bill.ID)
+	stored.TotalAmount = 1000 + 10000
+	repo.Update(ctx, stored)
+
+	resp, err = h.GetBill(ctx, created.Bill.ID, &model.GetBillRequest{Verify: true})
+	if err != nil {
+		t.Fatalf("GetBill(verify) error = %v", err)
+	}
+	if check := resp.TotalCheck; check == nil || check.Consistent || check.StoredTotal != 11000 || check.ComputedTotal != 4700 {
+		t.Errorf("expected the stored 11000 flagged against a computed 4700, got %+v", check)
+	}
+	if resp.Bill.TotalAmount != 11000 {
+		t.Errorf("expected the bill returned as stored, got %d", resp.Bill.TotalAmount)
+	}
+}
+
+func TestGetBillEvents(t *testing.T) {
+	topic := events.NewTopic()
+	eventLog := service.NewEventService(repository.NewInMemoryEventStore())
+	topic.Subscribe(eventLog.HandleEvent)
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository(), service.WithPublisher(topic))
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc, WithEventLog(eventLog))
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	for _, description := range []string{"Setup", "Usage", "Support"} {
+		h.AddLineItem(ctx, created.Bill.ID, &model.AddLineItemRequest{Description: description, Amount: 1.00, Currency: model.CurrencyUSD})
+	}
+
+	plain, err := h.GetBill(ctx, created.Bill.ID, &model.GetBillRequest{})
+	if err != nil {
+		t.Fatalf("GetBill() error = %v", err)
+	}
+	if plain.Events != nil {
+		t.Errorf("expected no events unless requested, got %d", len(plain.Events))
+	}
+
+	resp, err := h.GetBill(ctx, created.Bill.ID, &model.GetBillRequest{IncludeEvents: true})
+	if err != nil {
+		t.Fatalf("GetBill(includeEvents) error = %v", err)
+	}
+	if len(resp.Events) != 4 {
+		t.Fatalf("expected the created event and 3 line item events, got %d", len(resp.Events))
+	}
+	if resp.Events[0].LineItemID != resp.Bill.LineItems[2].ID || resp.Events[3].Type != events.EventBillCreated {
+		t.Errorf("expected events most recent first, got %s first and %s last", resp.Events[0].LineItemID, resp.Events[3].Type)
+	}
+
+	limited, err := h.GetBill(ctx, created.Bill.ID, &model.GetBillRequest{IncludeEvents: true, EventLimit: 2})
+	if err != nil {
+		t.Fatalf("GetBill(eventLimit) error = %v", err)
+	}
+	if len(limited.Events) != 2 || limited.Events[1].LineItemID != resp.Bill.LineItems[1].ID {
+		t.Errorf("expected the 2 most recent events, got %+v", limited.Events)
+	}
+}
+
+func TestDisplayCurrency(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyGEL})
+	billID := created.Bill.ID
+	h.AddLineItem(ctx, billID, &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL})
+	h.AddLineItem(ctx, billID, &model.AddLineItemRequest{Description: "Refund", Amount: 10.00, Currency: model.CurrencyGEL, Type: model.LineItemTypeCredit})
+
+	checkDisplay := func(t *testing.T, display *model.DisplayAmounts, withItems bool) {
+		t.Helper()
+		if display == nil {
+			t.Fatal("expected display amounts")
+		}
+		if display.Currency != model.CurrencyUSD || display.Rate != 0.37 || display.RateAsOf.IsZero() || display.TotalAmount != 3330 {
+			t.Errorf("expected 90.00 GEL shown as 3330 USD cents at 0.37, got %+v", display)
+		}
+		if !withItems {
+			return
+		}
+		if len(display.LineItems) != 2 || display.LineItems[0].NetAmount != 3700 || display.LineItems[1].NetAmount != -370 {
+			t.Errorf("expected line items shown as 3700 and -370, got %+v", display.LineItems)
+		}
+	}
+
+	got, err := h.GetBill(ctx, billID, &model.GetBillRequest{DisplayCurrency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("GetBill() error = %v", err)
+	}
+	checkDisplay(t, got.Bill.Display, true)
+	if got.Bill.Currency != model.CurrencyGEL || got.Bill.TotalAmount != 9000 {
+		t.Errorf("expected the bill's own amounts alongside, got %d %s", got.Bill.TotalAmount, got.Bill.Currency)
+	}
+
+	listed, err := h.ListBills(ctx, &model.ListBillsRequest{DisplayCurrency: model.CurrencyUSD})
+	if err != nil || len(listed.Bills) != 1 {
+		t.Fatalf("ListBills() = %v, %v", listed, err)
+	}
+	checkDisplay(t, listed.Bills[0].Display, true)
+	summaries, err := h.ListBills(ctx, &model.ListBillsRequest{Summary: true, DisplayCurrency: model.CurrencyUSD})
+	if err != nil || len(summaries.Summaries) != 1 {
+		t.Fatalf("ListBills(summary) = %v, %v", summaries, err)
+	}
+	checkDisplay(t, summaries.Summaries[0].Display, false)
+
+	stored, _ := svc.GetBill(ctx, billID, false)
+	if stored.Currency != model.CurrencyGEL || stored.TotalAmount != 9000 || stored.LineItems[0].Amount != 10000 || stored.LineItems[0].ConvertedAmount != 10000 {
+		t.Errorf("expected the stored bill untouched, got %d %s", stored.TotalAmount, stored.Currency)
+	}
+
+	if plain, _ := h.GetBill(ctx, billID, &model.GetBillRequest{}); plain.Bill.Display != nil {
+		t.Error("expected no display amounts without a display currency")
+	}
+	if _, err := h.GetBill(ctx, billID, &model.GetBillRequest{DisplayCurrency: "EUR"}); billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+		t.Errorf("expected an invalid argument error for an unsupported display currency, got %v", err)
+	}
+}
+
+func TestCloseBillsPassesApproval(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository(), service.WithMaxAutoCloseTotal(500))
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	h.AddLineItem(ctx, created.Bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+
+	resp, err := h.CloseBills(ctx, &model.CloseBillsRequest{BillIDs: []string{created.Bill.ID}})
+	if err != nil {
+		t.Fatalf("CloseBills() error = %v", err)
+	}
+	if resp.Results[0].Outcome != model.CloseOutcomeFailed {
+		t.Fatalf("expected a bill above the threshold to fail without approval, got %s", resp.Results[0].Outcome)
+	}
+
+	resp, err = h.CloseBills(ctx, &model.CloseBillsRequest{BillIDs: []string{created.Bill.ID}, ApprovedBy: "mgr_1"})
+	if err != nil {
+		t.Fatalf("CloseBills() error = %v", err)
+	}
+	if resp.Results[0].Outcome != model.CloseOutcomeClosed {
+		t.Errorf("expected the approved bill closed, got %s: %s", resp.Results[0].Outcome, resp.Results[0].Error)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/presentation"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// BillETag computes a strong ETag from the bill's content, so it changes whenever
+// anything about the bill does
+func BillETag(bill *model.Bill) string {
+	return contentETag(bill)
+}
+
+// contentETag computes a strong ETag from v's JSON encoding
+func contentETag(v any) string {
+	raw, _ := json.Marshal(v)
+	sum := sha256.Sum256(raw)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value matches the ETag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeGetBill is the raw HTTP form of GetBill. It sets an ETag on the response and
+// answers 304 Not Modified when the request's If-None-Match still matches, so
+// polling clients only download a bill when it changed. Clients sending
+// X-Field-Naming: snake_case get the legacy snake_case form of the bill.
+func (h *BillingHandler) ServeGetBill(w http.ResponseWriter, r *http.Request, billID string) {
+	query := r.URL.Query()
+	includeDeleted, _ := strconv.ParseBool(query.Get("includeDeleted"))
+	includeBreakdown, _ := strconv.ParseBool(query.Get("includeBreakdown"))
+	includeActions, _ := strconv.ParseBool(query.Get("includeActions"))
+	includeEvents, _ := strconv.ParseBool(query.Get("includeEvents"))
+	eventLimit, _ := strconv.Atoi(query.Get("eventLimit"))
+	verify, _ := strconv.ParseBool(query.Get("verify"))
+	req := &model.GetBillRequest{
+		IncludeDeleted:   includeDeleted,
+		IncludeBreakdown: includeBreakdown,
+		IncludeActions:   includeActions,
+		IncludeEvents:    includeEvents,
+		EventLimit:       eventLimit,
+		Verify:           verify,
+		DisplayCurrency:  model.Currency(query.Get("displayCurrency")).Normalize(),
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	bill, err := h.svc.GetBill(r.Context(), billID, includeDeleted)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := h.newGetBillResponse(r.Context(), bill, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	etag := BillETag(bill)
+	if includeEvents || resp.Bill.Display != nil || resp.Bill.DaysUntilDue != nil {
+		// Events are recorded asynchronously, display amounts follow the current rate
+		// and the days until due follow the clock, so any can change while the bill
+		// doesn't
+		etag = contentETag(struct {
+			Bill         *model.Bill
+			Events       []events.BillEvent
+			Display      *model.DisplayAmounts
+			DaysUntilDue *int
+		}{bill, resp.Events, resp.Bill.Display, resp.Bill.DaysUntilDue})
+	}
+
+	var body any = resp
+	if r.Header.Get(presentation.FieldNamingHeader) == presentation.FieldNamingSnakeCase {
+		body = presentation.NewLegacyGetBillResponse(resp)
+		// The same bill in another shape must not satisfy the other's If-None-Match
+		etag = contentETag(body)
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Vary", presentation.FieldNamingHeader)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes err as a JSON error body with the status matching its code
+func writeError(w http.ResponseWriter, err error) {
+	code := billingerrors.CodeOf(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code.HTTPStatus())
+	json.NewEncoder(w).Encode(struct {
+		Code    billingerrors.Code             `json:"code"`
+		Message string                         `json:"message"`
+		Fields  []billingerrors.FieldViolation `json:"fields,omitempty"`
+	}{code, err.Error(), billingerrors.FieldsOf(err)})
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fees-api/internal/model"
+	"fees-api/internal/presentation"
+	"fees-api/internal/repository"
+	"fees-api/internal/service"
+	"fees-api/internal/tenant"
+)
+
+func getBill(ctx context.Context, h *BillingHandler, billID, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/bills/"+billID, nil).WithContext(ctx)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeGetBill(rec, req, billID)
+	return rec
+}
+
+func TestServeGetBillETag(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	billID := created.Bill.ID
+
+	first := getBill(ctx, h, billID, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", first.Code, etag)
+	}
+
+	if again := getBill(ctx, h, billID, ""); again.Header().Get("ETag") != etag {
+		t.Errorf("expected the same ETag for an unchanged bill, got %q and %q", etag, again.Header().Get("ETag"))
+	}
+
+	notModified := getBill(ctx, h, billID, etag)
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Errorf("expected 304 with an empty body, got %d with %d bytes", notModified.Code, notModified.Body.Len())
+	}
+
+	h.AddLineItem(ctx, billID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+
+	changed := getBill(ctx, h, billID, etag)
+	if changed.Code != http.StatusOK {
+		t.Errorf("expected 200 after the bill changed, got %d", changed.Code)
+	}
+	if changed.Header().Get("ETag") == etag {
+		t.Error("expected a new ETag after adding a line item")
+	}
+
+	if missing := getBill(ctx, h, "nonexistent", ""); missing.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a nonexistent bill, got %d", missing.Code)
+	}
+}
+
+func TestServeGetBillFieldNaming(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	billID := created.Bill.ID
+	h.AddLineItem(ctx, billID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+
+	camel := getBill(ctx, h, billID, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/bills/"+billID, nil).WithContext(ctx)
+	req.Header.Set(presentation.FieldNamingHeader, presentation.FieldNamingSnakeCase)
+	snake := httptest.NewRecorder()
+	h.ServeGetBill(snake, req, billID)
+
+	if !strings.Contains(camel.Body.String(), `"totalAmount":1000`) || !strings.Contains(camel.Body.String(), `"lineItems"`) {
+		t.Errorf("expected camelCase fields by default, got %s", camel.Body)
+	}
+	if !strings.Contains(snake.Body.String(), `"total_amount":1000`) || !strings.Contains(snake.Body.String(), `"line_items"`) {
+		t.Errorf("expected snake_case fields, got %s", snake.Body)
+	}
+	if snake.Header().Get("ETag") == camel.Header().Get("ETag") {
+		t.Error("expected the two representations to have different ETags")
+	}
+	if vary := snake.Header().Get("Vary"); vary != presentation.FieldNamingHeader {
+		t.Errorf("expected Vary: %s, got %q", presentation.FieldNamingHeader, vary)
+	}
+}
+
+func TestServeGetBillDisplayCurrency(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyGEL})
+	billID := created.Bill.ID
+	h.AddLineItem(ctx, billID, &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL})
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/bills/"+billID+query, nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		h.ServeGetBill(rec, req, billID)
+		return rec
+	}
+
+	plain := serve("")
+	shown := serve("?displayCurrency=usd")
+	if shown.Code != http.StatusOK || !strings.Contains(shown.Body.String(), `"display":{"currency":"USD","rate":0.37`) {
+		t.Fatalf("expected display amounts in USD, got %d %s", shown.Code, shown.Body.String())
+	}
+	if shown.Header().Get("ETag") == plain.Header().Get("ETag") {
+		t.Error("expected the display currency to change the ETag")
+	}
+
+	if rejected := serve("?displayCurrency=EUR"); rejected.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unsupported display currency, got %d", rejected.Code)
+	}
+}
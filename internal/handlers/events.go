@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"context"
+
+	"fees-api/internal/presentation"
+	"fees-api/internal/service"
+)
+
+// EventHandler handles HTTP requests for bill event history
+type EventHandler struct {
+	svc *service.EventService
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(svc *service.EventService) *EventHandler {
+	return &EventHandler{svc: svc}
+}
+
+// GetBillEvents handles the GetBillEvents API
+func (h *EventHandler) GetBillEvents(ctx context.Context, billID string) (*presentation.GetBillEventsResponse, error) {
+	billEvents, err := h.svc.GetBillEvents(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	return &presentation.GetBillEventsResponse{Events: billEvents}, nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"fees-api/internal/model"
+	"fees-api/internal/service"
+)
+
+// ProcessorWebhookHandler handles webhook deliveries from the payment processor
+type ProcessorWebhookHandler struct {
+	svc       *service.ProcessorWebhookService
+	onApplied func(ctx context.Context, bill *model.Bill, lineItemID string)
+}
+
+// NewProcessorWebhookHandler creates a new processor webhook handler. onApplied, if
+// set, is called after a delivery adds a line item to a bill.
+func NewProcessorWebhookHandler(svc *service.ProcessorWebhookService, onApplied func(ctx context.Context, bill *model.Bill, lineItemID string)) *ProcessorWebhookHandler {
+	return &ProcessorWebhookHandler{svc: svc, onApplied: onApplied}
+}
+
+// ServeWebhook verifies and applies one processor delivery. It answers 401 for a
+// bad signature and 404 for a fee whose reference isn't linked to a bill, so the
+// processor retries those, and 200 otherwise, including for repeat deliveries.
+func (h *ProcessorWebhookHandler) ServeWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r, model.MaxRequestBodyBytes)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, bill, err := h.svc.Ingest(r.Context(), body, r.Header.Get(service.ProcessorSignatureHeader))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if bill != nil && h.onApplied != nil {
+		h.onApplied(r.Context(), bill, result.LineItemID)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// ServeLinkRef is the raw HTTP form of LinkRef, for the admin endpoint
+func (h *ProcessorWebhookHandler) ServeLinkRef(w http.ResponseWriter, r *http.Request, ref string) {
+	var req model.LinkProcessorRefRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	link, err := h.svc.LinkRef(r.Context(), ref, &req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, link)
+}
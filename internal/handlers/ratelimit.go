@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// RateLimitConfig configures a token bucket: Rate tokens are added per second up
+// to a maximum of Burst
+type RateLimitConfig struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultRateLimitConfig returns the limits applied to AddLineItem in production
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{Rate: 10, Burst: 20}
+}
+
+// BucketStore holds token bucket state. Take must atomically refill the bucket for
+// key and consume one token, reporting whether a token was available.
+type BucketStore interface {
+	Take(key string, config RateLimitConfig, now time.Time) bool
+}
+
+// RateLimiter limits requests per key using a token bucket
+type RateLimiter struct {
+	config RateLimitConfig
+	store  BucketStore
+	now    func() time.Time
+}
+
+// NewRateLimiter creates a rate limiter backed by the given store
+func NewRateLimiter(config RateLimitConfig, store BucketStore) *RateLimiter {
+	return &RateLimiter{config: config, store: store, now: time.Now}
+}
+
+// Allow consumes a token for key, returning a ResourceExhausted error if none is left
+func (l *RateLimiter) Allow(key string) error {
+	if !l.store.Take(key, l.config, l.now()) {
+		return billingerrors.RateLimited(key)
+	}
+	return nil
+}
+
+// AllowCaller consumes a token from the calling org's bucket, so one org's writes
+// share a limit however many bills they spread over. The org is the one
+// Authenticator.RequireOrg put on the context.
+func (l *RateLimiter) AllowCaller(ctx context.Context) error {
+	orgID, ok := tenant.OrgIDFromContext(ctx)
+	if !ok {
+		return billingerrors.Unauthenticated("request has no organization")
+	}
+	return l.Allow("org:" + orgID)
+}
+
+// bucket is the state of a single token bucket
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// full reports whether the bucket has refilled to its burst by now. A full bucket
+// is the same as a new one, so it can be dropped.
+func (b *bucket) full(config RateLimitConfig, now time.Time) bool {
+	return b.tokens+now.Sub(b.lastRefill).Seconds()*config.Rate >= float64(config.Burst)
+}
+
+// bucketSweepInterval is how often InMemoryBucketStore drops idle buckets
+const bucketSweepInterval = time.Minute
+
+// InMemoryBucketStore keeps token buckets in process memory. Buckets that have
+// refilled are dropped every bucketSweepInterval, so idle keys don't accumulate.
+type InMemoryBucketStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewInMemoryBucketStore creates an empty in-memory bucket store
+func NewInMemoryBucketStore() *InMemoryBucketStore {
+	return &InMemoryBucketStore{buckets: make(map[string]*bucket)}
+}
+
+// Take refills the key's bucket for the elapsed time and consumes one token
+func (s *InMemoryBucketStore) Take(key string, config RateLimitConfig, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= bucketSweepInterval {
+		for k, idle := range s.buckets {
+			if idle.full(config, now) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(config.Burst), lastRefill: now}
+		s.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(config.Burst), b.tokens+elapsed*config.Rate)
+		b.lastRefill = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	"fees-api/internal/service"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func newTestLimiter(config RateLimitConfig, now *time.Time) *RateLimiter {
+	limiter := NewRateLimiter(config, NewInMemoryBucketStore())
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestRateLimiterExhaustsAndRefills(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(RateLimitConfig{Rate: 1, Burst: 3}, &now)
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Allow("customer:1"); err != nil {
+			t.Fatalf("request %d: unexpected error %v", i+1, err)
+		}
+	}
+
+	err := limiter.Allow("customer:1")
+	if billingerrors.CodeOf(err) != billingerrors.CodeResourceExhausted {
+		t.Fatalf("expected ResourceExhausted once the bucket is empty, got %v", err)
+	}
+
+	// Other keys have their own bucket
+	if err := limiter.Allow("customer:2"); err != nil {
+		t.Errorf("expected independent bucket per key, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if err := limiter.Allow("customer:1"); err != nil {
+		t.Errorf("expected a token after the refill interval, got %v", err)
+	}
+	if err := limiter.Allow("customer:1"); err == nil {
+		t.Error("expected only one token to be refilled after one second")
+	}
+}
+
+func TestAddLineItemRateLimited(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc, WithRateLimiter(newTestLimiter(RateLimitConfig{Rate: 1, Burst: 2}, &now)))
+
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	other, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	req := &model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: model.CurrencyUSD}
+
+	for i := 0; i < 2; i++ {
+		if _, err := h.AddLineItem(ctx, created.Bill.ID, req); err != nil {
+			t.Fatalf("request %d: unexpected error %v", i+1, err)
+		}
+	}
+
+	// The limit is the org's, so another of its bills doesn't get a fresh bucket
+	_, err = h.AddLineItem(ctx, other.Bill.ID, req)
+	if billingerrors.CodeOf(err) != billingerrors.CodeResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+
+	// Other orgs have their own limit
+	otherOrg := tenant.WithOrgID(context.Background(), "org_other")
+	otherBill, _ := h.CreateBill(otherOrg, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if _, err := h.AddLineItem(otherOrg, otherBill.Bill.ID, req); err != nil {
+		t.Errorf("expected another org unaffected, got %v", err)
+	}
+
+	now = now.Add(2 * time.Second)
+	resp, err := h.AddLineItem(ctx, created.Bill.ID, req)
+	if err != nil {
+		t.Fatalf("expected recovery after refill, got %v", err)
+	}
+	if len(resp.Bill.LineItems) != 3 {
+		t.Errorf("expected 3 line items, got %d", len(resp.Bill.LineItems))
+	}
+}
+
+func TestInMemoryBucketStoreDropsIdleBuckets(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// A used token takes 100s to refill
+	config := RateLimitConfig{Rate: 0.01, Burst: 3}
+	store := NewInMemoryBucketStore()
+
+	store.Take("org:a", config, now)
+	store.Take("org:b", config, now)
+	for i := 0; i < 3; i++ {
+		store.Take("org:busy", config, now.Add(30*time.Second))
+	}
+	if len(store.buckets) != 3 {
+		t.Fatalf("expected buckets kept until the sweep interval passes, got %d", len(store.buckets))
+	}
+
+	// org:a and org:b have refilled by now; org:busy hasn't
+	store.Take("org:busy", config, now.Add(150*time.Second))
+	if _, ok := store.buckets["org:busy"]; !ok || len(store.buckets) != 1 {
+		t.Errorf("expected only the drained bucket kept, got %d buckets", len(store.buckets))
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"fees-api/internal/model"
+	"fees-api/internal/validation"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// Endpoints that act for an org are raw, so Authenticator.RequireOrg can put the
+// caller's org on the context before they run: typed Encore endpoints never see
+// the request's headers. Typed keeps their bodies in the typed shape.
+
+// Typed adapts a typed endpoint to a raw handler. The request is decoded into Req
+// from the query string of GET and DELETE requests and the JSON body of others,
+// and validated, as Encore does for typed endpoints. The response is written as
+// JSON, or the error with the status matching its code; an endpoint without a
+// response body returns a nil Resp.
+func Typed[Req, Resp any](call func(ctx context.Context, req *Req) (*Resp, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(Req)
+		if err := decodeRequest(r, req); err != nil {
+			writeError(w, err)
+			return
+		}
+		resp, err := call(r.Context(), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// PathParams returns the values of pattern's ":name" segments in path, e.g.
+// billID "bill_1" for "/bills/bill_1/close" against "/bills/:billID/close"
+func PathParams(path, pattern string) map[string]string {
+	params := make(map[string]string)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok && i < len(segments) {
+			params[name] = segments[i]
+		}
+	}
+	return params
+}
+
+// decodeRequest fills req from the request and validates it. An empty body leaves
+// req at its zero value.
+func decodeRequest(r *http.Request, req any) error {
+	if r.Method == http.MethodGet || r.Method == http.MethodDelete {
+		if err := decodeQuery(r.URL.Query(), req); err != nil {
+			return err
+		}
+	} else {
+		body, err := readBody(r, model.MaxRequestBodyBytes)
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			// Validates as well
+			return validation.DecodeJSON(body, req)
+		}
+	}
+	if validatable, ok := req.(validation.Validatable); ok {
+		return validatable.Validate()
+	}
+	return nil
+}
+
+// decodeQuery sets the fields of the struct dst points to from the query
+// parameters named by their `query` tags. Fields may be strings (or decode from
+// text), booleans, integers or floats.
+func decodeQuery(query url.Values, dst any) error {
+	value := reflect.ValueOf(dst).Elem()
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	var violations []billingerrors.FieldViolation
+	for i := 0; i < value.NumField(); i++ {
+		name := value.Type().Field(i).Tag.Get("query")
+		raw := query.Get(name)
+		if name == "" || raw == "" {
+			continue
+		}
+		if err := setField(value.Field(i), raw); err != nil {
+			violations = append(violations, billingerrors.FieldViolation{Field: name, Description: err.Error()})
+		}
+	}
+	if len(violations) > 0 {
+		return billingerrors.InvalidArgument(violations)
+	}
+	return nil
+}
+
+// setField parses raw into the field
+func setField(field reflect.Value, raw string) error {
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		field.SetInt(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		field.SetFloat(parsed)
+	default:
+		return errors.New("is not supported as a query parameter")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fees-api/internal/model"
+	"fees-api/internal/presentation"
+	"fees-api/internal/repository"
+	"fees-api/internal/service"
+)
+
+func TestTypedEndpointsAuthenticateWithAPIKey(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	auth := NewAuthenticator(ParseAPIKeys("key_a:org_a"), nil, "")
+	createBill := auth.RequireOrg(Typed(h.CreateBill))
+	listBills := auth.RequireOrg(Typed(h.ListBills))
+
+	serve := func(handler http.Handler, req *http.Request, token string) *httptest.ResponseRecorder {
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(createBill, httptest.NewRequest(http.MethodPost, "/bills", strings.NewReader(`{"currency":"usd"}`)), "key_a")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 creating a bill with an API key, got %d: %s", rec.Code, rec.Body)
+	}
+	var created presentation.CreateBillResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if created.Bill.OrgID != "org_a" || created.Bill.Currency != model.CurrencyUSD {
+		t.Errorf("expected a USD bill for org_a, got %+v", created.Bill)
+	}
+
+	rec = serve(listBills, httptest.NewRequest(http.MethodGet, "/bills?currency=usd&limit=10", nil), "key_a")
+	var listed presentation.ListBillsResponse
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &listed) != nil || len(listed.Bills) != 1 {
+		t.Errorf("expected 200 listing the org's bill, got %d: %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(createBill, httptest.NewRequest(http.MethodPost, "/bills", strings.NewReader(`{"currency":"usd"}`)), ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without an API key, got %d", rec.Code)
+	}
+	if rec := serve(createBill, httptest.NewRequest(http.MethodPost, "/bills", strings.NewReader(`{"currency":"xyz"}`)), "key_a"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid request, got %d", rec.Code)
+	}
+	if rec := serve(listBills, httptest.NewRequest(http.MethodGet, "/bills?limit=ten", nil), "key_a"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a malformed query parameter, got %d", rec.Code)
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	params := PathParams("/bills/bill_1/items/item_2", "/bills/:billID/items/:lineItemID")
+	if params["billID"] != "bill_1" || params["lineItemID"] != "item_2" {
+		t.Errorf("expected billID bill_1 and lineItemID item_2, got %v", params)
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// ServeWatchBill is the raw HTTP form of WatchBill, a long-poll for bill changes.
+// It answers with the bill once its version passes the "since" query parameter,
+// or 304 Not Modified if it doesn't within "timeout" (e.g. "25s"). Clients resume
+// by watching again from the version they last got.
+func (h *BillingHandler) ServeWatchBill(w http.ResponseWriter, r *http.Request, billID string) {
+	query := r.URL.Query()
+	var violations []billingerrors.FieldViolation
+	since, err := strconv.ParseInt(query.Get("since"), 10, 64)
+	if err != nil || since < 0 {
+		violations = append(violations, billingerrors.FieldViolation{Field: "since", Description: "must be a bill version"})
+	}
+	var timeout time.Duration
+	if raw := query.Get("timeout"); raw != "" {
+		if timeout, err = time.ParseDuration(raw); err != nil || timeout <= 0 {
+			violations = append(violations, billingerrors.FieldViolation{Field: "timeout", Description: "must be a positive duration, e.g. 25s"})
+		}
+	}
+	if len(violations) > 0 {
+		writeError(w, billingerrors.InvalidArgument(violations))
+		return
+	}
+
+	bill, err := h.svc.WatchBill(r.Context(), billID, since, timeout)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	if bill == nil {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	resp, err := h.newGetBillResponse(r.Context(), bill, &model.GetBillRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	"fees-api/internal/service"
+	"fees-api/internal/tenant"
+)
+
+func TestServeWatchBill(t *testing.T) {
+	topic := events.NewTopic()
+	watcher := service.NewBillWatcher()
+	topic.Subscribe(watcher.HandleEvent)
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository(),
+		service.WithPublisher(topic), service.WithBillWatcher(watcher))
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	h := NewBillingHandler(svc)
+	ctx := tenant.WithOrgID(context.Background(), "org_test")
+	created, _ := h.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	billID := created.Bill.ID
+
+	watch := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/bills/"+billID+"/watch?"+query, nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		h.ServeWatchBill(rec, req, billID)
+		return rec
+	}
+
+	if rec := watch("since=1&timeout=10ms"); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("expected 304 with an empty body for an unchanged bill, got %d with %d bytes", rec.Code, rec.Body.Len())
+	}
+	if rec := watch("since=0&timeout=10ms"); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a bill past the version, got %d", rec.Code)
+	}
+	for _, query := range []string{"", "since=abc", "since=1&timeout=soon"} {
+		if rec := watch(query); rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for %q, got %d", query, rec.Code)
+		}
+	}
+}
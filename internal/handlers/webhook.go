@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+
+	"fees-api/internal/model"
+	"fees-api/internal/service"
+)
+
+// WebhookHandler handles HTTP requests for webhook registration
+type WebhookHandler struct {
+	svc *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(svc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+// RegisterWebhook handles the RegisterWebhook API
+func (h *WebhookHandler) RegisterWebhook(ctx context.Context, req *model.RegisterWebhookRequest) (*model.RegisterWebhookResponse, error) {
+	endpoint, err := h.svc.Register(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &model.RegisterWebhookResponse{Webhook: *endpoint}, nil
+}
+
+// ListWebhooks handles the ListWebhooks API
+func (h *WebhookHandler) ListWebhooks(ctx context.Context) (*model.ListWebhooksResponse, error) {
+	endpoints, err := h.svc.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ListWebhooksResponse{Webhooks: endpoints}, nil
+}
+
+// DeleteWebhook handles the DeleteWebhook API
+func (h *WebhookHandler) DeleteWebhook(ctx context.Context, webhookID string) error {
+	return h.svc.Delete(ctx, webhookID)
+}
@@ -0,0 +1,73 @@
+// Package lifecycle coordinates an orderly shutdown of the service's background work
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Lifecycle runs registered shutdown hooks in the order they were registered
+type Lifecycle struct {
+	mu    sync.Mutex
+	hooks []hook
+	done  bool
+}
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// New creates a lifecycle with no hooks
+func New() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// OnShutdown registers a hook to run on Shutdown. Register producers before their
+// consumers so each component stops taking work before the next one drains.
+func (l *Lifecycle) OnShutdown(name string, stop func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook{name: name, stop: stop})
+}
+
+// Shutdown runs every hook in order, sharing ctx's deadline. A failing or timed
+// out hook doesn't stop the ones after it; their errors are joined. Only the first
+// call runs the hooks.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	if l.done {
+		l.mu.Unlock()
+		return nil
+	}
+	l.done = true
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	var errs []error
+	for _, h := range hooks {
+		if err := h.stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Wait blocks until wg's counter reaches zero or ctx is done, returning ctx's
+// error in the latter case
+func Wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,47 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownRunsHooksInOrder(t *testing.T) {
+	lc := New()
+	var order []string
+	lc.OnShutdown("first", func(ctx context.Context) error {
+		order = append(order, "first")
+		return errors.New("boom")
+	})
+	lc.OnShutdown("second", func(ctx context.Context) error {
+		order = append(order, "second")
+		return nil
+	})
+
+	err := lc.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "first: boom") {
+		t.Errorf("expected the first hook's error, got %v", err)
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("expected hooks to run in order despite the error, got %v", order)
+	}
+
+	if err := lc.Shutdown(context.Background()); err != nil || len(order) != 2 {
+		t.Errorf("expected a second Shutdown to be a no-op, got %v after %v", err, order)
+	}
+}
+
+func TestWaitHonoursDeadline(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	defer wg.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := Wait(ctx, &wg); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
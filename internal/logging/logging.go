@@ -0,0 +1,16 @@
+package logging
+
+// Logger writes leveled, structured log records. Attributes are passed as
+// alternating keys and values; *slog.Logger satisfies it.
+type Logger interface {
+	Info(msg string, keyvals ...any)
+	Warn(msg string, keyvals ...any)
+	Error(msg string, keyvals ...any)
+}
+
+// Nop discards every record
+type Nop struct{}
+
+func (Nop) Info(msg string, keyvals ...any)  {}
+func (Nop) Warn(msg string, keyvals ...any)  {}
+func (Nop) Error(msg string, keyvals ...any) {}
@@ -1,6 +1,11 @@
 package model
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // bill time zones must resolve on hosts without a zoneinfo database
+)
 
 // Currency represents the currency type
 type Currency string
@@ -10,83 +15,585 @@ const (
 	CurrencyUSD Currency = "USD"
 )
 
+// SupportedCurrencies lists the currencies bills and line items may use
+var SupportedCurrencies = []Currency{CurrencyGEL, CurrencyUSD}
+
+// Normalize returns the currency code trimmed and upper-cased, so "usd" and " GEL "
+// name USD and GEL. It doesn't check that the currency is supported.
+func (c Currency) Normalize() Currency {
+	return Currency(strings.ToUpper(strings.TrimSpace(string(c))))
+}
+
+// UnmarshalText decodes a currency code from JSON or a query string, normalizing
+// it before any validation runs
+func (c *Currency) UnmarshalText(text []byte) error {
+	*c = Currency(text).Normalize()
+	return nil
+}
+
+// IsSupported reports whether the currency is in SupportedCurrencies
+func (c Currency) IsSupported() bool {
+	for _, supported := range SupportedCurrencies {
+		if c == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrencyDecimalPlaces holds the number of minor-unit digits per currency.
+// Currencies missing from the table default to 2.
+var CurrencyDecimalPlaces = map[Currency]int{
+	CurrencyGEL: 2,
+	CurrencyUSD: 2,
+}
+
+// DecimalPlaces returns how many decimal digits the currency's minor unit has
+func (c Currency) DecimalPlaces() int {
+	if places, ok := CurrencyDecimalPlaces[c]; ok {
+		return places
+	}
+	return 2
+}
+
 // BillStatus represents the status of a bill
 type BillStatus string
 
 const (
+	BillStatusDraft  BillStatus = "draft" // staged, accepts line items but isn't live yet
 	BillStatusOpen   BillStatus = "open"
 	BillStatusClosed BillStatus = "closed"
 )
 
+// BillStatuses lists every status a bill may have
+var BillStatuses = []BillStatus{BillStatusDraft, BillStatusOpen, BillStatusClosed}
+
+// IsValid reports whether the status is in BillStatuses
+func (s BillStatus) IsValid() bool {
+	for _, status := range BillStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// LineItemCategory classifies a line item for reporting
+type LineItemCategory string
+
+const (
+	CategoryProcessing   LineItemCategory = "processing"
+	CategoryPenalty      LineItemCategory = "penalty"
+	CategorySubscription LineItemCategory = "subscription"
+	CategoryOther        LineItemCategory = "other"
+)
+
+// LineItemCategories lists the categories a line item may use
+var LineItemCategories = []LineItemCategory{
+	CategoryProcessing,
+	CategoryPenalty,
+	CategorySubscription,
+	CategoryOther,
+}
+
+// IsValid reports whether the category is in LineItemCategories
+func (c LineItemCategory) IsValid() bool {
+	for _, category := range LineItemCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// LineItemType says whether a line item adds to or subtracts from the bill
+type LineItemType string
+
+const (
+	LineItemTypeCharge LineItemType = "charge"
+	LineItemTypeCredit LineItemType = "credit" // refunds, goodwill; amounts stay positive and are subtracted
+)
+
+// LineItemTypes lists the types a line item may have
+var LineItemTypes = []LineItemType{LineItemTypeCharge, LineItemTypeCredit}
+
+// IsValid reports whether the type is in LineItemTypes
+func (t LineItemType) IsValid() bool {
+	for _, itemType := range LineItemTypes {
+		if t == itemType {
+			return true
+		}
+	}
+	return false
+}
+
 // Bill represents a billing invoice
 type Bill struct {
 	ID          string     `json:"id"`
+	OrgID       string     `json:"orgId"` // owning organization; bills are only visible within it
 	Status      BillStatus `json:"status"`
 	Currency    Currency   `json:"currency"`
-	TotalAmount int64      `json:"totalAmount"` // stored in cents
+	TotalAmount int64      `json:"totalAmount"`      // stored in cents, including FXFees
+	FXFees      int64      `json:"fxFees,omitempty"` // FX markup charged on converted line items, in cents
 	LineItems   []LineItem `json:"lineItems,omitempty"`
+	Note        string     `json:"note,omitempty"`     // internal free-text context, editable after close
+	Timezone    string     `json:"timezone,omitempty"` // IANA name timestamps are displayed in; stored times stay UTC
 	CreatedAt   time.Time  `json:"createdAt"`
 	ClosedAt    *time.Time `json:"closedAt,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"` // set when soft-deleted
+
+	// Billing period, set once the bill is open; drafts don't have one yet
+	PeriodStart *time.Time `json:"periodStart,omitempty"`
+	PeriodEnd   *time.Time `json:"periodEnd,omitempty"`
+
+	// Snapshot taken at close so later conversion changes can't alter the record
+	FinalTotal         *int64 `json:"finalTotal,omitempty"` // in cents
+	FinalLineItemCount *int   `json:"finalLineItemCount,omitempty"`
+
+	// InvoiceNumber is the number finance refers to the bill by, e.g. INV-2024-000123.
+	// It's assigned the first time the bill closes, from a per-year sequence without
+	// gaps, and kept through reopening; the API still routes by ID.
+	InvoiceNumber string `json:"invoiceNumber,omitempty"`
+
+	// Payment of a closed bill: due its payment terms after closing
+	DueDate    *time.Time `json:"dueDate,omitempty"`
+	AmountPaid int64      `json:"amountPaid,omitempty"` // in cents
+
+	// CloseApproval is set once someone approves closing a bill whose total is
+	// above the auto-close threshold
+	CloseApproval *CloseApproval `json:"closeApproval,omitempty"`
+
+	// Version starts at 1 and goes up with every change to the bill, so clients can
+	// tell whether the bill they hold is current and wait for the next change
+	Version int64 `json:"version"`
+}
+
+// CloseApproval records who approved closing a bill and at what total. It covers
+// the bill up to that total; a bill that grows past it needs approving again.
+type CloseApproval struct {
+	ApprovedBy string    `json:"approvedBy"`
+	Total      int64     `json:"total"` // in cents
+	ApprovedAt time.Time `json:"approvedAt"`
+}
+
+// BillSummary is the lightweight form of a bill used by list views: no line items
+type BillSummary struct {
+	ID            string     `json:"id"`
+	Status        BillStatus `json:"status"`
+	Currency      Currency   `json:"currency"`
+	TotalAmount   int64      `json:"totalAmount"` // in cents
+	LineItemCount int        `json:"lineItemCount"`
+	Timezone      string     `json:"timezone,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	ClosedAt      *time.Time `json:"closedAt,omitempty"`
+}
+
+// Summary returns the bill's summary
+func (b *Bill) Summary() BillSummary {
+	return BillSummary{
+		ID:            b.ID,
+		Status:        b.Status,
+		Currency:      b.Currency,
+		TotalAmount:   b.TotalAmount,
+		LineItemCount: len(b.LineItems),
+		Timezone:      b.Timezone,
+		CreatedAt:     b.CreatedAt,
+		ClosedAt:      b.ClosedAt,
+	}
+}
+
+// Location returns the time zone the bill's timestamps are displayed in. Bills
+// without a valid Timezone display in UTC.
+func (b *Bill) Location() *time.Location {
+	return displayLocation(b.Timezone)
+}
+
+// Location returns the time zone of the summarized bill
+func (s *BillSummary) Location() *time.Location {
+	return displayLocation(s.Timezone)
+}
+
+func displayLocation(timezone string) *time.Location {
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LoadTimezone resolves an IANA time zone name, treating an empty name as UTC
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// SortLineItems orders the bill's line items by Sequence, the order they were
+// added in. Items without one (stored before sequences existed) keep their
+// relative order, ahead of the rest.
+func (b *Bill) SortLineItems() {
+	sort.SliceStable(b.LineItems, func(i, j int) bool {
+		return b.LineItems[i].Sequence < b.LineItems[j].Sequence
+	})
+}
+
+// AppendLineItem adds an item to the end of the bill's line items, giving it the
+// next sequence number
+func (b *Bill) AppendLineItem(item LineItem) {
+	item.Sequence = 1
+	for _, existing := range b.LineItems {
+		if existing.Sequence >= item.Sequence {
+			item.Sequence = existing.Sequence + 1
+		}
+	}
+	b.LineItems = append(b.LineItems, item)
+}
+
+// Clone returns a deep copy of the bill: its line items, their metadata and every
+// pointer field are copied, so changes to one never show through the other
+func (b *Bill) Clone() *Bill {
+	clone := *b
+	if b.LineItems != nil {
+		clone.LineItems = make([]LineItem, len(b.LineItems))
+		for i := range b.LineItems {
+			clone.LineItems[i] = b.LineItems[i].Clone()
+		}
+	}
+	clone.ClosedAt = clonePtr(b.ClosedAt)
+	clone.DeletedAt = clonePtr(b.DeletedAt)
+	clone.PeriodStart = clonePtr(b.PeriodStart)
+	clone.PeriodEnd = clonePtr(b.PeriodEnd)
+	clone.FinalTotal = clonePtr(b.FinalTotal)
+	clone.FinalLineItemCount = clonePtr(b.FinalLineItemCount)
+	clone.DueDate = clonePtr(b.DueDate)
+	clone.CloseApproval = clonePtr(b.CloseApproval)
+	return &clone
+}
+
+// BalanceDue returns what's left to pay on the bill, in cents: its final total, or
+// running total before close, less what's been paid
+func (b *Bill) BalanceDue() int64 {
+	total := b.TotalAmount
+	if b.FinalTotal != nil {
+		total = *b.FinalTotal
+	}
+	return total - b.AmountPaid
 }
 
 // LineItem represents a single line item on a bill
 type LineItem struct {
-	ID          string    `json:"id"`
-	Description string    `json:"description"`
-	Amount      int64     `json:"amount"` // stored in cents
-	Currency    Currency  `json:"currency"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID string `json:"id"`
+	// Sequence is the item's position in the order items were added to the bill,
+	// from 1. Line items are always returned sorted by it; SQL-backed repositories
+	// should store it and ORDER BY it when loading a bill's items.
+	Sequence    int              `json:"sequence"`
+	Description string           `json:"description"`
+	Amount      int64            `json:"amount"` // stored in cents
+	Currency    Currency         `json:"currency"`
+	Category    LineItemCategory `json:"category"`
+	Type        LineItemType     `json:"type"`
+	// Metadata holds integration references (order ID, SKU, ...). SQL-backed
+	// repositories should persist it as a JSON column.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Ref is the integration's external reference, unique per bill when set.
+	// SQL-backed repositories should enforce it with a unique index on
+	// (bill_id, ref) where ref isn't empty.
+	Ref             string     `json:"ref,omitempty"`
+	ApprovedBy      string     `json:"approvedBy,omitempty"` // manager who approved an amount over the per-item cap
+	AppliedRate     float64    `json:"appliedRate"`          // rate from Currency to the bill's currency, frozen at addition
+	RateAsOf        *time.Time `json:"rateAsOf,omitempty"`   // when AppliedRate was quoted
+	ConvertedAmount int64      `json:"convertedAmount"`      // Amount in the bill's currency (cents), frozen at addition
+	FXFee           int64      `json:"fxFee,omitempty"`      // FX markup on ConvertedAmount (cents), frozen at addition
+	EffectiveDate   *time.Time `json:"effectiveDate,omitempty"`
+	FullAmount      *int64     `json:"fullAmount,omitempty"` // pre-proration amount (cents); Amount is the prorated charge
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// NetAmount is the line item's effect on the bill total in the bill's currency
+// (cents): its converted amount plus any FX fee, negated for credits
+func (item LineItem) NetAmount() int64 {
+	if item.Type == LineItemTypeCredit {
+		return -item.ConvertedAmount
+	}
+	return item.ConvertedAmount + item.FXFee
+}
+
+// Clone returns a deep copy of the line item
+func (item LineItem) Clone() LineItem {
+	if item.Metadata != nil {
+		metadata := make(map[string]string, len(item.Metadata))
+		for key, value := range item.Metadata {
+			metadata[key] = value
+		}
+		item.Metadata = metadata
+	}
+	item.RateAsOf = clonePtr(item.RateAsOf)
+	item.EffectiveDate = clonePtr(item.EffectiveDate)
+	item.FullAmount = clonePtr(item.FullAmount)
+	return item
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
 }
 
 // CreateBillRequest represents the request to create a new bill
 type CreateBillRequest struct {
 	Currency          Currency `json:"currency"`
 	BillingPeriodDays int      `json:"billingPeriodDays"` // defaults to 30 if not specified
-}
-
-// CreateBillResponse represents the response from creating a bill
-type CreateBillResponse struct {
-	Bill Bill `json:"bill"`
+	Draft             bool     `json:"draft"`             // create as a draft that must be activated
+	Note              string   `json:"note"`              // optional, up to 1000 characters
+	TemplateID        string   `json:"templateId"`        // optional; adds the template's line items, currency defaults to its
+	Timezone          string   `json:"timezone"`          // optional IANA name, e.g. Asia/Tbilisi; defaults to UTC
+	// PeriodStart and PeriodEnd set the billing period explicitly instead of
+	// BillingPeriodDays from now; both or neither must be given
+	PeriodStart *time.Time `json:"periodStart"`
+	PeriodEnd   *time.Time `json:"periodEnd"`
 }
 
 // AddLineItemRequest represents the request to add a line item
 type AddLineItemRequest struct {
-	Description string  `json:"description"`
-	Amount      float64 `json:"amount"` // accept float for human-friendly input, store as cents
-	Currency    Currency `json:"currency"`
+	Description string            `json:"description"`
+	Amount      float64           `json:"amount"`   // accept float for human-friendly input, store as cents
+	Currency    Currency          `json:"currency"` // defaults to the bill's currency if not specified
+	Category    LineItemCategory  `json:"category"` // defaults to "other" if not specified
+	Type        LineItemType      `json:"type"`     // charge (default) or credit; amount is positive either way
+	Metadata    map[string]string `json:"metadata"` // optional, limited to 20 keys
+	Ref         string            `json:"ref"`      // optional external reference; a bill accepts each ref once
+	// Proratable charges Amount for the share of the billing period remaining from
+	// EffectiveDate (defaults to now)
+	EffectiveDate *time.Time `json:"effectiveDate"`
+	Proratable    bool       `json:"proratable"`
+	// Force adds the item even if it duplicates one added within the dedup window
+	Force bool `json:"force"`
+	// Approved is a manager override of the per-item amount cap; ApproverID names
+	// the manager and is required with it
+	Approved   bool   `json:"approved"`
+	ApproverID string `json:"approverId"`
+}
+
+// ReplaceLineItemsRequest represents the request to replace a bill's line items
+type ReplaceLineItemsRequest struct {
+	LineItems []AddLineItemRequest `json:"lineItems"` // the complete new set; empty clears the bill
+}
+
+// UpdateLineItemRequest represents a partial update of a line item. A nil field is
+// left as it is; a field that is present is applied, so "metadata": {} clears the
+// metadata while an omitted metadata keeps it.
+type UpdateLineItemRequest struct {
+	Description *string            `json:"description"`
+	Amount      *float64           `json:"amount"` // in the line item's currency
+	Currency    *Currency          `json:"currency"`
+	Category    *LineItemCategory  `json:"category"`
+	Metadata    *map[string]string `json:"metadata"` // replaces the whole map
+	// Approved and ApproverID approve a new amount or currency over the per-item
+	// amount cap, as for AddLineItemRequest
+	Approved   bool   `json:"approved"`
+	ApproverID string `json:"approverId"`
+}
+
+// ActivateBillRequest represents the request to activate a draft bill
+type ActivateBillRequest struct {
+	BillingPeriodDays int `json:"billingPeriodDays"` // defaults to 30 if not specified
 }
 
-// AddLineItemResponse represents the response from adding a line item
-type AddLineItemResponse struct {
-	Bill Bill `json:"bill"`
+// UpdateNoteRequest represents the request to set a bill's note
+type UpdateNoteRequest struct {
+	Note string `json:"note"` // empty clears the note
+}
+
+// ChangeCurrencyRequest represents the request to change a bill's currency
+type ChangeCurrencyRequest struct {
+	Currency Currency `json:"currency"`
 }
 
 // CloseBillRequest represents the request to close a bill
 type CloseBillRequest struct {
-	BillID string `query:"billId"`
+	BillID     string `query:"billId"`
+	AllowEmpty bool   `json:"allowEmpty"` // close even if the bill has no line items
+	Reason     string `json:"reason"`     // optional, why the bill closed early (churn, dispute, manual, ...)
+	ApprovedBy string `json:"approvedBy"` // approves closing a bill above the auto-close threshold
+	Idempotent bool   `json:"idempotent"` // closing an already closed bill returns it rather than failing
 }
 
-// CloseBillResponse represents the response from closing a bill
-type CloseBillResponse struct {
-	Bill Bill `json:"bill"`
+// ForceCloseBillRequest represents an operator's request to close a bill at once
+type ForceCloseBillRequest struct {
+	Reason string `json:"reason"` // optional, recorded as the workflow's close reason
 }
 
-// GetBillRequest represents the request to get a bill
-type GetBillRequest struct {
-	BillID string `query:"billId"`
+// RecordPaymentRequest represents a payment received against a closed bill
+type RecordPaymentRequest struct {
+	Amount float64 `json:"amount"` // in the bill's currency
+}
+
+// ApproveBillRequest represents the request to approve closing a bill above the
+// auto-close threshold
+type ApproveBillRequest struct {
+	Approver string `json:"approver"`
+}
+
+// SplitBillRequest represents the request to move line items to a new bill
+type SplitBillRequest struct {
+	LineItemIDs []string `json:"lineItemIds"`
+}
+
+// MergeBillsRequest represents the request to merge bills into a target bill
+type MergeBillsRequest struct {
+	SourceBillIDs []string `json:"sourceBillIds"`
 }
 
-// GetBillResponse represents the response from getting a bill
-type GetBillResponse struct {
-	Bill Bill `json:"bill"`
+// CloseBillsRequest represents the request to close a batch of bills
+type CloseBillsRequest struct {
+	BillIDs    []string `json:"billIds"`
+	AllowEmpty bool     `json:"allowEmpty"` // close bills even if they have no line items
+	Reason     string   `json:"reason"`     // optional, recorded on each bill's workflow
+	ApprovedBy string   `json:"approvedBy"` // approves closing bills above the auto-close threshold
+}
+
+// CloseOutcome is what happened to one bill in a batch close
+type CloseOutcome string
+
+const (
+	CloseOutcomeClosed        CloseOutcome = "closed"
+	CloseOutcomeAlreadyClosed CloseOutcome = "already_closed"
+	CloseOutcomeNotFound      CloseOutcome = "not_found"
+	CloseOutcomeFailed        CloseOutcome = "failed"
+)
+
+// CloseBillResult is the outcome of closing one bill in a batch
+type CloseBillResult struct {
+	BillID  string       `json:"billId"`
+	Outcome CloseOutcome `json:"outcome"`
+	Bill    *Bill        `json:"bill,omitempty"`  // the closed bill; unset when not found or failed
+	Error   string       `json:"error,omitempty"` // set when the outcome is failed
+}
+
+// LineItemImportReport is the outcome of importing line items from a CSV, row by row
+type LineItemImportReport struct {
+	Imported int                 `json:"imported"`
+	Failed   int                 `json:"failed"`
+	Rows     []LineItemImportRow `json:"rows"`
+}
+
+// LineItemImportRow is the outcome of one CSV row
+type LineItemImportRow struct {
+	Row        int    `json:"row"` // record number in the CSV, the header being record 1
+	BillID     string `json:"billId"`
+	LineItemID string `json:"lineItemId,omitempty"` // set when the item was added
+	Error      string `json:"error,omitempty"`      // set when the row failed
+}
+
+// TotalCheck is the result of checking a bill's stored total against the sum of its
+// line items, the invariant every write keeps. A mismatch means the stored bill is
+// corrupt, e.g. from a conversion bug, and RecalculateTotal would change it.
+type TotalCheck struct {
+	Consistent    bool  `json:"consistent"`
+	StoredTotal   int64 `json:"storedTotal"`   // in cents
+	ComputedTotal int64 `json:"computedTotal"` // in cents
+}
+
+// OverdueSummary totals one customer's overdue bills in one currency, for
+// collections. Bills in different currencies are summarized apart.
+type OverdueSummary struct {
+	CustomerID   string   `json:"customerId"` // the billed org
+	Currency     Currency `json:"currency"`
+	OverdueCount int      `json:"overdueCount"`
+	OverdueTotal int64    `json:"overdueTotal"` // balance still due, in cents
+}
+
+// GetBillRequest represents the request to get a bill
+type GetBillRequest struct {
+	IncludeDeleted   bool `query:"includeDeleted"`
+	IncludeBreakdown bool `query:"includeBreakdown"`
+	IncludeActions   bool `query:"includeActions"` // list the operations the bill currently allows
+	IncludeEvents    bool `query:"includeEvents"`  // attach the bill's most recent events
+	Verify           bool `query:"verify"`         // check the stored total against the line items
+	EventLimit       int  `query:"eventLimit"`     // how many events with includeEvents; defaults to 10, at most 50
+	// DisplayCurrency adds the bill's amounts converted into this currency, for display only
+	DisplayCurrency Currency `query:"displayCurrency"`
 }
 
 // ListBillsRequest represents the request to list bills
 type ListBillsRequest struct {
-	Status string `query:"status"`
+	Status         string   `query:"status"` // comma-separated list, matched as OR
+	Currency       Currency `query:"currency"`
+	IncludeDeleted bool     `query:"includeDeleted"`
+	MinTotal       float64  `query:"minTotal"`     // only bills totalling at least this much, in each bill's own currency
+	HasLineItems   bool     `query:"hasLineItems"` // only bills with at least one line item
+	Summary        bool     `query:"summary"`      // return BillSummary entries instead of full bills
+	Limit          int      `query:"limit"`        // page size; defaults to 50 with a cursor, at most 200
+	Cursor         string   `query:"cursor"`       // NextCursor of the previous page
+	// DisplayCurrency adds each bill's amounts converted into this currency, for display only
+	DisplayCurrency Currency `query:"displayCurrency"`
+}
+
+// ConvertCurrencyRequest represents the request to preview a currency conversion
+type ConvertCurrencyRequest struct {
+	Amount float64  `query:"amount"` // accept float for human-friendly input, converted as cents
+	From   Currency `query:"from"`
+	To     Currency `query:"to"`
+}
+
+// ImportBillRequest represents the admin request to import a historical bill as it
+// stands, bypassing the open -> close lifecycle
+type ImportBillRequest struct {
+	OrgID     string           `json:"orgId"`
+	Currency  Currency         `json:"currency"`
+	Status    BillStatus       `json:"status"` // only closed bills can be imported
+	CreatedAt time.Time        `json:"createdAt"`
+	ClosedAt  *time.Time       `json:"closedAt"`
+	Note      string           `json:"note"`
+	LineItems []ImportLineItem `json:"lineItems"`
+	Total     float64          `json:"total"` // must match the sum of the converted line items
+}
+
+// ImportLineItem is a historical line item, converted at the rate it was billed at
+type ImportLineItem struct {
+	AddLineItemRequest
+	AppliedRate float64    `json:"appliedRate"` // to the bill's currency; the current rate if zero
+	CreatedAt   *time.Time `json:"createdAt"`   // defaults to the bill's CreatedAt
+}
+
+// SetExchangeRateRequest represents the admin request to override a currency's rate
+type SetExchangeRateRequest struct {
+	RateToUSD float64 `json:"rateToUSD"` // USD value of one unit
+}
+
+// CurrencyInfo describes a supported currency and its current USD rate
+type CurrencyInfo struct {
+	Code          Currency  `json:"code"`
+	DecimalPlaces int       `json:"decimalPlaces"`
+	RateToUSD     float64   `json:"rateToUSD"` // USD value of one unit
+	RateAsOf      time.Time `json:"rateAsOf"`
+}
+
+// DisplayAmounts are a bill's amounts converted into a reporting currency. They're
+// computed when the bill is read, at the current rate, and never stored.
+type DisplayAmounts struct {
+	Currency    Currency          `json:"currency"`
+	Rate        float64           `json:"rate"` // from the bill's currency
+	RateAsOf    time.Time         `json:"rateAsOf"`
+	TotalAmount int64             `json:"totalAmount"` // in cents of Currency
+	LineItems   []DisplayLineItem `json:"lineItems,omitempty"`
+}
+
+// DisplayLineItem is a line item's effect on the total in the display currency
+type DisplayLineItem struct {
+	ID        string `json:"id"`
+	NetAmount int64  `json:"netAmount"` // in cents of the display currency; negative for credits
 }
 
-// ListBillsResponse represents the response from listing bills
-type ListBillsResponse struct {
-	Bills []Bill `json:"bills"`
+// Conversion is the result of converting an amount between currencies
+type Conversion struct {
+	From            Currency  `json:"from"`
+	To              Currency  `json:"to"`
+	Rate            float64   `json:"rate"`
+	RateAsOf        time.Time `json:"rateAsOf"`
+	Amount          int64     `json:"amount"`          // in cents of From
+	ConvertedAmount int64     `json:"convertedAmount"` // in cents of To
 }
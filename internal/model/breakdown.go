@@ -0,0 +1,25 @@
+package model
+
+// BillBreakdown is the financial breakdown of a bill, in the bill's currency (cents).
+// GrandTotal is always Subtotal - CreditTotal + FXFees - DiscountTotal + TaxTotal.
+type BillBreakdown struct {
+	Currency      Currency        `json:"currency"`
+	Lines         []BreakdownLine `json:"lines"`   // charges
+	Credits       []BreakdownLine `json:"credits"` // credits, listed apart from charges
+	Subtotal      int64           `json:"subtotal"`
+	CreditTotal   int64           `json:"creditTotal"`
+	FXFees        int64           `json:"fxFees"` // FX markup on converted charges
+	DiscountTotal int64           `json:"discountTotal"`
+	TaxTotal      int64           `json:"taxTotal"`
+	GrandTotal    int64           `json:"grandTotal"`
+}
+
+// BreakdownLine is a single line item's contribution to a breakdown
+type BreakdownLine struct {
+	LineItemID      string           `json:"lineItemId"`
+	Description     string           `json:"description"`
+	Category        LineItemCategory `json:"category"`
+	Amount          int64            `json:"amount"` // in the line item's currency (cents)
+	Currency        Currency         `json:"currency"`
+	ConvertedAmount int64            `json:"convertedAmount"` // in the bill's currency (cents)
+}
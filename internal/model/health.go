@@ -0,0 +1,15 @@
+package model
+
+// HealthStatus represents the health of the service or one of its dependencies
+type HealthStatus string
+
+const (
+	HealthStatusOK          HealthStatus = "ok"
+	HealthStatusUnavailable HealthStatus = "unavailable"
+)
+
+// HealthResponse represents the response from the health check
+type HealthResponse struct {
+	Status       HealthStatus      `json:"status"`
+	Dependencies map[string]string `json:"dependencies"`
+}
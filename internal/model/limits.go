@@ -0,0 +1,23 @@
+package model
+
+// Input size limits, shared by request validation and the service so the two
+// can't drift apart. Lengths count characters, not bytes.
+const (
+	// MaxDescriptionLength caps a line item's description
+	MaxDescriptionLength = 500
+
+	// MaxNoteLength caps a bill's free-text note
+	MaxNoteLength = 1000
+
+	// MaxMetadataKeys caps how many metadata entries a line item may carry
+	MaxMetadataKeys = 20
+
+	// MaxMetadataKeyLength caps a metadata key
+	MaxMetadataKeyLength = 40
+
+	// MaxMetadataValueLength caps a metadata value
+	MaxMetadataValueLength = 500
+
+	// MaxRequestBodyBytes caps the size of a raw request body
+	MaxRequestBodyBytes = 1 << 20
+)
@@ -0,0 +1,17 @@
+package model
+
+import "math"
+
+// ToCents converts an amount in major units, the float form requests carry, to
+// cents, rounding to the nearest cent. Amounts are stored and summed in cents only,
+// so float imprecision never reaches a total.
+func ToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// AmountsEqual reports whether two amounts in major units are the same amount of
+// money, that is whether they're equal to the cent. Float results that differ only
+// by rounding noise, like 0.1+0.2 and 0.3, are equal; amounts a cent apart aren't.
+func AmountsEqual(a, b float64) bool {
+	return ToCents(a) == ToCents(b)
+}
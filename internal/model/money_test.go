@@ -0,0 +1,45 @@
+package model
+
+import "testing"
+
+func TestAmountsEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b float64
+		want bool
+	}{
+		{name: "float sum against its decimal", a: 0.1 + 0.2, b: 0.3, want: true},
+		{name: "repeated additions", a: 0.1 + 0.1 + 0.1 + 0.1 + 0.1 + 0.1 + 0.1 + 0.1 + 0.1 + 0.1, b: 1.0, want: true},
+		{name: "converted at a rate", a: 100 * 0.37, b: 37.0, want: true},
+		{name: "large amounts", a: 1e9 + 0.1 + 0.2, b: 1e9 + 0.3, want: true},
+		{name: "a cent apart", a: 0.30, b: 0.31, want: false},
+		{name: "negative amounts", a: -(0.1 + 0.2), b: -0.3, want: true},
+		{name: "opposite signs", a: 0.3, b: -0.3, want: false},
+		{name: "zero", a: 0, b: 0.001, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AmountsEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("AmountsEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := AmountsEqual(tt.b, tt.a); got != tt.want {
+				t.Errorf("AmountsEqual(%v, %v) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToCents(t *testing.T) {
+	for amount, want := range map[float64]int64{
+		0.1 + 0.2: 30,
+		19.99:     1999,
+		1.005:     100, // 1.005 is stored as 1.00499999...
+		0.125:     13,
+		-2.50:     -250,
+	} {
+		if got := ToCents(amount); got != want {
+			t.Errorf("ToCents(%v) = %d, want %d", amount, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// ProcessorEventFeeCreated is the processor event type that carries a new fee
+const ProcessorEventFeeCreated = "fee.created"
+
+// ProcessorEvent is a webhook notification from the payment processor. Only
+// fee.created events turn into line items; other types are acknowledged and ignored.
+type ProcessorEvent struct {
+	ID   string             `json:"id"`
+	Type string             `json:"type"`
+	Data ProcessorEventData `json:"data"`
+}
+
+// ProcessorEventData wraps the object an event is about
+type ProcessorEventData struct {
+	Object ProcessorFee `json:"object"`
+}
+
+// ProcessorFee is a fee the processor charged, as carried by a fee.created event
+type ProcessorFee struct {
+	ID          string `json:"id"`
+	Amount      int64  `json:"amount"`   // in minor units (cents)
+	Currency    string `json:"currency"` // ISO code, usually lower-case
+	Description string `json:"description"`
+	Reference   string `json:"reference"` // external reference linked to a bill
+}
+
+// ProcessorRef links a processor's external reference to the bill its fees go to
+type ProcessorRef struct {
+	Ref       string    `json:"ref"`
+	OrgID     string    `json:"orgId"`
+	BillID    string    `json:"billId"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// LinkProcessorRefRequest represents the request to link an external reference to a bill
+type LinkProcessorRefRequest struct {
+	OrgID  string `json:"orgId"`
+	BillID string `json:"billId"`
+}
+
+// ProcessorWebhookResult reports what an ingested processor event did
+type ProcessorWebhookResult struct {
+	EventID    string `json:"eventId"`
+	Applied    bool   `json:"applied"`   // a line item was added
+	Duplicate  bool   `json:"duplicate"` // the fee was already on the bill, from an earlier delivery
+	BillID     string `json:"billId,omitempty"`
+	LineItemID string `json:"lineItemId,omitempty"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Receipt is the finalized snapshot of a bill as it closed. Receipts are stored apart
+// from bills and never change: reopening a bill leaves its receipts as they were,
+// and closing it again issues a new one.
+type Receipt struct {
+	ID            string   `json:"id"`
+	BillID        string   `json:"billId"`
+	InvoiceNumber string   `json:"invoiceNumber,omitempty"` // the bill's, the same on every receipt
+	OrgID         string   `json:"orgId"`                   // the customer billed
+	Sequence      int      `json:"sequence"`                // 1 for the bill's first close, 2 after a reopen, ...
+	Currency      Currency `json:"currency"`
+	Timezone      string   `json:"timezone,omitempty"`
+	// Amounts are in the bill's currency (cents); Total is Subtotal - CreditTotal +
+	// FXFees - DiscountTotal + TaxTotal, the bill's total at close
+	Subtotal      int64      `json:"subtotal"`
+	CreditTotal   int64      `json:"creditTotal"`
+	FXFees        int64      `json:"fxFees"`
+	DiscountTotal int64      `json:"discountTotal"`
+	TaxTotal      int64      `json:"taxTotal"`
+	Total         int64      `json:"total"`
+	LineItems     []LineItem `json:"lineItems"`
+	ApprovedBy    string     `json:"approvedBy,omitempty"` // who approved a close above the auto-close threshold
+	ClosedAt      time.Time  `json:"closedAt"`
+}
+
+// Location returns the time zone of the receipt's bill
+func (r *Receipt) Location() *time.Location {
+	return displayLocation(r.Timezone)
+}
+
+// Clone returns a deep copy of the receipt
+func (r *Receipt) Clone() *Receipt {
+	clone := *r
+	clone.LineItems = make([]LineItem, len(r.LineItems))
+	for i := range r.LineItems {
+		clone.LineItems[i] = r.LineItems[i].Clone()
+	}
+	return &clone
+}
@@ -0,0 +1,24 @@
+package model
+
+// WorkflowBillState is the billing period workflow's running view of a bill
+type WorkflowBillState struct {
+	Status        string `json:"status"`      // open, closing, closed or close-failed
+	TotalAmount   int64  `json:"totalAmount"` // in the bill's currency (cents)
+	LineItemCount int    `json:"lineItemCount"`
+}
+
+// Discrepancy is a field on which the workflow and the stored bill disagree
+type Discrepancy struct {
+	Field    string `json:"field"` // status, totalAmount or lineItemCount
+	Bill     string `json:"bill"`
+	Workflow string `json:"workflow"`
+}
+
+// ReconciliationReport compares a bill with its workflow's state
+type ReconciliationReport struct {
+	BillID        string            `json:"billId"`
+	InSync        bool              `json:"inSync"`
+	Bill          WorkflowBillState `json:"bill"` // the stored bill, in the workflow's terms
+	Workflow      WorkflowBillState `json:"workflow"`
+	Discrepancies []Discrepancy     `json:"discrepancies,omitempty"`
+}
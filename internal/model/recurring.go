@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// RecurringTemplateStatus represents the state of a recurring bill template
+type RecurringTemplateStatus string
+
+const (
+	RecurringTemplateActive    RecurringTemplateStatus = "active"
+	RecurringTemplatePaused    RecurringTemplateStatus = "paused"    // skips renewals until resumed
+	RecurringTemplateCancelled RecurringTemplateStatus = "cancelled" // terminal, no further bills
+)
+
+// RecurringBillTemplate describes a bill that is materialized on a repeating cadence
+type RecurringBillTemplate struct {
+	ID           string                  `json:"id"`
+	OrgID        string                  `json:"orgId"`
+	CustomerID   string                  `json:"customerId"`
+	Currency     Currency                `json:"currency"`
+	LineItems    []AddLineItemRequest    `json:"lineItems"`
+	IntervalDays int                     `json:"intervalDays"`
+	Status       RecurringTemplateStatus `json:"status"`
+	LastBillID   string                  `json:"lastBillId,omitempty"`
+	BillCount    int                     `json:"billCount"` // bills materialized so far
+	CreatedAt    time.Time               `json:"createdAt"`
+	UpdatedAt    time.Time               `json:"updatedAt"`
+}
+
+// CreateRecurringTemplateRequest represents the request to register a recurring bill template
+type CreateRecurringTemplateRequest struct {
+	CustomerID   string               `json:"customerId"`
+	Currency     Currency             `json:"currency"` // defaults to USD
+	LineItems    []AddLineItemRequest `json:"lineItems"`
+	IntervalDays int                  `json:"intervalDays"` // defaults to 30 if not specified
+}
+
+// RecurringTemplateResponse represents the response from a recurring template operation
+type RecurringTemplateResponse struct {
+	Template RecurringBillTemplate `json:"template"`
+}
+
+// MaterializeRecurringTemplateResponse represents the result of a scheduled renewal.
+// Bill is nil when the template is paused; Cancelled tells the scheduler to stop.
+type MaterializeRecurringTemplateResponse struct {
+	Bill      *Bill `json:"bill,omitempty"`
+	Cancelled bool  `json:"cancelled"`
+}
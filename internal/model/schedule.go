@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// BillingScheduleStatus represents the state of a billing schedule
+type BillingScheduleStatus string
+
+const (
+	BillingScheduleActive BillingScheduleStatus = "active"
+	BillingSchedulePaused BillingScheduleStatus = "paused" // skips period starts until resumed
+)
+
+// BillingSchedule opens a new billing period for a customer on the 1st of every
+// month. Each organization has at most one schedule per customer.
+type BillingSchedule struct {
+	ID         string                `json:"id"`
+	OrgID      string                `json:"orgId"`
+	CustomerID string                `json:"customerId"`
+	Currency   Currency              `json:"currency"`
+	Status     BillingScheduleStatus `json:"status"`
+	LastBillID string                `json:"lastBillId,omitempty"`
+	BillCount  int                   `json:"billCount"` // periods started so far
+	CreatedAt  time.Time             `json:"createdAt"`
+	UpdatedAt  time.Time             `json:"updatedAt"`
+}
+
+// CreateBillingScheduleRequest represents the request to schedule monthly billing periods for a customer
+type CreateBillingScheduleRequest struct {
+	CustomerID string   `json:"customerId"`
+	Currency   Currency `json:"currency"` // defaults to USD
+}
+
+// BillingScheduleResponse represents the response from a billing schedule operation
+type BillingScheduleResponse struct {
+	Schedule BillingSchedule `json:"schedule"`
+}
+
+// StartScheduledPeriodResponse represents the result of a scheduled period start.
+// Skipped is set, with no bill, when the schedule is paused or the previous
+// period's bill is still open.
+type StartScheduledPeriodResponse struct {
+	Bill    *Bill `json:"bill,omitempty"`
+	Skipped bool  `json:"skipped"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// BillTemplate is a registered set of default line items, such as a setup fee and a
+// base subscription, added to a bill created from it
+type BillTemplate struct {
+	ID        string               `json:"id"`
+	OrgID     string               `json:"orgId"`
+	Name      string               `json:"name"`
+	Currency  Currency             `json:"currency"` // bills created from the template use it
+	LineItems []AddLineItemRequest `json:"lineItems"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+// CreateBillTemplateRequest represents the request to register a bill template
+type CreateBillTemplateRequest struct {
+	Name      string               `json:"name"`
+	Currency  Currency             `json:"currency"` // defaults to USD
+	LineItems []AddLineItemRequest `json:"lineItems"`
+}
+
+// BillTemplateResponse represents the response from a bill template operation
+type BillTemplateResponse struct {
+	Template BillTemplate `json:"template"`
+}
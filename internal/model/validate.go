@@ -0,0 +1,266 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"fees-api/internal/validation"
+)
+
+// Request shape checks: required fields, number ranges and allowed values. Encore
+// runs these before an endpoint is called; rules that depend on stored state stay
+// in the service.
+
+// Validate checks the fields of a create bill request
+func (r CreateBillRequest) Validate() error {
+	var v validation.Validator
+	validation.OneOf(&v, "currency", r.Currency, SupportedCurrencies)
+	v.NonNegative("billingPeriodDays", float64(r.BillingPeriodDays))
+	v.Check((r.PeriodStart == nil) == (r.PeriodEnd == nil), "periodEnd", "must be given together with periodStart")
+	if r.PeriodStart != nil && r.PeriodEnd != nil {
+		v.Check(r.PeriodEnd.After(*r.PeriodStart), "periodEnd", "must be after periodStart")
+	}
+	v.Check(!r.Draft || r.PeriodStart == nil, "periodStart", "drafts get their period on activation")
+	v.MaxLength("note", r.Note, MaxNoteLength)
+	_, err := LoadTimezone(r.Timezone)
+	v.Check(err == nil, "timezone", "must be an IANA time zone name")
+	return v.Err()
+}
+
+// Validate checks the fields of an add line item request
+func (r AddLineItemRequest) Validate() error {
+	var v validation.Validator
+	r.validate(&v, "")
+	return v.Err()
+}
+
+func (r AddLineItemRequest) validate(v *validation.Validator, path string) {
+	v.Required(validation.Path(path, "description"), r.Description)
+	v.MaxLength(validation.Path(path, "description"), r.Description, MaxDescriptionLength)
+	v.Positive(validation.Path(path, "amount"), r.Amount)
+	// An omitted currency defaults to the bill's; only one that's given is checked
+	validation.OneOf(v, validation.Path(path, "currency"), r.Currency, SupportedCurrencies)
+	validation.OneOf(v, validation.Path(path, "category"), r.Category, LineItemCategories)
+	validation.OneOf(v, validation.Path(path, "type"), r.Type, LineItemTypes)
+	v.Check(!r.Approved || r.ApproverID != "", validation.Path(path, "approverId"), "is required when approved")
+	validateMetadata(v, validation.Path(path, "metadata"), r.Metadata)
+}
+
+// validateMetadata checks the number and size of line item metadata entries
+func validateMetadata(v *validation.Validator, path string, metadata map[string]string) {
+	v.Check(len(metadata) <= MaxMetadataKeys, path, fmt.Sprintf("must have at most %d keys", MaxMetadataKeys))
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, value := validation.Path(path, key), metadata[key]
+		v.Check(key != "", path, "keys must not be empty")
+		v.Check(utf8.RuneCountInString(key) <= MaxMetadataKeyLength, field, fmt.Sprintf("key must be at most %d characters", MaxMetadataKeyLength))
+		v.MaxLength(field, value, MaxMetadataValueLength)
+	}
+}
+
+// Validate checks the fields an update sets; omitted fields aren't checked
+func (r UpdateLineItemRequest) Validate() error {
+	var v validation.Validator
+	if r.Description != nil {
+		v.Required("description", *r.Description)
+		v.MaxLength("description", *r.Description, MaxDescriptionLength)
+	}
+	if r.Amount != nil {
+		v.Positive("amount", *r.Amount)
+	}
+	if r.Currency != nil {
+		v.Required("currency", string(*r.Currency))
+		validation.OneOf(&v, "currency", *r.Currency, SupportedCurrencies)
+	}
+	if r.Category != nil {
+		validation.OneOf(&v, "category", *r.Category, LineItemCategories)
+	}
+	if r.Metadata != nil {
+		validateMetadata(&v, "metadata", *r.Metadata)
+	}
+	v.Check(!r.Approved || r.ApproverID != "", "approverId", "is required when approved")
+	return v.Err()
+}
+
+// Validate checks the length of a note
+func (r UpdateNoteRequest) Validate() error {
+	var v validation.Validator
+	v.MaxLength("note", r.Note, MaxNoteLength)
+	return v.Err()
+}
+
+// Validate checks every line item of a replace request
+func (r ReplaceLineItemsRequest) Validate() error {
+	var v validation.Validator
+	for i, item := range r.LineItems {
+		item.validate(&v, validation.Index("lineItems", i))
+	}
+	return v.Err()
+}
+
+// Validate checks the fields of an activate request
+func (r ActivateBillRequest) Validate() error {
+	var v validation.Validator
+	v.NonNegative("billingPeriodDays", float64(r.BillingPeriodDays))
+	return v.Err()
+}
+
+// Validate checks the fields of a close approval
+func (r ApproveBillRequest) Validate() error {
+	var v validation.Validator
+	v.Required("approver", r.Approver)
+	return v.Err()
+}
+
+// Validate checks the fields of a payment
+func (r RecordPaymentRequest) Validate() error {
+	var v validation.Validator
+	v.Positive("amount", r.Amount)
+	return v.Err()
+}
+
+// Validate checks the fields of a change currency request
+func (r ChangeCurrencyRequest) Validate() error {
+	var v validation.Validator
+	v.Required("currency", string(r.Currency))
+	validation.OneOf(&v, "currency", r.Currency, SupportedCurrencies)
+	return v.Err()
+}
+
+// Validate checks the fields of a bulk close request
+func (r CloseBillsRequest) Validate() error {
+	var v validation.Validator
+	v.Check(len(r.BillIDs) > 0, "billIds", "is required")
+	for i, id := range r.BillIDs {
+		v.Required(validation.Index("billIds", i), id)
+	}
+	return v.Err()
+}
+
+// Validate checks the fields of a get request
+func (r GetBillRequest) Validate() error {
+	var v validation.Validator
+	validation.OneOf(&v, "displayCurrency", r.DisplayCurrency, SupportedCurrencies)
+	return v.Err()
+}
+
+// Validate checks the fields of a list request
+func (r ListBillsRequest) Validate() error {
+	var v validation.Validator
+	validation.OneOf(&v, "currency", r.Currency, SupportedCurrencies)
+	validation.OneOf(&v, "displayCurrency", r.DisplayCurrency, SupportedCurrencies)
+	v.NonNegative("minTotal", r.MinTotal)
+	v.NonNegative("limit", float64(r.Limit))
+	return v.Err()
+}
+
+// Validate checks the fields of a conversion request
+func (r ConvertCurrencyRequest) Validate() error {
+	var v validation.Validator
+	v.NonNegative("amount", r.Amount)
+	v.Required("from", string(r.From))
+	validation.OneOf(&v, "from", r.From, SupportedCurrencies)
+	v.Required("to", string(r.To))
+	validation.OneOf(&v, "to", r.To, SupportedCurrencies)
+	return v.Err()
+}
+
+// Validate checks the fields of a bill import
+func (r ImportBillRequest) Validate() error {
+	var v validation.Validator
+	v.Required("orgId", r.OrgID)
+	v.Required("currency", string(r.Currency))
+	validation.OneOf(&v, "currency", r.Currency, SupportedCurrencies)
+	validation.OneOf(&v, "status", r.Status, []BillStatus{BillStatusClosed})
+	v.MaxLength("note", r.Note, MaxNoteLength)
+	v.Check(!r.CreatedAt.IsZero(), "createdAt", "is required")
+	v.Check(r.ClosedAt != nil, "closedAt", "is required")
+	if r.ClosedAt != nil {
+		v.Check(!r.ClosedAt.Before(r.CreatedAt), "closedAt", "must not be before createdAt")
+	}
+	v.Check(len(r.LineItems) > 0, "lineItems", "is required")
+	for i, item := range r.LineItems {
+		path := validation.Index("lineItems", i)
+		item.validate(&v, path)
+		v.NonNegative(validation.Path(path, "appliedRate"), item.AppliedRate)
+	}
+	return v.Err()
+}
+
+// Validate checks the fields of an exchange rate override
+func (r SetExchangeRateRequest) Validate() error {
+	var v validation.Validator
+	v.Positive("rateToUSD", r.RateToUSD)
+	return v.Err()
+}
+
+// Validate checks the fields of a recurring template request
+func (r CreateRecurringTemplateRequest) Validate() error {
+	var v validation.Validator
+	validation.OneOf(&v, "currency", r.Currency, SupportedCurrencies)
+	v.NonNegative("intervalDays", float64(r.IntervalDays))
+	v.Check(len(r.LineItems) > 0, "lineItems", "is required")
+	for i, item := range r.LineItems {
+		item.validate(&v, validation.Index("lineItems", i))
+	}
+	return v.Err()
+}
+
+// Validate checks the fields of a bill template request
+func (r CreateBillTemplateRequest) Validate() error {
+	var v validation.Validator
+	v.Required("name", r.Name)
+	validation.OneOf(&v, "currency", r.Currency, SupportedCurrencies)
+	v.Check(len(r.LineItems) > 0, "lineItems", "is required")
+	for i, item := range r.LineItems {
+		item.validate(&v, validation.Index("lineItems", i))
+	}
+	return v.Err()
+}
+
+// Validate checks the fields of a billing schedule request
+func (r CreateBillingScheduleRequest) Validate() error {
+	var v validation.Validator
+	v.Required("customerId", r.CustomerID)
+	validation.OneOf(&v, "currency", r.Currency, SupportedCurrencies)
+	return v.Err()
+}
+
+// Validate checks the fields of a split request
+func (r SplitBillRequest) Validate() error {
+	var v validation.Validator
+	v.Check(len(r.LineItemIDs) > 0, "lineItemIds", "must list at least one line item")
+	seen := make(map[string]bool, len(r.LineItemIDs))
+	for i, id := range r.LineItemIDs {
+		v.Required(validation.Index("lineItemIds", i), id)
+		v.Check(id == "" || !seen[id], validation.Index("lineItemIds", i), "is listed twice")
+		seen[id] = true
+	}
+	return v.Err()
+}
+
+// Validate checks the fields of a merge request
+func (r MergeBillsRequest) Validate() error {
+	var v validation.Validator
+	v.Check(len(r.SourceBillIDs) > 0, "sourceBillIds", "must list at least one bill")
+	seen := make(map[string]bool, len(r.SourceBillIDs))
+	for i, id := range r.SourceBillIDs {
+		v.Required(validation.Index("sourceBillIds", i), id)
+		v.Check(id == "" || !seen[id], validation.Index("sourceBillIds", i), "is listed twice")
+		seen[id] = true
+	}
+	return v.Err()
+}
+
+// Validate checks the fields of a processor reference link
+func (r LinkProcessorRefRequest) Validate() error {
+	var v validation.Validator
+	v.Required("orgId", r.OrgID)
+	v.Required("billId", r.BillID)
+	return v.Err()
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"fees-api/internal/validation"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestDecodeAndValidateAddLineItem(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string // fields with violations, in order; nil means valid
+	}{
+		{
+			name: "valid",
+			body: `{"description": "Fee", "amount": 10.5, "currency": "USD"}`,
+		},
+		{
+			name:       "amount sent as a string",
+			body:       `{"description": "Fee", "amount": "10.50", "currency": "USD"}`,
+			wantFields: []string{"amount"},
+		},
+		{
+			name:       "description sent as a number",
+			body:       `{"description": 5, "amount": 10, "currency": "USD"}`,
+			wantFields: []string{"description"},
+		},
+		{
+			name:       "missing fields",
+			body:       `{}`,
+			wantFields: []string{"description", "amount"},
+		},
+		{
+			name: "currency omitted for the bill's",
+			body: `{"description": "Fee", "amount": 10}`,
+		},
+		{
+			name:       "unsupported currency and category",
+			body:       `{"description": "Fee", "amount": 10, "currency": "EUR", "category": "misc"}`,
+			wantFields: []string{"currency", "category"},
+		},
+		{
+			name: "lower-case currency",
+			body: `{"description": "Fee", "amount": 10, "currency": "usd"}`,
+		},
+		{
+			name: "padded mixed-case currency",
+			body: `{"description": "Fee", "amount": 10, "currency": " Gel "}`,
+		},
+		{
+			name:       "currency sent as a number",
+			body:       `{"description": "Fee", "amount": 10, "currency": 840}`,
+			wantFields: []string{"currency"},
+		},
+		{
+			name:       "negative amount",
+			body:       `{"description": "Fee", "amount": -1, "currency": "GEL"}`,
+			wantFields: []string{"amount"},
+		},
+		{
+			name: "valid credit",
+			body: `{"description": "Refund", "amount": 5, "currency": "USD", "type": "credit"}`,
+		},
+		{
+			name:       "negative credit",
+			body:       `{"description": "Refund", "amount": -5, "currency": "USD", "type": "credit"}`,
+			wantFields: []string{"amount"},
+		},
+		{
+			name:       "unknown type",
+			body:       `{"description": "Fee", "amount": 5, "currency": "USD", "type": "debit"}`,
+			wantFields: []string{"type"},
+		},
+		{
+			name:       "description too long",
+			body:       `{"description": "` + strings.Repeat("x", MaxDescriptionLength+1) + `", "amount": 10, "currency": "USD"}`,
+			wantFields: []string{"description"},
+		},
+		{
+			name: "description at the limit in multi-byte characters",
+			body: `{"description": "` + strings.Repeat("ლ", MaxDescriptionLength) + `", "amount": 10, "currency": "USD"}`,
+		},
+		{
+			name:       "metadata value too long",
+			body:       `{"description": "Fee", "amount": 10, "currency": "USD", "metadata": {"ref": "` + strings.Repeat("v", MaxMetadataValueLength+1) + `"}}`,
+			wantFields: []string{"metadata.ref"},
+		},
+		{
+			name: "metadata key at the limit in multi-byte characters",
+			body: `{"description": "Fee", "amount": 10, "currency": "USD", "metadata": {"` + strings.Repeat("ქ", MaxMetadataKeyLength) + `": "v"}}`,
+		},
+		{
+			name:       "metadata key too long in multi-byte characters",
+			body:       `{"description": "Fee", "amount": 10, "currency": "USD", "metadata": {"` + strings.Repeat("ქ", MaxMetadataKeyLength+1) + `": "v"}}`,
+			wantFields: []string{"metadata." + strings.Repeat("ქ", MaxMetadataKeyLength+1)},
+		},
+		{
+			name:       "malformed JSON",
+			body:       `{"description": "Fee",`,
+			wantFields: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req AddLineItemRequest
+			err := validation.DecodeJSON([]byte(tt.body), &req)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if code := billingerrors.CodeOf(err); code != billingerrors.CodeInvalidArgument {
+				t.Fatalf("expected invalid_argument, got %q (%v)", code, err)
+			}
+			var fields []string
+			for _, violation := range billingerrors.FieldsOf(err) {
+				fields = append(fields, violation.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("expected violations for %v, got %v", tt.wantFields, fields)
+			}
+		})
+	}
+}
+
+func TestValidateNestedLineItems(t *testing.T) {
+	req := ReplaceLineItemsRequest{LineItems: []AddLineItemRequest{
+		{Description: "Fee", Amount: 10, Currency: CurrencyUSD},
+		{Description: "", Amount: 10, Currency: CurrencyUSD},
+	}}
+
+	violations := billingerrors.FieldsOf(req.Validate())
+	if len(violations) != 1 || violations[0].Field != "lineItems[1].description" {
+		t.Errorf("expected a violation for lineItems[1].description, got %+v", violations)
+	}
+}
+
+func TestValidateLengthLimits(t *testing.T) {
+	long := strings.Repeat("x", MaxDescriptionLength+1)
+	tests := []struct {
+		name string
+		req  interface{ Validate() error }
+	}{
+		{"update description", UpdateLineItemRequest{Description: &long}},
+		{"create note", CreateBillRequest{Note: strings.Repeat("n", MaxNoteLength+1)}},
+		{"update note", UpdateNoteRequest{Note: strings.Repeat("n", MaxNoteLength+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := billingerrors.FieldsOf(tt.req.Validate())
+			if len(violations) != 1 || !strings.HasPrefix(violations[0].Description, "must be at most") {
+				t.Errorf("expected a single length violation, got %+v", violations)
+			}
+		})
+	}
+}
+
+func TestCurrencyNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Currency
+	}{
+		{"USD", CurrencyUSD},
+		{"usd", CurrencyUSD},
+		{"Usd", CurrencyUSD},
+		{" GEL ", CurrencyGEL},
+		{"\tgel\n", CurrencyGEL},
+		{"eur", "EUR"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Currency(tt.input).Normalize(); got != tt.want {
+			t.Errorf("Currency(%q).Normalize() = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	var req CreateBillRequest
+	if err := validation.DecodeJSON([]byte(`{"currency": " usd"}`), &req); err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+	if req.Currency != CurrencyUSD {
+		t.Errorf("expected the decoded currency normalized to USD, got %q", req.Currency)
+	}
+}
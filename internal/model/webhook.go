@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// WebhookEndpoint represents an external HTTP callback registered for bill events
+type WebhookEndpoint struct {
+	ID         string    `json:"id"`
+	OrgID      string    `json:"orgId"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	Secret     string    `json:"-"` // never returned to clients
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// WebhookDeadLetter records a delivery that permanently failed
+type WebhookDeadLetter struct {
+	WebhookID string    `json:"webhookId"`
+	EventType string    `json:"eventType"`
+	BillID    string    `json:"billId"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError"`
+	FailedAt  time.Time `json:"failedAt"`
+}
+
+// RegisterWebhookRequest represents the request to register a webhook
+type RegisterWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"` // empty subscribes to all events
+	Secret     string   `json:"secret"`
+}
+
+// RegisterWebhookResponse represents the response from registering a webhook
+type RegisterWebhookResponse struct {
+	Webhook WebhookEndpoint `json:"webhook"`
+}
+
+// ListWebhooksResponse represents the response from listing webhooks
+type ListWebhooksResponse struct {
+	Webhooks []WebhookEndpoint `json:"webhooks"`
+}
@@ -0,0 +1,208 @@
+package presentation
+
+import (
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/pkg/money"
+)
+
+// BillView is the API representation of a bill. It adds display formatting on top
+// of the domain model so storage types never carry presentation concerns.
+type BillView struct {
+	ID                 string                `json:"id"`
+	OrgID              string                `json:"orgId"`
+	Status             model.BillStatus      `json:"status"`
+	Currency           model.Currency        `json:"currency"`
+	TotalAmount        int64                 `json:"totalAmount"` // in cents
+	TotalAmountDisplay string                `json:"totalAmountDisplay"`
+	FXFees             int64                 `json:"fxFees,omitempty"` // in cents, included in TotalAmount
+	LineItems          []LineItemView        `json:"lineItems,omitempty"`
+	Note               string                `json:"note,omitempty"`
+	Timezone           string                `json:"timezone"` // the zone every timestamp below is rendered in
+	CreatedAt          time.Time             `json:"createdAt"`
+	ClosedAt           *time.Time            `json:"closedAt,omitempty"`
+	DeletedAt          *time.Time            `json:"deletedAt,omitempty"`
+	PeriodStart        *time.Time            `json:"periodStart,omitempty"`
+	PeriodEnd          *time.Time            `json:"periodEnd,omitempty"`
+	FinalTotal         *int64                `json:"finalTotal,omitempty"` // in cents
+	FinalLineItemCount *int                  `json:"finalLineItemCount,omitempty"`
+	InvoiceNumber      string                `json:"invoiceNumber,omitempty"`
+	CloseApproval      *model.CloseApproval  `json:"closeApproval,omitempty"`
+	DueDate            *time.Time            `json:"dueDate,omitempty"`
+	AmountPaid         int64                 `json:"amountPaid,omitempty"`    // in cents
+	DaysUntilDue       *int                  `json:"daysUntilDue,omitempty"`  // negative once overdue; see SetPaymentStatus
+	PaymentStatus      PaymentStatus         `json:"paymentStatus,omitempty"` // see SetPaymentStatus
+	Display            *model.DisplayAmounts `json:"display,omitempty"`       // set when a display currency is requested
+	Version            int64                 `json:"version"`                 // see WatchBill
+}
+
+// BillSummaryView is the API representation of a bill summary
+type BillSummaryView struct {
+	ID                 string                `json:"id"`
+	Status             model.BillStatus      `json:"status"`
+	Currency           model.Currency        `json:"currency"`
+	TotalAmount        int64                 `json:"totalAmount"` // in cents
+	TotalAmountDisplay string                `json:"totalAmountDisplay"`
+	LineItemCount      int                   `json:"lineItemCount"`
+	Timezone           string                `json:"timezone"`
+	CreatedAt          time.Time             `json:"createdAt"`
+	ClosedAt           *time.Time            `json:"closedAt,omitempty"`
+	Display            *model.DisplayAmounts `json:"display,omitempty"` // set when a display currency is requested
+}
+
+// LineItemView is the API representation of a line item
+type LineItemView struct {
+	ID              string                 `json:"id"`
+	Sequence        int                    `json:"sequence"` // position in the order items were added
+	Description     string                 `json:"description"`
+	Amount          int64                  `json:"amount"` // in cents
+	AmountDisplay   string                 `json:"amountDisplay"`
+	Currency        model.Currency         `json:"currency"`
+	Category        model.LineItemCategory `json:"category"`
+	Type            model.LineItemType     `json:"type"`
+	Metadata        map[string]string      `json:"metadata,omitempty"`
+	Ref             string                 `json:"ref,omitempty"`
+	ApprovedBy      string                 `json:"approvedBy,omitempty"`
+	AppliedRate     float64                `json:"appliedRate"`
+	RateAsOf        *time.Time             `json:"rateAsOf,omitempty"`
+	ConvertedAmount int64                  `json:"convertedAmount"` // in the bill's currency (cents)
+	FXFee           int64                  `json:"fxFee,omitempty"` // in the bill's currency (cents)
+	EffectiveDate   *time.Time             `json:"effectiveDate,omitempty"`
+	FullAmount      *int64                 `json:"fullAmount,omitempty"` // pre-proration amount (cents)
+	CreatedAt       time.Time              `json:"createdAt"`
+}
+
+// BillAction is an operation a client may currently perform on a bill
+type BillAction string
+
+const (
+	ActionAddLineItem BillAction = "add_line_item"
+	ActionActivate    BillAction = "activate"
+	ActionClose       BillAction = "close"
+	ActionReopen      BillAction = "reopen"
+)
+
+// BillActions lists the operations the bill's status currently allows, for clients
+// to drive their workflows by. Deleted bills allow none until restored. There's no
+// void operation yet, so closed bills only offer reopen.
+func BillActions(bill *model.Bill) []BillAction {
+	if bill.DeletedAt != nil {
+		return []BillAction{}
+	}
+	switch bill.Status {
+	case model.BillStatusDraft:
+		return []BillAction{ActionAddLineItem, ActionActivate}
+	case model.BillStatusOpen:
+		return []BillAction{ActionAddLineItem, ActionClose}
+	case model.BillStatusClosed:
+		return []BillAction{ActionReopen}
+	default:
+		return []BillAction{}
+	}
+}
+
+// NewBillView maps a domain bill to its API representation. Timestamps are
+// rendered in the bill's time zone; the bill itself is left in UTC.
+func NewBillView(bill *model.Bill) BillView {
+	loc := bill.Location()
+	view := BillView{
+		ID:                 bill.ID,
+		OrgID:              bill.OrgID,
+		Status:             bill.Status,
+		Currency:           bill.Currency,
+		TotalAmount:        bill.TotalAmount,
+		TotalAmountDisplay: money.Format(bill.TotalAmount, bill.Currency),
+		FXFees:             bill.FXFees,
+		Note:               bill.Note,
+		Timezone:           loc.String(),
+		CreatedAt:          bill.CreatedAt.In(loc),
+		ClosedAt:           timeIn(bill.ClosedAt, loc),
+		DeletedAt:          timeIn(bill.DeletedAt, loc),
+		PeriodStart:        timeIn(bill.PeriodStart, loc),
+		PeriodEnd:          timeIn(bill.PeriodEnd, loc),
+		FinalTotal:         bill.FinalTotal,
+		FinalLineItemCount: bill.FinalLineItemCount,
+		InvoiceNumber:      bill.InvoiceNumber,
+		DueDate:            timeIn(bill.DueDate, loc),
+		AmountPaid:         bill.AmountPaid,
+		Version:            bill.Version,
+	}
+	if bill.CloseApproval != nil {
+		approval := *bill.CloseApproval
+		approval.ApprovedAt = approval.ApprovedAt.In(loc)
+		view.CloseApproval = &approval
+	}
+
+	if len(bill.LineItems) > 0 {
+		view.LineItems = make([]LineItemView, len(bill.LineItems))
+		for i, item := range bill.LineItems {
+			view.LineItems[i] = NewLineItemView(item, loc)
+		}
+	}
+
+	return view
+}
+
+// timeIn returns a copy of t in loc, leaving t itself untouched
+func timeIn(t *time.Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	in := t.In(loc)
+	return &in
+}
+
+// NewBillViews maps a list of domain bills to their API representation
+func NewBillViews(bills []model.Bill) []BillView {
+	views := make([]BillView, len(bills))
+	for i := range bills {
+		views[i] = NewBillView(&bills[i])
+	}
+	return views
+}
+
+// NewBillSummaryViews maps bill summaries to their API representation
+func NewBillSummaryViews(summaries []model.BillSummary) []BillSummaryView {
+	views := make([]BillSummaryView, len(summaries))
+	for i, summary := range summaries {
+		loc := summary.Location()
+		views[i] = BillSummaryView{
+			ID:                 summary.ID,
+			Status:             summary.Status,
+			Currency:           summary.Currency,
+			TotalAmount:        summary.TotalAmount,
+			TotalAmountDisplay: money.Format(summary.TotalAmount, summary.Currency),
+			LineItemCount:      summary.LineItemCount,
+			Timezone:           loc.String(),
+			CreatedAt:          summary.CreatedAt.In(loc),
+			ClosedAt:           timeIn(summary.ClosedAt, loc),
+		}
+	}
+	return views
+}
+
+// NewLineItemView maps a domain line item to its API representation, with its
+// timestamps rendered in loc
+func NewLineItemView(item model.LineItem, loc *time.Location) LineItemView {
+	return LineItemView{
+		ID:              item.ID,
+		Sequence:        item.Sequence,
+		Description:     item.Description,
+		Amount:          item.Amount,
+		AmountDisplay:   money.Format(item.Amount, item.Currency),
+		Currency:        item.Currency,
+		Category:        item.Category,
+		Type:            item.Type,
+		Metadata:        item.Metadata,
+		Ref:             item.Ref,
+		ApprovedBy:      item.ApprovedBy,
+		AppliedRate:     item.AppliedRate,
+		RateAsOf:        timeIn(item.RateAsOf, loc),
+		ConvertedAmount: item.ConvertedAmount,
+		FXFee:           item.FXFee,
+		EffectiveDate:   timeIn(item.EffectiveDate, loc),
+		FullAmount:      item.FullAmount,
+		CreatedAt:       item.CreatedAt.In(loc),
+	}
+}
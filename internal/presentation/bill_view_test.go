@@ -0,0 +1,235 @@
+package presentation
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+)
+
+func TestNewBillView(t *testing.T) {
+	bill := &model.Bill{
+		ID:          "bill_1",
+		Status:      model.BillStatusOpen,
+		Currency:    model.CurrencyUSD,
+		TotalAmount: 4700,
+		LineItems: []model.LineItem{
+			{ID: "li_1", Description: "Fee", Amount: 1000, Currency: model.CurrencyUSD, ConvertedAmount: 1000},
+			{ID: "li_2", Description: "Fee", Amount: 10000, Currency: model.CurrencyGEL, ConvertedAmount: 3700},
+		},
+	}
+
+	view := NewBillView(bill)
+
+	if view.TotalAmountDisplay != "$47.00" {
+		t.Errorf("expected total display $47.00, got %q", view.TotalAmountDisplay)
+	}
+	if len(view.LineItems) != 2 {
+		t.Fatalf("expected 2 line items, got %d", len(view.LineItems))
+	}
+	if view.LineItems[1].AmountDisplay != "₾100.00" {
+		t.Errorf("expected line item display ₾100.00, got %q", view.LineItems[1].AmountDisplay)
+	}
+}
+
+func TestNewBillViewRendersBillTimezone(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 22, 30, 0, 0, time.UTC)
+	periodEnd := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		timezone string
+		want     string // CreatedAt as rendered
+	}{
+		{name: "UTC by default", timezone: "", want: "2024-03-01T22:30:00Z"},
+		{name: "Tbilisi", timezone: "Asia/Tbilisi", want: "2024-03-02T02:30:00+04:00"},
+		{name: "New York", timezone: "America/New_York", want: "2024-03-01T17:30:00-05:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bill := &model.Bill{
+				ID:        "bill_1",
+				Currency:  model.CurrencyUSD,
+				Timezone:  tt.timezone,
+				CreatedAt: createdAt,
+				PeriodEnd: &periodEnd,
+				LineItems: []model.LineItem{{ID: "li_1", Currency: model.CurrencyUSD, CreatedAt: createdAt}},
+			}
+
+			view := NewBillView(bill)
+
+			if got := view.CreatedAt.Format(time.RFC3339); got != tt.want {
+				t.Errorf("expected createdAt %s, got %s", tt.want, got)
+			}
+			if got := view.LineItems[0].CreatedAt.Format(time.RFC3339); got != tt.want {
+				t.Errorf("expected line item createdAt %s, got %s", tt.want, got)
+			}
+			if !view.PeriodEnd.Equal(periodEnd) || view.PeriodEnd.Location().String() != view.Timezone {
+				t.Errorf("expected periodEnd %s in %s, got %s", periodEnd, view.Timezone, view.PeriodEnd)
+			}
+			// The bill itself stays in UTC
+			if bill.CreatedAt.Location() != time.UTC || bill.PeriodEnd.Location() != time.UTC || bill.LineItems[0].CreatedAt.Location() != time.UTC {
+				t.Error("expected rendering not to change the bill's stored timestamps")
+			}
+		})
+	}
+}
+
+func TestDomainBillSerializesWithoutDisplayFields(t *testing.T) {
+	bill := &model.Bill{ID: "bill_1", Currency: model.CurrencyUSD, TotalAmount: 1000}
+
+	raw, _ := json.Marshal(bill)
+	if strings.Contains(string(raw), "totalAmountDisplay") {
+		t.Errorf("domain bill JSON leaked a display field: %s", raw)
+	}
+
+	view, _ := json.Marshal(NewBillView(bill))
+	if !strings.Contains(string(view), `"totalAmountDisplay":"$10.00"`) {
+		t.Errorf("expected view JSON to include the display total, got %s", view)
+	}
+}
+
+func TestNewListCurrenciesResponse(t *testing.T) {
+	resp := NewListCurrenciesResponse([]model.CurrencyInfo{
+		{Code: model.CurrencyGEL, DecimalPlaces: 2, RateToUSD: 0.37},
+		{Code: "JPY", DecimalPlaces: 0},
+	})
+
+	if resp.Currencies[0].Symbol != "₾" || resp.Currencies[0].RateToUSD != 0.37 {
+		t.Errorf("unexpected GEL view %+v", resp.Currencies[0])
+	}
+	if resp.Currencies[1].Symbol != "JPY" {
+		t.Errorf("expected a currency without a symbol to fall back to its code, got %q", resp.Currencies[1].Symbol)
+	}
+}
+
+func TestBillActions(t *testing.T) {
+	deletedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		bill model.Bill
+		want []BillAction
+	}{
+		{"draft", model.Bill{Status: model.BillStatusDraft}, []BillAction{ActionAddLineItem, ActionActivate}},
+		{"open", model.Bill{Status: model.BillStatusOpen}, []BillAction{ActionAddLineItem, ActionClose}},
+		{"closed", model.Bill{Status: model.BillStatusClosed}, []BillAction{ActionReopen}},
+		{"deleted", model.Bill{Status: model.BillStatusOpen, DeletedAt: &deletedAt}, []BillAction{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BillActions(&tt.bill); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BillActions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegacyBillViewUsesSnakeCase(t *testing.T) {
+	closedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	bill := &model.Bill{
+		ID:          "bill_1",
+		OrgID:       "org_1",
+		Status:      model.BillStatusClosed,
+		Currency:    model.CurrencyUSD,
+		TotalAmount: 1000,
+		ClosedAt:    &closedAt,
+		LineItems: []model.LineItem{
+			{ID: "li_1", Sequence: 1, Description: "Fee", Amount: 1000, Currency: model.CurrencyUSD, ConvertedAmount: 1000},
+		},
+	}
+	view := NewBillView(bill)
+
+	camel, _ := json.Marshal(view)
+	snake, _ := json.Marshal(NewLegacyBillView(view))
+
+	tests := []struct {
+		name    string
+		raw     []byte
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "camelCase",
+			raw:     camel,
+			want:    []string{`"totalAmount":1000`, `"lineItems":[`, `"orgId":"org_1"`, `"closedAt":"2024-03-01T00:00:00Z"`, `"amountDisplay":"$10.00"`, `"convertedAmount":1000`},
+			notWant: []string{`"total_amount"`, `"line_items"`},
+		},
+		{
+			name:    "snake_case",
+			raw:     snake,
+			want:    []string{`"total_amount":1000`, `"line_items":[`, `"org_id":"org_1"`, `"closed_at":"2024-03-01T00:00:00Z"`, `"amount_display":"$10.00"`, `"converted_amount":1000`},
+			notWant: []string{`"totalAmount"`, `"lineItems"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, field := range tt.want {
+				if !strings.Contains(string(tt.raw), field) {
+					t.Errorf("expected %s in %s", field, tt.raw)
+				}
+			}
+			for _, field := range tt.notWant {
+				if strings.Contains(string(tt.raw), field) {
+					t.Errorf("unexpected %s in %s", field, tt.raw)
+				}
+			}
+		})
+	}
+}
+
+func TestSetPaymentStatus(t *testing.T) {
+	// Due at the end of the working day in Tbilisi (UTC+4), 14:00 UTC
+	due := time.Date(2024, 4, 10, 14, 0, 0, 0, time.UTC)
+	closed := func(paid int64) *model.Bill {
+		total := int64(5000)
+		return &model.Bill{
+			Status: model.BillStatusClosed, Currency: model.CurrencyUSD, Timezone: "Asia/Tbilisi",
+			TotalAmount: total, FinalTotal: &total, DueDate: &due, AmountPaid: paid,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		bill       *model.Bill
+		now        time.Time
+		wantStatus PaymentStatus
+		wantDays   *int
+	}{
+		{name: "not due", bill: closed(0), now: due.AddDate(0, 0, -(DueSoonDays + 1)), wantStatus: PaymentStatusNotDue, wantDays: intPtr(DueSoonDays + 1)},
+		{name: "due soon", bill: closed(0), now: due.AddDate(0, 0, -DueSoonDays), wantStatus: PaymentStatusDueSoon, wantDays: intPtr(DueSoonDays)},
+		{name: "due later today", bill: closed(0), now: due.Add(-10 * time.Hour), wantStatus: PaymentStatusDueSoon, wantDays: intPtr(0)},
+		{name: "past the due time, still the due date", bill: closed(0), now: due.Add(5 * time.Hour), wantStatus: PaymentStatusDueSoon, wantDays: intPtr(0)},
+		{name: "one day overdue", bill: closed(0), now: due.Add(24 * time.Hour), wantStatus: PaymentStatusOverdue, wantDays: intPtr(-1)},
+		{name: "partly paid and overdue", bill: closed(4999), now: due.AddDate(0, 0, 3), wantStatus: PaymentStatusOverdue, wantDays: intPtr(-3)},
+		{name: "fully paid", bill: closed(5000), now: due.AddDate(0, 0, 3), wantStatus: PaymentStatusPaid},
+		{name: "open", bill: &model.Bill{Status: model.BillStatusOpen, TotalAmount: 5000}, now: due},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := NewBillView(tt.bill)
+			view.SetPaymentStatus(tt.bill, tt.now)
+
+			if view.PaymentStatus != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, view.PaymentStatus)
+			}
+			if !reflect.DeepEqual(view.DaysUntilDue, tt.wantDays) {
+				t.Errorf("expected days until due %v, got %v", derefInt(tt.wantDays), derefInt(view.DaysUntilDue))
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func derefInt(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
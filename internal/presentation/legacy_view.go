@@ -0,0 +1,131 @@
+package presentation
+
+import (
+	"time"
+
+	"fees-api/internal/model"
+)
+
+// Field naming styles a client can ask for with the FieldNamingHeader
+const (
+	FieldNamingHeader    = "X-Field-Naming"
+	FieldNamingSnakeCase = "snake_case"
+)
+
+// LegacyBillView is BillView with snake_case field names, for older clients that
+// predate the camelCase API. It is built from a BillView so the two never disagree
+// on content, only on naming.
+type LegacyBillView struct {
+	ID                 string                   `json:"id"`
+	OrgID              string                   `json:"org_id"`
+	Status             model.BillStatus         `json:"status"`
+	Currency           model.Currency           `json:"currency"`
+	TotalAmount        int64                    `json:"total_amount"` // in cents
+	TotalAmountDisplay string                   `json:"total_amount_display"`
+	FXFees             int64                    `json:"fx_fees,omitempty"` // in cents, included in TotalAmount
+	LineItems          []LegacyLineItemView     `json:"line_items,omitempty"`
+	Note               string                   `json:"note,omitempty"`
+	Timezone           string                   `json:"timezone"`
+	CreatedAt          time.Time                `json:"created_at"`
+	ClosedAt           *time.Time               `json:"closed_at,omitempty"`
+	DeletedAt          *time.Time               `json:"deleted_at,omitempty"`
+	PeriodStart        *time.Time               `json:"period_start,omitempty"`
+	PeriodEnd          *time.Time               `json:"period_end,omitempty"`
+	FinalTotal         *int64                   `json:"final_total,omitempty"` // in cents
+	FinalLineItemCount *int                     `json:"final_line_item_count,omitempty"`
+	InvoiceNumber      string                   `json:"invoice_number,omitempty"`
+	CloseApproval      *LegacyCloseApprovalView `json:"close_approval,omitempty"`
+	DueDate            *time.Time               `json:"due_date,omitempty"`
+	AmountPaid         int64                    `json:"amount_paid,omitempty"` // in cents
+	DaysUntilDue       *int                     `json:"days_until_due,omitempty"`
+	PaymentStatus      PaymentStatus            `json:"payment_status,omitempty"`
+	Version            int64                    `json:"version"`
+}
+
+// LegacyLineItemView is LineItemView with snake_case field names
+type LegacyLineItemView struct {
+	ID              string                 `json:"id"`
+	Sequence        int                    `json:"sequence"`
+	Description     string                 `json:"description"`
+	Amount          int64                  `json:"amount"` // in cents
+	AmountDisplay   string                 `json:"amount_display"`
+	Currency        model.Currency         `json:"currency"`
+	Category        model.LineItemCategory `json:"category"`
+	Type            model.LineItemType     `json:"type"`
+	Metadata        map[string]string      `json:"metadata,omitempty"`
+	Ref             string                 `json:"ref,omitempty"`
+	ApprovedBy      string                 `json:"approved_by,omitempty"`
+	AppliedRate     float64                `json:"applied_rate"`
+	RateAsOf        *time.Time             `json:"rate_as_of,omitempty"`
+	ConvertedAmount int64                  `json:"converted_amount"` // in the bill's currency (cents)
+	FXFee           int64                  `json:"fx_fee,omitempty"` // in the bill's currency (cents)
+	EffectiveDate   *time.Time             `json:"effective_date,omitempty"`
+	FullAmount      *int64                 `json:"full_amount,omitempty"` // pre-proration amount (cents)
+	CreatedAt       time.Time              `json:"created_at"`
+}
+
+// LegacyCloseApprovalView is model.CloseApproval with snake_case field names
+type LegacyCloseApprovalView struct {
+	ApprovedBy string    `json:"approved_by"`
+	Total      int64     `json:"total"` // in cents
+	ApprovedAt time.Time `json:"approved_at"`
+}
+
+// LegacyGetBillResponse is the GetBill response for snake_case clients. The
+// optional breakdown, actions and events aren't part of it.
+type LegacyGetBillResponse struct {
+	Bill           LegacyBillView                   `json:"bill"`
+	CategoryTotals map[model.LineItemCategory]int64 `json:"category_totals"` // in the bill's currency (cents)
+}
+
+// NewLegacyBillView maps a bill's API representation to its snake_case form
+func NewLegacyBillView(view BillView) LegacyBillView {
+	legacy := LegacyBillView{
+		ID:                 view.ID,
+		OrgID:              view.OrgID,
+		Status:             view.Status,
+		Currency:           view.Currency,
+		TotalAmount:        view.TotalAmount,
+		TotalAmountDisplay: view.TotalAmountDisplay,
+		FXFees:             view.FXFees,
+		Note:               view.Note,
+		Timezone:           view.Timezone,
+		CreatedAt:          view.CreatedAt,
+		ClosedAt:           view.ClosedAt,
+		DeletedAt:          view.DeletedAt,
+		PeriodStart:        view.PeriodStart,
+		PeriodEnd:          view.PeriodEnd,
+		FinalTotal:         view.FinalTotal,
+		FinalLineItemCount: view.FinalLineItemCount,
+		InvoiceNumber:      view.InvoiceNumber,
+		DueDate:            view.DueDate,
+		AmountPaid:         view.AmountPaid,
+		DaysUntilDue:       view.DaysUntilDue,
+		PaymentStatus:      view.PaymentStatus,
+		Version:            view.Version,
+	}
+	if view.CloseApproval != nil {
+		legacy.CloseApproval = &LegacyCloseApprovalView{
+			ApprovedBy: view.CloseApproval.ApprovedBy,
+			Total:      view.CloseApproval.Total,
+			ApprovedAt: view.CloseApproval.ApprovedAt,
+		}
+	}
+
+	if len(view.LineItems) > 0 {
+		legacy.LineItems = make([]LegacyLineItemView, len(view.LineItems))
+		for i, item := range view.LineItems {
+			legacy.LineItems[i] = LegacyLineItemView(item)
+		}
+	}
+
+	return legacy
+}
+
+// NewLegacyGetBillResponse maps a GetBill response to its snake_case form
+func NewLegacyGetBillResponse(resp *GetBillResponse) *LegacyGetBillResponse {
+	return &LegacyGetBillResponse{
+		Bill:           NewLegacyBillView(resp.Bill),
+		CategoryTotals: resp.CategoryTotals,
+	}
+}
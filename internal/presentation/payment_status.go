@@ -0,0 +1,56 @@
+package presentation
+
+import (
+	"time"
+
+	"fees-api/internal/model"
+)
+
+// PaymentStatus summarizes where a closed bill stands against its due date
+type PaymentStatus string
+
+const (
+	PaymentStatusNotDue  PaymentStatus = "not_due"
+	PaymentStatusDueSoon PaymentStatus = "due_soon" // due within DueSoonDays, or today
+	PaymentStatusOverdue PaymentStatus = "overdue"
+	PaymentStatusPaid    PaymentStatus = "paid"
+)
+
+// DueSoonDays is how many days before its due date a bill counts as due soon
+const DueSoonDays = 7
+
+// SetPaymentStatus derives DaysUntilDue and PaymentStatus for the view as of now.
+// Days are counted between calendar dates in the bill's time zone, so a bill is
+// due today (0 days) until midnight there and overdue by 1 day after it. Bills
+// without a due date, i.e. not closed, are left without either; paid bills get a
+// status but no day count.
+func (v *BillView) SetPaymentStatus(bill *model.Bill, now time.Time) {
+	v.DaysUntilDue, v.PaymentStatus = nil, ""
+	if bill.DueDate == nil {
+		return
+	}
+	if bill.BalanceDue() <= 0 {
+		v.PaymentStatus = PaymentStatusPaid
+		return
+	}
+
+	loc := bill.Location()
+	days := calendarDays(now.In(loc), bill.DueDate.In(loc))
+	v.DaysUntilDue = &days
+	switch {
+	case days < 0:
+		v.PaymentStatus = PaymentStatusOverdue
+	case days <= DueSoonDays:
+		v.PaymentStatus = PaymentStatusDueSoon
+	default:
+		v.PaymentStatus = PaymentStatusNotDue
+	}
+}
+
+// calendarDays counts the calendar days from one date to another, ignoring the
+// time of day and any daylight saving shift between them
+func calendarDays(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
@@ -0,0 +1,67 @@
+package presentation
+
+import (
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/pkg/money"
+)
+
+// ReceiptView is the API representation of a receipt. Like a bill's, its timestamps
+// are rendered in the bill's time zone.
+type ReceiptView struct {
+	ID            string         `json:"id"`
+	BillID        string         `json:"billId"`
+	InvoiceNumber string         `json:"invoiceNumber,omitempty"`
+	OrgID         string         `json:"orgId"`
+	Sequence      int            `json:"sequence"`
+	Currency      model.Currency `json:"currency"`
+	Subtotal      int64          `json:"subtotal"` // in cents
+	CreditTotal   int64          `json:"creditTotal"`
+	FXFees        int64          `json:"fxFees"`
+	DiscountTotal int64          `json:"discountTotal"`
+	TaxTotal      int64          `json:"taxTotal"`
+	Total         int64          `json:"total"`
+	TotalDisplay  string         `json:"totalDisplay"`
+	LineItems     []LineItemView `json:"lineItems"`
+	ApprovedBy    string         `json:"approvedBy,omitempty"`
+	Timezone      string         `json:"timezone"`
+	ClosedAt      time.Time      `json:"closedAt"`
+}
+
+// NewReceiptView maps a receipt to its API representation
+func NewReceiptView(receipt *model.Receipt) ReceiptView {
+	loc := receipt.Location()
+	view := ReceiptView{
+		ID:            receipt.ID,
+		BillID:        receipt.BillID,
+		InvoiceNumber: receipt.InvoiceNumber,
+		OrgID:         receipt.OrgID,
+		Sequence:      receipt.Sequence,
+		Currency:      receipt.Currency,
+		Subtotal:      receipt.Subtotal,
+		CreditTotal:   receipt.CreditTotal,
+		FXFees:        receipt.FXFees,
+		DiscountTotal: receipt.DiscountTotal,
+		TaxTotal:      receipt.TaxTotal,
+		Total:         receipt.Total,
+		TotalDisplay:  money.Format(receipt.Total, receipt.Currency),
+		LineItems:     make([]LineItemView, len(receipt.LineItems)),
+		ApprovedBy:    receipt.ApprovedBy,
+		Timezone:      loc.String(),
+		ClosedAt:      receipt.ClosedAt.In(loc),
+	}
+	for i, item := range receipt.LineItems {
+		view.LineItems[i] = NewLineItemView(item, loc)
+	}
+	return view
+}
+
+// NewReceiptViews maps receipts to their API representation
+func NewReceiptViews(receipts []model.Receipt) []ReceiptView {
+	views := make([]ReceiptView, len(receipts))
+	for i := range receipts {
+		views[i] = NewReceiptView(&receipts[i])
+	}
+	return views
+}
@@ -0,0 +1,260 @@
+package presentation
+
+import (
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/pkg/money"
+)
+
+// CreateBillResponse represents the response from creating a bill
+type CreateBillResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// AddLineItemResponse represents the response from adding a line item
+type AddLineItemResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// ReplaceLineItemsResponse represents the response from replacing a bill's line items
+type ReplaceLineItemsResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// UpdateLineItemResponse represents the response from updating a line item
+type UpdateLineItemResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// ActivateBillResponse represents the response from activating a draft bill
+type ActivateBillResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// ApproveBillResponse represents the response from approving a bill to close
+type ApproveBillResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// SplitBillResponse represents the response from splitting a bill: the source bill
+// without the moved line items, and the new bill holding them
+type SplitBillResponse struct {
+	Source BillView `json:"source"`
+	Bill   BillView `json:"bill"`
+}
+
+// MergeBillsResponse represents the response from merging bills: the target bill
+// holding every merged line item
+type MergeBillsResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// ReopenBillResponse represents the response from reopening a closed bill
+type ReopenBillResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// UpdateNoteResponse represents the response from updating a bill's note
+type UpdateNoteResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// RecordPaymentResponse represents the response from recording a payment
+type RecordPaymentResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// DeleteBillResponse represents the response from soft-deleting a bill
+type DeleteBillResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// RestoreBillResponse represents the response from restoring a soft-deleted bill
+type RestoreBillResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// ChangeCurrencyResponse represents the response from changing a bill's currency
+type ChangeCurrencyResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// CloseBillResponse represents the response from closing a bill
+type CloseBillResponse struct {
+	Bill    BillView     `json:"bill"`
+	Receipt *ReceiptView `json:"receipt,omitempty"`
+}
+
+// GetReceiptResponse represents the response from fetching a bill's latest receipt
+type GetReceiptResponse struct {
+	Receipt ReceiptView `json:"receipt"`
+}
+
+// ListReceiptsResponse represents the response from listing a bill's receipts
+type ListReceiptsResponse struct {
+	Receipts []ReceiptView `json:"receipts"` // oldest first
+}
+
+// PreviewCloseResponse represents the bill as closing it would leave it
+type PreviewCloseResponse struct {
+	Bill BillView `json:"bill"`
+}
+
+// CloseBillsResponse represents the response from closing a batch of bills
+type CloseBillsResponse struct {
+	Results []CloseBillResultView `json:"results"` // one per requested ID, in request order
+}
+
+// CloseBillResultView is the API representation of one bill's batch close outcome
+type CloseBillResultView struct {
+	BillID  string             `json:"billId"`
+	Outcome model.CloseOutcome `json:"outcome"`
+	Bill    *BillView          `json:"bill,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// NewCloseBillsResponse maps batch close results to their API representation
+func NewCloseBillsResponse(results []model.CloseBillResult) *CloseBillsResponse {
+	views := make([]CloseBillResultView, len(results))
+	for i, result := range results {
+		views[i] = CloseBillResultView{
+			BillID:  result.BillID,
+			Outcome: result.Outcome,
+			Error:   result.Error,
+		}
+		if result.Bill != nil {
+			bill := NewBillView(result.Bill)
+			views[i].Bill = &bill
+		}
+	}
+	return &CloseBillsResponse{Results: views}
+}
+
+// RecalculateTotalResponse represents the response from recalculating a bill's total
+type RecalculateTotalResponse struct {
+	Bill     BillView `json:"bill"`
+	OldTotal int64    `json:"oldTotal"` // in cents
+	NewTotal int64    `json:"newTotal"` // in cents
+}
+
+// RecalculateOpenBillsResponse represents the response from re-converting open
+// bills after a rate update
+type RecalculateOpenBillsResponse struct {
+	Currency string `json:"currency"`
+	Updated  int    `json:"updated"` // number of bills whose totals changed
+}
+
+// GetBillResponse represents the response from getting a bill
+type GetBillResponse struct {
+	Bill           BillView                         `json:"bill"`
+	CategoryTotals map[model.LineItemCategory]int64 `json:"categoryTotals"` // in the bill's currency (cents)
+	Breakdown      *model.BillBreakdown             `json:"breakdown,omitempty"`
+	Actions        []BillAction                     `json:"actions,omitempty"`    // set with includeActions
+	Events         []events.BillEvent               `json:"events,omitempty"`     // set with includeEvents, most recent first
+	TotalCheck     *model.TotalCheck                `json:"totalCheck,omitempty"` // set with verify
+}
+
+// ListBillsResponse represents the response from listing bills. With summary=true,
+// Bills is empty and Summaries holds the matching bills instead.
+type ListBillsResponse struct {
+	Bills      []BillView               `json:"bills"`
+	Summaries  []BillSummaryView        `json:"summaries,omitempty"`
+	Totals     map[model.Currency]int64 `json:"totals"`               // summed per currency over all matching bills (cents)
+	NextCursor string                   `json:"nextCursor,omitempty"` // set when another page follows
+}
+
+// GetBillEventsResponse represents the response from listing a bill's events
+type GetBillEventsResponse struct {
+	Events []events.BillEvent `json:"events"`
+}
+
+// CurrencyView represents a supported currency in API responses
+type CurrencyView struct {
+	Code          model.Currency `json:"code"`
+	Symbol        string         `json:"symbol"`
+	DecimalPlaces int            `json:"decimalPlaces"`
+	RateToUSD     float64        `json:"rateToUSD"`
+	RateAsOf      time.Time      `json:"rateAsOf"`
+}
+
+// ListCurrenciesResponse represents the response from listing supported currencies
+type ListCurrenciesResponse struct {
+	Currencies []CurrencyView `json:"currencies"`
+}
+
+// NewListCurrenciesResponse maps currency descriptions to their API representation
+func NewListCurrenciesResponse(currencies []model.CurrencyInfo) *ListCurrenciesResponse {
+	views := make([]CurrencyView, len(currencies))
+	for i, currency := range currencies {
+		views[i] = NewCurrencyView(currency)
+	}
+	return &ListCurrenciesResponse{Currencies: views}
+}
+
+// NewCurrencyView maps a currency description to its API representation
+func NewCurrencyView(currency model.CurrencyInfo) CurrencyView {
+	return CurrencyView{
+		Code:          currency.Code,
+		Symbol:        money.Symbol(currency.Code),
+		DecimalPlaces: currency.DecimalPlaces,
+		RateToUSD:     currency.RateToUSD,
+		RateAsOf:      currency.RateAsOf,
+	}
+}
+
+// ConvertCurrencyResponse represents the response from converting an amount
+type ConvertCurrencyResponse struct {
+	From                   model.Currency `json:"from"`
+	To                     model.Currency `json:"to"`
+	Rate                   float64        `json:"rate"`
+	RateAsOf               time.Time      `json:"rateAsOf"`
+	Amount                 int64          `json:"amount"`          // in cents of From
+	ConvertedAmount        int64          `json:"convertedAmount"` // in cents of To
+	ConvertedAmountDisplay string         `json:"convertedAmountDisplay"`
+}
+
+// NewConvertCurrencyResponse maps a conversion to its API representation
+func NewConvertCurrencyResponse(conversion *model.Conversion) *ConvertCurrencyResponse {
+	return &ConvertCurrencyResponse{
+		From:                   conversion.From,
+		To:                     conversion.To,
+		Rate:                   conversion.Rate,
+		RateAsOf:               conversion.RateAsOf,
+		Amount:                 conversion.Amount,
+		ConvertedAmount:        conversion.ConvertedAmount,
+		ConvertedAmountDisplay: money.Format(conversion.ConvertedAmount, conversion.To),
+	}
+}
+
+// OverdueSummaryResponse is the collections view of overdue bills as of AsOf
+type OverdueSummaryResponse struct {
+	AsOf      time.Time            `json:"asOf"`
+	Customers []OverdueSummaryView `json:"customers"`
+}
+
+// OverdueSummaryView is the API representation of one customer's overdue total in
+// one currency
+type OverdueSummaryView struct {
+	CustomerID          string         `json:"customerId"`
+	Currency            model.Currency `json:"currency"`
+	OverdueCount        int            `json:"overdueCount"`
+	OverdueTotal        int64          `json:"overdueTotal"` // in cents
+	OverdueTotalDisplay string         `json:"overdueTotalDisplay"`
+}
+
+// NewOverdueSummaryResponse maps an overdue summary to its API representation
+func NewOverdueSummaryResponse(asOf time.Time, summary []model.OverdueSummary) *OverdueSummaryResponse {
+	views := make([]OverdueSummaryView, len(summary))
+	for i, row := range summary {
+		views[i] = OverdueSummaryView{
+			CustomerID:          row.CustomerID,
+			Currency:            row.Currency,
+			OverdueCount:        row.OverdueCount,
+			OverdueTotal:        row.OverdueTotal,
+			OverdueTotalDisplay: money.Format(row.OverdueTotal, row.Currency),
+		}
+	}
+	return &OverdueSummaryResponse{AsOf: asOf, Customers: views}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"time"
+
+	"fees-api/internal/model"
+)
+
+// BillQuery is the criteria QueryBills selects bills by. It's the contract between
+// the service and storage: every criterion must be evaluated by the datastore, so a
+// SQL-backed repository turns the whole query into its WHERE, ORDER BY and LIMIT
+// clauses, with Matches as the reference semantics. Loading bills and filtering
+// them in Go after a full scan is not allowed; it would read every bill in the
+// table for each call.
+// Zero-valued fields match everything except soft-deleted bills, which are only
+// matched with IncludeDeleted.
+type BillQuery struct {
+	OrgID          string
+	Statuses       []model.BillStatus // matches any of the listed statuses
+	Currencies     []model.Currency   // matches any of the listed currencies
+	CreatedFrom    time.Time          // only bills created at or after this instant; zero disables
+	CreatedBefore  time.Time          // only bills created before this instant; zero disables
+	IncludeDeleted bool
+	MinTotal       int64 // cents in the bill's own currency; zero disables the threshold
+	HasLineItems   bool
+	NoLineItems    bool
+	After          *BillCursor // only bills sorting after the cursor
+	Limit          int         // at most this many bills, first in (CreatedAt, ID) order; zero means all
+}
+
+// Query returns the filter as the BillQuery List must run
+func (f BillFilter) Query() BillQuery {
+	q := BillQuery{
+		OrgID:          f.OrgID,
+		Statuses:       f.Statuses,
+		CreatedBefore:  f.CreatedBefore,
+		IncludeDeleted: f.IncludeDeleted,
+		MinTotal:       f.MinTotal,
+		HasLineItems:   f.HasLineItems,
+		NoLineItems:    f.NoLineItems,
+		After:          f.After,
+		Limit:          f.Limit,
+	}
+	if f.Currency != "" {
+		q.Currencies = []model.Currency{f.Currency}
+	}
+	return q
+}
+
+// Matches reports whether the bill satisfies every criterion in the query. It's the
+// reference semantics SQL must reproduce, for in-memory stores and re-checks.
+func (q BillQuery) Matches(bill *model.Bill) bool {
+	if q.OrgID != "" && bill.OrgID != q.OrgID {
+		return false
+	}
+	if len(q.Statuses) > 0 && !containsStatus(q.Statuses, bill.Status) {
+		return false
+	}
+	if len(q.Currencies) > 0 && !containsCurrency(q.Currencies, bill.Currency) {
+		return false
+	}
+	if !q.CreatedFrom.IsZero() && bill.CreatedAt.Before(q.CreatedFrom) {
+		return false
+	}
+	if !q.CreatedBefore.IsZero() && !bill.CreatedAt.Before(q.CreatedBefore) {
+		return false
+	}
+	if !q.IncludeDeleted && bill.DeletedAt != nil {
+		return false
+	}
+	if q.MinTotal > 0 && bill.TotalAmount < q.MinTotal {
+		return false
+	}
+	if q.HasLineItems && len(bill.LineItems) == 0 {
+		return false
+	}
+	if q.NoLineItems && len(bill.LineItems) > 0 {
+		return false
+	}
+	if q.After != nil && !q.After.Less(CursorOf(bill)) {
+		return false
+	}
+	return true
+}
+
+// Paginate sorts bills into list order and cuts them to the query's limit
+func (q BillQuery) Paginate(bills []model.Bill) []model.Bill {
+	return paginate(bills, func(bill *model.Bill) BillCursor { return CursorOf(bill) }, q.Limit)
+}
+
+func containsCurrency(currencies []model.Currency, currency model.Currency) bool {
+	for _, c := range currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
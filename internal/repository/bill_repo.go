@@ -1,72 +1,388 @@
 package repository
 
 import (
-	"fees-api/internal/model"
+	"context"
+	"sort"
 	"sync"
+	"time"
+
+	"fees-api/internal/model"
 )
 
 // BillRepository defines the interface for bill data access
 type BillRepository interface {
-	Create(bill *model.Bill) error
-	Get(id string) (*model.Bill, error)
-	Update(bill *model.Bill) error
-	List(status string) ([]model.Bill, error)
+	Create(ctx context.Context, bill *model.Bill) error
+	// Get returns nil, nil when the bill doesn't exist or belongs to another org.
+	// Line items come back ordered by Sequence, as from List.
+	Get(ctx context.Context, orgID, id string) (*model.Bill, error)
+	// Exists reports whether Get would return the bill, without loading it.
+	// SQL-backed repositories should SELECT 1 rather than fetch the row.
+	Exists(ctx context.Context, orgID, id string) (bool, error)
+	Update(ctx context.Context, bill *model.Bill) error
+	// QueryBills returns the bills matching the query ordered by (CreatedAt, ID). The
+	// datastore evaluates every criterion; see BillQuery.
+	QueryBills(ctx context.Context, criteria BillQuery) ([]model.Bill, error)
+	// List is QueryBills with filter.Query(), and must push the filter down the same way
+	List(ctx context.Context, filter BillFilter) ([]model.Bill, error)
+	// ListSummaries returns summaries of the bills matching the filter. SQL-backed
+	// repositories should select only the summary columns and count line items
+	// rather than loading them, filtering as QueryBills does.
+	ListSummaries(ctx context.Context, filter BillFilter) ([]model.BillSummary, error)
+	// SumTotals sums TotalAmount per currency over every bill matching the filter,
+	// which SQL-backed repositories should do with GROUP BY
+	SumTotals(ctx context.Context, filter BillFilter) (map[model.Currency]int64, error)
+	// WithTransaction runs fn as a single unit of work; writes made through tx
+	// are only applied if fn returns nil
+	WithTransaction(ctx context.Context, fn func(tx BillRepository) error) error
+	// Ping verifies the underlying datastore is reachable
+	Ping(ctx context.Context) error
+}
+
+// BillFilter narrows the bills returned by List; zero-valued fields match everything
+// except soft-deleted bills, which are only matched with IncludeDeleted. It's the
+// service-facing form of BillQuery, which Query converts it to.
+type BillFilter struct {
+	OrgID          string
+	Statuses       []model.BillStatus // matches any of the listed statuses
+	Currency       model.Currency
+	IncludeDeleted bool
+	MinTotal       int64 // cents in the bill's own currency; zero disables the threshold
+	HasLineItems   bool
+	NoLineItems    bool      // only bills without line items
+	CreatedBefore  time.Time // only bills created before this instant; zero disables
+	// After only matches bills sorting after the key, for keyset pagination. SQL-backed
+	// repositories should bound the query with WHERE (created_at, id) > (...).
+	After *BillCursor
+	Limit int // at most this many bills, first in (CreatedAt, ID) order; zero means all
+}
+
+// BillCursor is a bill's position in list order: by CreatedAt, then by ID for
+// bills created at the same instant
+type BillCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Less reports whether c sorts before other
+func (c BillCursor) Less(other BillCursor) bool {
+	if !c.CreatedAt.Equal(other.CreatedAt) {
+		return c.CreatedAt.Before(other.CreatedAt)
+	}
+	return c.ID < other.ID
+}
+
+// CursorOf returns the bill's position in list order
+func CursorOf(bill *model.Bill) BillCursor {
+	return BillCursor{CreatedAt: bill.CreatedAt, ID: bill.ID}
+}
+
+// Paginate sorts bills into list order and cuts them to the filter's limit
+func (f BillFilter) Paginate(bills []model.Bill) []model.Bill {
+	return paginate(bills, func(bill *model.Bill) BillCursor { return CursorOf(bill) }, f.Limit)
 }
 
-// InMemoryBillRepository is an in-memory implementation of BillRepository
+// PaginateSummaries is Paginate for bill summaries
+func (f BillFilter) PaginateSummaries(summaries []model.BillSummary) []model.BillSummary {
+	return paginate(summaries, func(summary *model.BillSummary) BillCursor {
+		return BillCursor{CreatedAt: summary.CreatedAt, ID: summary.ID}
+	}, f.Limit)
+}
+
+func paginate[T any](items []T, key func(*T) BillCursor, limit int) []T {
+	sort.Slice(items, func(i, j int) bool { return key(&items[i]).Less(key(&items[j])) })
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// Matches reports whether the bill satisfies every criterion in the filter
+func (f BillFilter) Matches(bill *model.Bill) bool {
+	return f.Query().Matches(bill)
+}
+
+func containsStatus(statuses []model.BillStatus, status model.BillStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// InMemoryBillRepository is an in-memory implementation of BillRepository. Bills are
+// stored by pointer so scans don't copy them; every write stores a deep copy and
+// every read hands out one, line items included, so stored bills are never shared
+// with callers and a reader never sees a write half-applied. Bills are also indexed
+// by org, so queries for one org only scan that org's bills.
 type InMemoryBillRepository struct {
 	mu    sync.RWMutex
-	bills map[string]model.Bill
+	bills map[string]*model.Bill
+	byOrg map[string]map[string]*model.Bill // org ID -> bill ID -> bill
 }
 
 // NewInMemoryBillRepository creates a new in-memory bill repository
 func NewInMemoryBillRepository() *InMemoryBillRepository {
 	return &InMemoryBillRepository{
-		bills: make(map[string]model.Bill),
+		bills: make(map[string]*model.Bill),
+		byOrg: make(map[string]map[string]*model.Bill),
 	}
 }
 
+// store saves a bill and indexes it by org; the write lock must be held
+func (r *InMemoryBillRepository) store(bill *model.Bill) {
+	if previous, ok := r.bills[bill.ID]; ok && previous.OrgID != bill.OrgID {
+		delete(r.byOrg[previous.OrgID], bill.ID)
+	}
+	r.bills[bill.ID] = bill
+	if r.byOrg[bill.OrgID] == nil {
+		r.byOrg[bill.OrgID] = make(map[string]*model.Bill)
+	}
+	r.byOrg[bill.OrgID][bill.ID] = bill
+}
+
 // Create creates a new bill
-func (r *InMemoryBillRepository) Create(bill *model.Bill) error {
+func (r *InMemoryBillRepository) Create(ctx context.Context, bill *model.Bill) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.bills[bill.ID] = *bill
+	r.store(bill.Clone())
 	return nil
 }
 
-// Get retrieves a bill by ID
-func (r *InMemoryBillRepository) Get(id string) (*model.Bill, error) {
+// Get retrieves a bill by ID within an org
+func (r *InMemoryBillRepository) Get(ctx context.Context, orgID, id string) (*model.Bill, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	bill, ok := r.bills[id]
-	if !ok {
+	stored, ok := r.bills[id]
+	if !ok || stored.OrgID != orgID {
 		return nil, nil
 	}
-	return &bill, nil
+	bill := stored.Clone()
+	bill.SortLineItems()
+	return bill, nil
+}
+
+// Exists reports whether a bill with the ID exists within an org
+func (r *InMemoryBillRepository) Exists(ctx context.Context, orgID, id string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stored, ok := r.bills[id]
+	return ok && stored.OrgID == orgID, nil
 }
 
 // Update updates an existing bill
-func (r *InMemoryBillRepository) Update(bill *model.Bill) error {
+func (r *InMemoryBillRepository) Update(ctx context.Context, bill *model.Bill) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if _, ok := r.bills[bill.ID]; !ok {
 		return nil
 	}
-	r.bills[bill.ID] = *bill
+	r.store(bill.Clone())
 	return nil
 }
 
-// List returns all bills, optionally filtered by status
-func (r *InMemoryBillRepository) List(status string) ([]model.Bill, error) {
+// ForEach calls fn with every bill matching the filter until fn returns false. The
+// bill is the stored one, so fn must not modify or retain it, and must not call
+// back into the repository: the read lock is held throughout.
+func (r *InMemoryBillRepository) ForEach(filter BillFilter, fn func(bill *model.Bill) bool) {
+	r.forEach(filter.Query(), fn)
+}
+
+// forEach is ForEach for a query: only the query's org is scanned when it names one
+func (r *InMemoryBillRepository) forEach(q BillQuery, fn func(bill *model.Bill) bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	
+
+	candidates := r.bills
+	if q.OrgID != "" {
+		candidates = r.byOrg[q.OrgID]
+	}
+	for _, bill := range candidates {
+		if q.Matches(bill) && !fn(bill) {
+			return
+		}
+	}
+}
+
+// QueryBills returns the bills matching the query in list order
+func (r *InMemoryBillRepository) QueryBills(ctx context.Context, criteria BillQuery) ([]model.Bill, error) {
+	var result []model.Bill
+	r.forEach(criteria, func(bill *model.Bill) bool {
+		clone := bill.Clone()
+		clone.SortLineItems()
+		result = append(result, *clone)
+		return true
+	})
+	return criteria.Paginate(result), nil
+}
+
+// List returns all bills matching the filter
+func (r *InMemoryBillRepository) List(ctx context.Context, filter BillFilter) ([]model.Bill, error) {
+	return r.QueryBills(ctx, filter.Query())
+}
+
+// Count returns how many bills match the filter
+func (r *InMemoryBillRepository) Count(ctx context.Context, filter BillFilter) (int, error) {
+	count := 0
+	r.ForEach(filter, func(bill *model.Bill) bool {
+		count++
+		return true
+	})
+	return count, nil
+}
+
+// ListSummaries returns summaries of the bills matching the filter, without copying line items
+func (r *InMemoryBillRepository) ListSummaries(ctx context.Context, filter BillFilter) ([]model.BillSummary, error) {
+	var result []model.BillSummary
+	r.ForEach(filter, func(bill *model.Bill) bool {
+		result = append(result, bill.Summary())
+		return true
+	})
+	return filter.PaginateSummaries(result), nil
+}
+
+// SumTotals sums TotalAmount per currency over every bill matching the filter
+func (r *InMemoryBillRepository) SumTotals(ctx context.Context, filter BillFilter) (map[model.Currency]int64, error) {
+	totals := make(map[model.Currency]int64)
+	r.ForEach(filter, func(bill *model.Bill) bool {
+		totals[bill.Currency] += bill.TotalAmount
+		return true
+	})
+	return totals, nil
+}
+
+// Ping always succeeds for the in-memory repository unless the context is done
+func (r *InMemoryBillRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// WithTransaction runs fn while holding the write lock. Writes are staged and only
+// applied to the repository if fn succeeds.
+func (r *InMemoryBillRepository) WithTransaction(ctx context.Context, fn func(tx BillRepository) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := &inMemoryBillTx{
+		bills:  r.bills,
+		byOrg:  r.byOrg,
+		staged: make(map[string]model.Bill),
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for id := range tx.staged {
+		bill := tx.staged[id]
+		r.store(&bill)
+	}
+	return nil
+}
+
+// inMemoryBillTx is the BillRepository view handed to a transaction. The parent
+// repository's lock is already held, so it does no locking of its own.
+type inMemoryBillTx struct {
+	bills  map[string]*model.Bill
+	byOrg  map[string]map[string]*model.Bill
+	staged map[string]model.Bill
+}
+
+func (tx *inMemoryBillTx) lookup(id string) (model.Bill, bool) {
+	if bill, ok := tx.staged[id]; ok {
+		return *bill.Clone(), true
+	}
+	if bill, ok := tx.bills[id]; ok {
+		return *bill.Clone(), true
+	}
+	return model.Bill{}, false
+}
+
+func (tx *inMemoryBillTx) Create(ctx context.Context, bill *model.Bill) error {
+	tx.staged[bill.ID] = *bill.Clone()
+	return nil
+}
+
+func (tx *inMemoryBillTx) Get(ctx context.Context, orgID, id string) (*model.Bill, error) {
+	bill, ok := tx.lookup(id)
+	if !ok || bill.OrgID != orgID {
+		return nil, nil
+	}
+	bill.SortLineItems()
+	return &bill, nil
+}
+
+func (tx *inMemoryBillTx) Exists(ctx context.Context, orgID, id string) (bool, error) {
+	if bill, ok := tx.staged[id]; ok {
+		return bill.OrgID == orgID, nil
+	}
+	bill, ok := tx.bills[id]
+	return ok && bill.OrgID == orgID, nil
+}
+
+func (tx *inMemoryBillTx) Update(ctx context.Context, bill *model.Bill) error {
+	if _, ok := tx.lookup(bill.ID); !ok {
+		return nil
+	}
+	tx.staged[bill.ID] = *bill.Clone()
+	return nil
+}
+
+func (tx *inMemoryBillTx) QueryBills(ctx context.Context, criteria BillQuery) ([]model.Bill, error) {
+	candidates := tx.bills
+	if criteria.OrgID != "" {
+		candidates = tx.byOrg[criteria.OrgID]
+	}
 	var result []model.Bill
-	for _, bill := range r.bills {
-		if status != "" && string(bill.Status) != status {
+	for id, bill := range candidates {
+		if _, ok := tx.staged[id]; ok {
 			continue
 		}
-		result = append(result, bill)
+		if criteria.Matches(bill) {
+			result = append(result, *bill.Clone())
+		}
+	}
+	for _, bill := range tx.staged {
+		if criteria.Matches(&bill) {
+			result = append(result, *bill.Clone())
+		}
+	}
+	return criteria.Paginate(result), nil
+}
+
+func (tx *inMemoryBillTx) List(ctx context.Context, filter BillFilter) ([]model.Bill, error) {
+	return tx.QueryBills(ctx, filter.Query())
+}
+
+func (tx *inMemoryBillTx) ListSummaries(ctx context.Context, filter BillFilter) ([]model.BillSummary, error) {
+	bills, err := tx.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.BillSummary, len(bills))
+	for i := range bills {
+		result[i] = bills[i].Summary()
 	}
 	return result, nil
 }
+
+func (tx *inMemoryBillTx) SumTotals(ctx context.Context, filter BillFilter) (map[model.Currency]int64, error) {
+	bills, err := tx.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[model.Currency]int64)
+	for _, bill := range bills {
+		totals[bill.Currency] += bill.TotalAmount
+	}
+	return totals, nil
+}
+
+func (tx *inMemoryBillTx) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (tx *inMemoryBillTx) WithTransaction(ctx context.Context, fn func(tx BillRepository) error) error {
+	return fn(tx)
+}
@@ -0,0 +1,408 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+)
+
+func TestWithTransaction(t *testing.T) {
+	tests := []struct {
+		name      string
+		fnErr     error
+		wantTotal int64
+	}{
+		{name: "commits writes on success", fnErr: nil, wantTotal: 500},
+		{name: "discards writes on error", fnErr: errors.New("boom"), wantTotal: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewInMemoryBillRepository()
+			repo.Create(ctx, &model.Bill{ID: "bill_1", OrgID: "org_1", Status: model.BillStatusOpen, TotalAmount: 100})
+
+			err := repo.WithTransaction(ctx, func(tx BillRepository) error {
+				bill, _ := tx.Get(ctx, "org_1", "bill_1")
+				bill.TotalAmount = 500
+				tx.Update(ctx, bill)
+
+				// Reads inside the transaction see staged writes
+				staged, _ := tx.Get(ctx, "org_1", "bill_1")
+				if staged.TotalAmount != 500 {
+					t.Errorf("expected staged total 500, got %d", staged.TotalAmount)
+				}
+				return tt.fnErr
+			})
+
+			if err != tt.fnErr {
+				t.Errorf("WithTransaction() error = %v, want %v", err, tt.fnErr)
+			}
+			bill, _ := repo.Get(ctx, "org_1", "bill_1")
+			if bill.TotalAmount != tt.wantTotal {
+				t.Errorf("expected total %d, got %d", tt.wantTotal, bill.TotalAmount)
+			}
+		})
+	}
+}
+
+func TestWithTransactionHonorsCancellation(t *testing.T) {
+	repo := NewInMemoryBillRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := repo.WithTransaction(ctx, func(tx BillRepository) error {
+		called = true
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if called {
+		t.Error("expected transaction body not to run on a cancelled context")
+	}
+}
+
+func TestGetScopesToOrg(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryBillRepository()
+	repo.Create(ctx, &model.Bill{ID: "bill_1", OrgID: "org_1"})
+	repo.Create(ctx, &model.Bill{ID: "bill_2", OrgID: "org_2"})
+
+	if bill, _ := repo.Get(ctx, "org_2", "bill_1"); bill != nil {
+		t.Error("expected another org's bill to be invisible")
+	}
+	if bill, _ := repo.Get(ctx, "org_1", "bill_1"); bill == nil {
+		t.Error("expected the org's own bill to be found")
+	}
+
+	bills, _ := repo.List(ctx, BillFilter{OrgID: "org_1"})
+	if len(bills) != 1 || bills[0].ID != "bill_1" {
+		t.Errorf("expected only org_1's bill, got %+v", bills)
+	}
+}
+
+func TestExists(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryBillRepository()
+	repo.Create(ctx, &model.Bill{ID: "bill_1", OrgID: "org_1"})
+
+	tests := []struct {
+		name  string
+		orgID string
+		id    string
+		want  bool
+	}{
+		{name: "existing bill", orgID: "org_1", id: "bill_1", want: true},
+		{name: "missing bill", orgID: "org_1", id: "bill_missing", want: false},
+		{name: "another org's bill", orgID: "org_2", id: "bill_1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := repo.Exists(ctx, tt.orgID, tt.id); err != nil || got != tt.want {
+				t.Errorf("Exists() = %v, %v, want %v", got, err, tt.want)
+			}
+			repo.WithTransaction(ctx, func(tx BillRepository) error {
+				if got, err := tx.Exists(ctx, tt.orgID, tt.id); err != nil || got != tt.want {
+					t.Errorf("tx.Exists() = %v, %v, want %v", got, err, tt.want)
+				}
+				return nil
+			})
+		})
+	}
+
+	// A transaction sees the bills it creates
+	repo.WithTransaction(ctx, func(tx BillRepository) error {
+		tx.Create(ctx, &model.Bill{ID: "bill_2", OrgID: "org_1"})
+		if ok, _ := tx.Exists(ctx, "org_1", "bill_2"); !ok {
+			t.Error("expected a staged bill to exist within the transaction")
+		}
+		return errors.New("rollback")
+	})
+	if ok, _ := repo.Exists(ctx, "org_1", "bill_2"); ok {
+		t.Error("expected a rolled back bill not to exist")
+	}
+}
+
+func TestListAfterCursor(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryBillRepository()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, bill := range []model.Bill{
+		{ID: "bill_c", CreatedAt: base},
+		{ID: "bill_a", CreatedAt: base.Add(time.Minute)},
+		{ID: "bill_b", CreatedAt: base},
+		{ID: "bill_d", CreatedAt: base.Add(2 * time.Minute)},
+	} {
+		bill.OrgID = "org_1"
+		repo.Create(ctx, &bill)
+	}
+
+	ids := func(bills []model.Bill) []string {
+		var result []string
+		for _, bill := range bills {
+			result = append(result, bill.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		filter BillFilter
+		want   []string
+	}{
+		{name: "orders by creation then ID", filter: BillFilter{}, want: []string{"bill_b", "bill_c", "bill_a", "bill_d"}},
+		{name: "limits to the first bills", filter: BillFilter{Limit: 2}, want: []string{"bill_b", "bill_c"}},
+		{name: "resumes after a tied key", filter: BillFilter{After: &BillCursor{CreatedAt: base, ID: "bill_b"}, Limit: 2}, want: []string{"bill_c", "bill_a"}},
+		{name: "resumes after a later key", filter: BillFilter{After: &BillCursor{CreatedAt: base.Add(time.Minute), ID: "bill_a"}}, want: []string{"bill_d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bills, _ := repo.List(ctx, tt.filter)
+			if got := ids(bills); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			summaries, _ := repo.ListSummaries(ctx, tt.filter)
+			if len(summaries) != len(tt.want) {
+				t.Errorf("expected %d summaries, got %d", len(tt.want), len(summaries))
+			}
+		})
+	}
+}
+
+func TestForEach(t *testing.T) {
+	repo := newBenchmarkRepository(1000)
+	closed := BillFilter{OrgID: "org_1", Statuses: []model.BillStatus{model.BillStatusClosed}}
+
+	visited := 0
+	repo.ForEach(closed, func(bill *model.Bill) bool {
+		if bill.Status != model.BillStatusClosed {
+			t.Errorf("expected only closed bills, got %s", bill.Status)
+		}
+		visited++
+		return true
+	})
+	if visited != 10 {
+		t.Errorf("expected 10 closed bills, visited %d", visited)
+	}
+	if count, _ := repo.Count(context.Background(), closed); count != 10 {
+		t.Errorf("expected Count to agree with ForEach, got %d", count)
+	}
+
+	visited = 0
+	repo.ForEach(BillFilter{}, func(bill *model.Bill) bool {
+		visited++
+		return visited < 3
+	})
+	if visited != 3 {
+		t.Errorf("expected ForEach to stop after fn returned false, visited %d", visited)
+	}
+}
+
+func TestReadsDoNotShareStoredBills(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryBillRepository()
+	repo.Create(ctx, &model.Bill{ID: "bill_1", OrgID: "org_1", TotalAmount: 100})
+
+	bill, _ := repo.Get(ctx, "org_1", "bill_1")
+	bill.TotalAmount = 999
+	listed, _ := repo.List(ctx, BillFilter{})
+	listed[0].TotalAmount = 999
+
+	if stored, _ := repo.Get(ctx, "org_1", "bill_1"); stored.TotalAmount != 100 {
+		t.Errorf("expected the stored bill to be unaffected by callers' changes, got %d", stored.TotalAmount)
+	}
+}
+
+func TestReadsSeeConsistentBillsDuringWrites(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryBillRepository()
+	repo.Create(ctx, &model.Bill{ID: "bill_1", OrgID: "org_1", Status: model.BillStatusOpen})
+
+	const writes = 200
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < writes; i++ {
+			repo.WithTransaction(ctx, func(tx BillRepository) error {
+				bill, _ := tx.Get(ctx, "org_1", "bill_1")
+				bill.LineItems = append(bill.LineItems, model.LineItem{
+					ID:              fmt.Sprintf("item_%d", i),
+					ConvertedAmount: 100,
+					Metadata:        map[string]string{"seq": fmt.Sprint(i)},
+				})
+				bill.TotalAmount += 100
+				return tx.Update(ctx, bill)
+			})
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				bills, _ := repo.List(ctx, BillFilter{})
+				bill, _ := repo.Get(ctx, "org_1", "bill_1")
+				for _, read := range []*model.Bill{&bills[0], bill} {
+					var sum int64
+					for _, item := range read.LineItems {
+						sum += item.ConvertedAmount
+					}
+					if sum != read.TotalAmount {
+						t.Errorf("torn read: line items sum to %d, total is %d", sum, read.TotalAmount)
+						return
+					}
+					// Callers may scribble on what they read without affecting anyone else
+					for j := range read.LineItems {
+						read.LineItems[j].ConvertedAmount = 0
+						read.LineItems[j].Metadata["seq"] = "changed"
+					}
+					read.LineItems = append(read.LineItems, model.LineItem{ConvertedAmount: 1})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	bill, _ := repo.Get(ctx, "org_1", "bill_1")
+	if len(bill.LineItems) != writes || bill.TotalAmount != writes*100 {
+		t.Fatalf("expected %d items totalling %d, got %d totalling %d", writes, writes*100, len(bill.LineItems), bill.TotalAmount)
+	}
+	for i, item := range bill.LineItems {
+		if item.ConvertedAmount != 100 || item.Metadata["seq"] != fmt.Sprint(i) {
+			t.Fatalf("expected stored item %d unchanged by readers, got %+v", i, item)
+		}
+	}
+}
+
+func TestReadsOrderLineItemsBySequence(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryBillRepository()
+	// As a SQL repository might hand rows back, out of insertion order
+	repo.Create(ctx, &model.Bill{ID: "bill_1", OrgID: "org_1", LineItems: []model.LineItem{
+		{ID: "c", Sequence: 3}, {ID: "a", Sequence: 1}, {ID: "d", Sequence: 4}, {ID: "b", Sequence: 2},
+	}})
+
+	bill, _ := repo.Get(ctx, "org_1", "bill_1")
+	listed, _ := repo.List(ctx, BillFilter{})
+	var staged *model.Bill
+	repo.WithTransaction(ctx, func(tx BillRepository) error {
+		staged, _ = tx.Get(ctx, "org_1", "bill_1")
+		return nil
+	})
+
+	for name, got := range map[string]*model.Bill{"Get": bill, "List": &listed[0], "tx.Get": staged} {
+		var ids string
+		for _, item := range got.LineItems {
+			ids += item.ID
+		}
+		if ids != "abcd" {
+			t.Errorf("%s: expected line items in sequence order abcd, got %s", name, ids)
+		}
+	}
+}
+
+// newBenchmarkRepository fills a repository with n bills of 3 line items each,
+// 1 in 100 of them closed
+func newBenchmarkRepository(n int) *InMemoryBillRepository {
+	ctx := context.Background()
+	repo := NewInMemoryBillRepository()
+	for i := 0; i < n; i++ {
+		status := model.BillStatusOpen
+		if i%100 == 0 {
+			status = model.BillStatusClosed
+		}
+		repo.Create(ctx, &model.Bill{
+			ID:          fmt.Sprintf("bill_%d", i),
+			OrgID:       "org_1",
+			Status:      status,
+			Currency:    model.CurrencyUSD,
+			TotalAmount: 300,
+			LineItems:   make([]model.LineItem, 3),
+		})
+	}
+	return repo
+}
+
+func BenchmarkList(b *testing.B) {
+	ctx := context.Background()
+	repo := newBenchmarkRepository(100_000)
+	filter := BillFilter{OrgID: "org_1", Statuses: []model.BillStatus{model.BillStatusClosed}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		repo.List(ctx, filter)
+	}
+}
+
+func BenchmarkSumTotals(b *testing.B) {
+	ctx := context.Background()
+	repo := newBenchmarkRepository(100_000)
+	filter := BillFilter{OrgID: "org_1"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		repo.SumTotals(ctx, filter)
+	}
+}
+
+func TestQueryBills(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryBillRepository()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, bill := range []model.Bill{
+		{ID: "bill_1", OrgID: "org_1", Currency: model.CurrencyUSD, Status: model.BillStatusOpen, CreatedAt: base},
+		{ID: "bill_2", OrgID: "org_1", Currency: model.CurrencyGEL, Status: model.BillStatusOpen, CreatedAt: base.Add(time.Hour)},
+		{ID: "bill_3", OrgID: "org_1", Currency: model.CurrencyGEL, Status: model.BillStatusClosed, CreatedAt: base.Add(2 * time.Hour)},
+		{ID: "bill_4", OrgID: "org_2", Currency: model.CurrencyGEL, Status: model.BillStatusOpen, CreatedAt: base.Add(time.Hour)},
+	} {
+		repo.Create(ctx, &bill)
+	}
+
+	query := BillQuery{
+		OrgID:       "org_1",
+		Currencies:  []model.Currency{model.CurrencyGEL},
+		CreatedFrom: base.Add(time.Hour),
+	}
+	ids := func(bills []model.Bill) []string {
+		var result []string
+		for _, bill := range bills {
+			result = append(result, bill.ID)
+		}
+		return result
+	}
+
+	bills, err := repo.QueryBills(ctx, query)
+	if err != nil {
+		t.Fatalf("QueryBills() error = %v", err)
+	}
+	if got, want := ids(bills), []string{"bill_2", "bill_3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// A transaction sees its own staged writes through the same query
+	repo.WithTransaction(ctx, func(tx BillRepository) error {
+		tx.Create(ctx, &model.Bill{ID: "bill_5", OrgID: "org_1", Currency: model.CurrencyGEL, CreatedAt: base.Add(3 * time.Hour)})
+		query.Statuses = []model.BillStatus{model.BillStatusOpen, ""}
+		bills, _ := tx.QueryBills(ctx, query)
+		if got, want := ids(bills), []string{"bill_2", "bill_5"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v in the transaction, got %v", want, got)
+		}
+		return nil
+	})
+	if bills, _ := repo.List(ctx, BillFilter{OrgID: "org_1"}); len(bills) != 4 {
+		t.Errorf("expected the committed bill to be indexed under its org, got %d bills", len(bills))
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"fees-api/internal/model"
+)
+
+// BillTemplateRepository defines the interface for bill template storage
+type BillTemplateRepository interface {
+	Create(ctx context.Context, template *model.BillTemplate) error
+	// Get returns nil, nil when the template doesn't exist
+	Get(ctx context.Context, id string) (*model.BillTemplate, error)
+}
+
+// InMemoryBillTemplateRepository is an in-memory implementation of BillTemplateRepository
+type InMemoryBillTemplateRepository struct {
+	mu        sync.RWMutex
+	templates map[string]model.BillTemplate
+}
+
+// NewInMemoryBillTemplateRepository creates a new in-memory bill template repository
+func NewInMemoryBillTemplateRepository() *InMemoryBillTemplateRepository {
+	return &InMemoryBillTemplateRepository{
+		templates: make(map[string]model.BillTemplate),
+	}
+}
+
+// Create stores a new template
+func (r *InMemoryBillTemplateRepository) Create(ctx context.Context, template *model.BillTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[template.ID] = *template
+	return nil
+}
+
+// Get retrieves a template by ID
+func (r *InMemoryBillTemplateRepository) Get(ctx context.Context, id string) (*model.BillTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	template, ok := r.templates[id]
+	if !ok {
+		return nil, nil
+	}
+	return &template, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"fees-api/internal/events"
+)
+
+// EventStore defines the interface for persisting bill events
+type EventStore interface {
+	Append(ctx context.Context, event events.BillEvent) error
+	// ListByBill returns a bill's events ordered by the time they occurred
+	ListByBill(ctx context.Context, billID string) ([]events.BillEvent, error)
+}
+
+// InMemoryEventStore is an in-memory implementation of EventStore
+type InMemoryEventStore struct {
+	mu     sync.RWMutex
+	events map[string][]events.BillEvent
+}
+
+// NewInMemoryEventStore creates a new in-memory event store
+func NewInMemoryEventStore() *InMemoryEventStore {
+	return &InMemoryEventStore{
+		events: make(map[string][]events.BillEvent),
+	}
+}
+
+// Append stores an event
+func (s *InMemoryEventStore) Append(ctx context.Context, event events.BillEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[event.BillID] = append(s.events[event.BillID], event)
+	return nil
+}
+
+// ListByBill returns a bill's events ordered by time, preserving append order for ties
+func (s *InMemoryEventStore) ListByBill(ctx context.Context, billID string) ([]events.BillEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]events.BillEvent, len(s.events[billID]))
+	copy(result, s.events[billID])
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].OccurredAt.Before(result[j].OccurredAt)
+	})
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// InvoiceSequence issues the numbers behind invoice numbers: per year, counting
+// from 1 with no gaps. A number is reserved before the close that uses it is stored,
+// so a close that then fails releases it for the next one.
+type InvoiceSequence interface {
+	// Next reserves the year's next number
+	Next(ctx context.Context, year int) (int64, error)
+	// Release gives back a reserved number that went unused. Only the year's most
+	// recent number can be released; releasing any other fails, since reissuing it
+	// would leave a gap or a duplicate.
+	Release(ctx context.Context, year int, number int64) error
+}
+
+// InMemoryInvoiceSequence is an in-memory implementation of InvoiceSequence
+type InMemoryInvoiceSequence struct {
+	mu   sync.Mutex
+	last map[int]int64 // by year
+}
+
+// NewInMemoryInvoiceSequence creates a new in-memory invoice sequence
+func NewInMemoryInvoiceSequence() *InMemoryInvoiceSequence {
+	return &InMemoryInvoiceSequence{last: make(map[int]int64)}
+}
+
+// Next reserves the year's next number
+func (s *InMemoryInvoiceSequence) Next(ctx context.Context, year int) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last[year]++
+	return s.last[year], nil
+}
+
+// Release gives back the year's most recent number
+func (s *InMemoryInvoiceSequence) Release(ctx context.Context, year int, number int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last[year] != number {
+		return fmt.Errorf("cannot release invoice number %d of %d: %d was issued since", number, year, s.last[year])
+	}
+	s.last[year]--
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"fees-api/internal/model"
+)
+
+// ProcessorRefRepository defines the interface for processor reference links
+type ProcessorRefRepository interface {
+	// Put creates or replaces the link for the reference
+	Put(ctx context.Context, link *model.ProcessorRef) error
+	// Get returns nil, nil when the reference isn't linked
+	Get(ctx context.Context, ref string) (*model.ProcessorRef, error)
+}
+
+// InMemoryProcessorRefRepository is an in-memory implementation of ProcessorRefRepository
+type InMemoryProcessorRefRepository struct {
+	mu    sync.RWMutex
+	links map[string]model.ProcessorRef
+}
+
+// NewInMemoryProcessorRefRepository creates a new in-memory processor reference repository
+func NewInMemoryProcessorRefRepository() *InMemoryProcessorRefRepository {
+	return &InMemoryProcessorRefRepository{
+		links: make(map[string]model.ProcessorRef),
+	}
+}
+
+// Put stores the link for its reference
+func (r *InMemoryProcessorRefRepository) Put(ctx context.Context, link *model.ProcessorRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.links[link.Ref] = *link
+	return nil
+}
+
+// Get retrieves the link for a reference
+func (r *InMemoryProcessorRefRepository) Get(ctx context.Context, ref string) (*model.ProcessorRef, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	link, ok := r.links[ref]
+	if !ok {
+		return nil, nil
+	}
+	return &link, nil
+}
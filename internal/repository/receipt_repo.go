@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"fees-api/internal/model"
+)
+
+// ReceiptRepository stores the receipts issued when bills close. Receipts are
+// immutable, so there is no update.
+type ReceiptRepository interface {
+	// Create stores a new receipt, failing if one with its ID already exists
+	Create(ctx context.Context, receipt *model.Receipt) error
+	// ListByBill returns a bill's receipts in the order they were issued
+	ListByBill(ctx context.Context, orgID, billID string) ([]model.Receipt, error)
+}
+
+// InMemoryReceiptRepository is an in-memory implementation of ReceiptRepository.
+// Receipts are copied in and out, so stored ones are never shared with callers.
+type InMemoryReceiptRepository struct {
+	mu       sync.RWMutex
+	ids      map[string]bool
+	receipts map[string][]model.Receipt // by bill ID, in issue order
+}
+
+// NewInMemoryReceiptRepository creates a new in-memory receipt repository
+func NewInMemoryReceiptRepository() *InMemoryReceiptRepository {
+	return &InMemoryReceiptRepository{
+		ids:      make(map[string]bool),
+		receipts: make(map[string][]model.Receipt),
+	}
+}
+
+// Create stores a new receipt
+func (r *InMemoryReceiptRepository) Create(ctx context.Context, receipt *model.Receipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ids[receipt.ID] {
+		return fmt.Errorf("receipt %s already exists", receipt.ID)
+	}
+	r.ids[receipt.ID] = true
+	r.receipts[receipt.BillID] = append(r.receipts[receipt.BillID], *receipt.Clone())
+	return nil
+}
+
+// ListByBill returns the bill's receipts, oldest first
+func (r *InMemoryReceiptRepository) ListByBill(ctx context.Context, orgID, billID string) ([]model.Receipt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []model.Receipt
+	for _, receipt := range r.receipts[billID] {
+		if receipt.OrgID == orgID {
+			result = append(result, *receipt.Clone())
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"fees-api/internal/model"
+)
+
+// RecurringTemplateRepository defines the interface for recurring bill template storage
+type RecurringTemplateRepository interface {
+	Create(ctx context.Context, template *model.RecurringBillTemplate) error
+	// Get returns nil, nil when the template doesn't exist
+	Get(ctx context.Context, id string) (*model.RecurringBillTemplate, error)
+	Update(ctx context.Context, template *model.RecurringBillTemplate) error
+	List(ctx context.Context) ([]model.RecurringBillTemplate, error)
+}
+
+// InMemoryRecurringTemplateRepository is an in-memory implementation of RecurringTemplateRepository
+type InMemoryRecurringTemplateRepository struct {
+	mu        sync.RWMutex
+	templates map[string]model.RecurringBillTemplate
+}
+
+// NewInMemoryRecurringTemplateRepository creates a new in-memory recurring template repository
+func NewInMemoryRecurringTemplateRepository() *InMemoryRecurringTemplateRepository {
+	return &InMemoryRecurringTemplateRepository{
+		templates: make(map[string]model.RecurringBillTemplate),
+	}
+}
+
+// Create stores a new template
+func (r *InMemoryRecurringTemplateRepository) Create(ctx context.Context, template *model.RecurringBillTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[template.ID] = *template
+	return nil
+}
+
+// Get retrieves a template by ID
+func (r *InMemoryRecurringTemplateRepository) Get(ctx context.Context, id string) (*model.RecurringBillTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	template, ok := r.templates[id]
+	if !ok {
+		return nil, nil
+	}
+	return &template, nil
+}
+
+// Update replaces an existing template
+func (r *InMemoryRecurringTemplateRepository) Update(ctx context.Context, template *model.RecurringBillTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[template.ID] = *template
+	return nil
+}
+
+// List returns all templates ordered by creation time
+func (r *InMemoryRecurringTemplateRepository) List(ctx context.Context) ([]model.RecurringBillTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]model.RecurringBillTemplate, 0, len(r.templates))
+	for _, template := range r.templates {
+		result = append(result, template)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
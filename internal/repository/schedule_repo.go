@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"fees-api/internal/model"
+)
+
+// BillingScheduleRepository defines the interface for billing schedule storage
+type BillingScheduleRepository interface {
+	Create(ctx context.Context, schedule *model.BillingSchedule) error
+	// Get returns nil, nil when the schedule doesn't exist
+	Get(ctx context.Context, id string) (*model.BillingSchedule, error)
+	// GetByCustomer returns the org's schedule for the customer, or nil, nil
+	GetByCustomer(ctx context.Context, orgID, customerID string) (*model.BillingSchedule, error)
+	Update(ctx context.Context, schedule *model.BillingSchedule) error
+	Delete(ctx context.Context, id string) error
+}
+
+// InMemoryBillingScheduleRepository is an in-memory implementation of BillingScheduleRepository
+type InMemoryBillingScheduleRepository struct {
+	mu        sync.RWMutex
+	schedules map[string]model.BillingSchedule
+}
+
+// NewInMemoryBillingScheduleRepository creates a new in-memory billing schedule repository
+func NewInMemoryBillingScheduleRepository() *InMemoryBillingScheduleRepository {
+	return &InMemoryBillingScheduleRepository{
+		schedules: make(map[string]model.BillingSchedule),
+	}
+}
+
+// Create stores a new schedule
+func (r *InMemoryBillingScheduleRepository) Create(ctx context.Context, schedule *model.BillingSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schedules[schedule.ID] = *schedule
+	return nil
+}
+
+// Get retrieves a schedule by ID
+func (r *InMemoryBillingScheduleRepository) Get(ctx context.Context, id string) (*model.BillingSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schedule, ok := r.schedules[id]
+	if !ok {
+		return nil, nil
+	}
+	return &schedule, nil
+}
+
+// GetByCustomer retrieves an org's schedule for a customer
+func (r *InMemoryBillingScheduleRepository) GetByCustomer(ctx context.Context, orgID, customerID string) (*model.BillingSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, schedule := range r.schedules {
+		if schedule.OrgID == orgID && schedule.CustomerID == customerID {
+			return &schedule, nil
+		}
+	}
+	return nil, nil
+}
+
+// Update replaces an existing schedule
+func (r *InMemoryBillingScheduleRepository) Update(ctx context.Context, schedule *model.BillingSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schedules[schedule.ID] = *schedule
+	return nil
+}
+
+// Delete removes a schedule
+func (r *InMemoryBillingScheduleRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.schedules, id)
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"fees-api/internal/model"
+)
+
+// WebhookRepository defines the interface for webhook endpoint storage
+type WebhookRepository interface {
+	Create(endpoint *model.WebhookEndpoint) error
+	Get(id string) (*model.WebhookEndpoint, error)
+	Delete(id string) (bool, error)
+	List() ([]model.WebhookEndpoint, error)
+}
+
+// InMemoryWebhookRepository is an in-memory implementation of WebhookRepository
+type InMemoryWebhookRepository struct {
+	mu        sync.RWMutex
+	endpoints map[string]model.WebhookEndpoint
+}
+
+// NewInMemoryWebhookRepository creates a new in-memory webhook repository
+func NewInMemoryWebhookRepository() *InMemoryWebhookRepository {
+	return &InMemoryWebhookRepository{
+		endpoints: make(map[string]model.WebhookEndpoint),
+	}
+}
+
+// Create stores a new webhook endpoint
+func (r *InMemoryWebhookRepository) Create(endpoint *model.WebhookEndpoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endpoints[endpoint.ID] = *endpoint
+	return nil
+}
+
+// Get retrieves a webhook endpoint by ID
+func (r *InMemoryWebhookRepository) Get(id string) (*model.WebhookEndpoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	endpoint, ok := r.endpoints[id]
+	if !ok {
+		return nil, nil
+	}
+	return &endpoint, nil
+}
+
+// Delete removes a webhook endpoint, reporting whether it existed
+func (r *InMemoryWebhookRepository) Delete(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.endpoints[id]; !ok {
+		return false, nil
+	}
+	delete(r.endpoints, id)
+	return true, nil
+}
+
+// List returns all webhook endpoints ordered by creation time
+func (r *InMemoryWebhookRepository) List() ([]model.WebhookEndpoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]model.WebhookEndpoint, 0, len(r.endpoints))
+	for _, endpoint := range r.endpoints {
+		result = append(result, endpoint)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
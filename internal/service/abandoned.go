@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+)
+
+// AbandonedBillPolicy selects the bills VoidAbandonedBills voids: bills in one of
+// Statuses that still have no line items TTL after they were created
+type AbandonedBillPolicy struct {
+	TTL      time.Duration
+	Statuses []model.BillStatus // draft and/or open; defaults to draft only
+}
+
+// statuses returns the policy's statuses, defaulting to drafts
+func (p AbandonedBillPolicy) statuses() []model.BillStatus {
+	if len(p.Statuses) == 0 {
+		return []model.BillStatus{model.BillStatusDraft}
+	}
+	return p.Statuses
+}
+
+// Validate checks the policy has a TTL and only expires draft or open bills
+func (p AbandonedBillPolicy) Validate() error {
+	if p.TTL <= 0 {
+		return fmt.Errorf("abandoned bill TTL must be positive, got %s", p.TTL)
+	}
+	for _, status := range p.Statuses {
+		if status != model.BillStatusDraft && status != model.BillStatusOpen {
+			return fmt.Errorf("only draft and open bills can be voided as abandoned, got %q", status)
+		}
+	}
+	return nil
+}
+
+// VoidAbandonedBills soft-deletes, across every org, the empty bills the policy
+// selects and publishes an auto_voided event for each. Candidates come from a single
+// repository query; each is then re-checked in its own transaction, so a bill that
+// gained a line item in the meantime is kept. It returns the voided bills.
+func (s *BillingService) VoidAbandonedBills(ctx context.Context, policy AbandonedBillPolicy) ([]model.Bill, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	filter := repository.BillFilter{
+		Statuses:      policy.statuses(),
+		NoLineItems:   true,
+		CreatedBefore: now.Add(-policy.TTL),
+	}
+	candidates, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list abandoned bills: %w", err)
+	}
+
+	var voided []model.Bill
+	for _, candidate := range candidates {
+		var bill *model.Bill
+		err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+			var err error
+			if bill, err = tx.Get(ctx, candidate.OrgID, candidate.ID); err != nil || bill == nil {
+				return err
+			}
+			if !filter.Matches(bill) {
+				bill = nil
+				return nil
+			}
+			bill.DeletedAt = &now
+			return saveBill(ctx, tx, bill)
+		})
+		if err != nil {
+			return voided, fmt.Errorf("void abandoned bill %s: %w", candidate.ID, err)
+		}
+		if bill == nil {
+			continue
+		}
+
+		s.logger.Info("abandoned bill voided", "bill_id", bill.ID, "org_id", bill.OrgID, "status", bill.Status,
+			"created_at", bill.CreatedAt)
+		s.publish(ctx, events.NewBillEvent(events.EventBillAutoVoided, bill))
+		voided = append(voided, *bill)
+	}
+	return voided, nil
+}
+
+// AbandonedBillSweeper runs VoidAbandonedBills in the background on an interval
+type AbandonedBillSweeper struct {
+	bills    *BillingService
+	policy   AbandonedBillPolicy
+	interval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewAbandonedBillSweeper creates a sweeper voiding bills under policy every interval
+func NewAbandonedBillSweeper(bills *BillingService, policy AbandonedBillPolicy, interval time.Duration) (*AbandonedBillSweeper, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("sweep interval must be positive, got %s", interval)
+	}
+	return &AbandonedBillSweeper{
+		bills:    bills,
+		policy:   policy,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Start begins sweeping in a goroutine until Shutdown
+func (s *AbandonedBillSweeper) Start() {
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if _, err := s.bills.VoidAbandonedBills(context.Background(), s.policy); err != nil {
+					s.bills.logger.Error("abandoned bill sweep failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Shutdown stops the sweeper and waits for a sweep in progress to finish or ctx to
+// be done. It must only be called after Start.
+func (s *AbandonedBillSweeper) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
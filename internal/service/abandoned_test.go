@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestVoidAbandonedBills(t *testing.T) {
+	ctx := testContext()
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	topic := events.NewTopic()
+	var voidedEvents []string
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		if event.Type == events.EventBillAutoVoided {
+			voidedEvents = append(voidedEvents, event.BillID)
+		}
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock), WithPublisher(topic))
+
+	staleDraft, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+	otherOrgDraft, _ := svc.CreateBill(tenant.WithOrgID(context.Background(), "org_other"), &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+	staleOpen, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	nonEmptyDraft, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+	svc.AddLineItem(ctx, nonEmptyDraft.ID, &model.AddLineItemRequest{Description: "Setup", Amount: 10.00, Currency: model.CurrencyUSD})
+
+	clock.Advance(25 * time.Hour)
+	recentDraft, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+
+	voided, err := svc.VoidAbandonedBills(ctx, AbandonedBillPolicy{TTL: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("VoidAbandonedBills() error = %v", err)
+	}
+	want := []string{staleDraft.ID, otherOrgDraft.ID}
+	sort.Strings(want)
+	if got := billIDs(voided); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected drafts %v voided, got %v", want, got)
+	}
+	sort.Strings(voidedEvents)
+	if !reflect.DeepEqual(voidedEvents, want) {
+		t.Errorf("expected auto_voided events for %v, got %v", want, voidedEvents)
+	}
+
+	if _, err := svc.GetBill(ctx, staleDraft.ID, false); billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+		t.Errorf("expected the stale draft to be hidden, got %v", err)
+	}
+	for _, kept := range []string{staleOpen.ID, nonEmptyDraft.ID, recentDraft.ID} {
+		if _, err := svc.GetBill(ctx, kept, false); err != nil {
+			t.Errorf("expected bill %s to be kept, got %v", kept, err)
+		}
+	}
+
+	// Open bills expire only when the policy includes them; voided bills aren't voided again
+	voidedEvents = nil
+	voided, err = svc.VoidAbandonedBills(ctx, AbandonedBillPolicy{
+		TTL:      24 * time.Hour,
+		Statuses: []model.BillStatus{model.BillStatusDraft, model.BillStatusOpen},
+	})
+	if err != nil {
+		t.Fatalf("VoidAbandonedBills() error = %v", err)
+	}
+	if got := billIDs(voided); !reflect.DeepEqual(got, []string{staleOpen.ID}) || !reflect.DeepEqual(voidedEvents, []string{staleOpen.ID}) {
+		t.Errorf("expected only %s voided, got %v and events %v", staleOpen.ID, got, voidedEvents)
+	}
+}
+
+func TestAbandonedBillPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  AbandonedBillPolicy
+		wantErr bool
+	}{
+		{name: "drafts by default", policy: AbandonedBillPolicy{TTL: time.Hour}},
+		{name: "drafts and open", policy: AbandonedBillPolicy{TTL: time.Hour, Statuses: []model.BillStatus{model.BillStatusDraft, model.BillStatusOpen}}},
+		{name: "no TTL", policy: AbandonedBillPolicy{}, wantErr: true},
+		{name: "closed bills", policy: AbandonedBillPolicy{TTL: time.Hour, Statuses: []model.BillStatus{model.BillStatusClosed}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAbandonedBillSweeper(t *testing.T) {
+	ctx := testContext()
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	topic := events.NewTopic()
+	voided := make(chan string, 1)
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		if event.Type == events.EventBillAutoVoided {
+			voided <- event.BillID
+		}
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock), WithPublisher(topic))
+	draft, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+	clock.Advance(2 * time.Hour)
+
+	sweeper, err := NewAbandonedBillSweeper(svc, AbandonedBillPolicy{TTL: time.Hour}, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewAbandonedBillSweeper() error = %v", err)
+	}
+	sweeper.Start()
+
+	select {
+	case id := <-voided:
+		if id != draft.ID {
+			t.Errorf("expected %s voided, got %s", draft.ID, id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the sweeper to void the stale draft")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := sweeper.Shutdown(shutdownCtx); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+func billIDs(bills []model.Bill) []string {
+	ids := make([]string, len(bills))
+	for i, bill := range bills {
+		ids[i] = bill.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"fees-api/internal/model"
+)
+
+// accumulationRates are the rates the accumulation tests convert at
+func accumulationRates() *StaticRateProvider {
+	return NewStaticRateProvider(map[model.Currency]float64{
+		model.CurrencyGEL: 0.37,
+		model.CurrencyUSD: 1.0,
+	})
+}
+
+// expectedNet computes, independently of the service, what a line item of the given
+// cents adds to a bill's total: converted at the quoted rate and rounded once to
+// the cent, plus the FX markup on cross-currency charges, minus for credits
+func expectedNet(t testing.TB, rates ExchangeRateProvider, cents int64, from, to model.Currency, credit bool, markup float64) int64 {
+	t.Helper()
+	quote, err := rates.Quote(from, to)
+	if err != nil {
+		t.Fatalf("Quote(%s, %s) error = %v", from, to, err)
+	}
+	converted := int64(math.Round(float64(cents) * quote.Rate))
+	if credit {
+		return -converted
+	}
+	if from != to {
+		converted += int64(math.Round(float64(converted) * markup))
+	}
+	return converted
+}
+
+// TestTotalAccumulationIsExact adds thousands of random amounts in mixed currencies
+// and checks the bill's total is exactly the sum of what each item should add, so
+// no float drift creeps into conversion or accumulation
+func TestTotalAccumulationIsExact(t *testing.T) {
+	const (
+		items  = 2000
+		markup = 0.025
+	)
+	currencies := []model.Currency{model.CurrencyUSD, model.CurrencyGEL}
+
+	for _, seed := range []int64{1, 2, 3} {
+		for _, billCurrency := range currencies {
+			t.Run(fmt.Sprintf("seed %d %s", seed, billCurrency), func(t *testing.T) {
+				ctx := testContext()
+				rng := rand.New(rand.NewSource(seed))
+				rates := accumulationRates()
+				svc := newTestBillingService(t, newMockBillRepository(),
+					WithRateProvider(rates), WithFXMarkup(markup), WithMaxLineItems(items))
+
+				bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: billCurrency})
+				var want int64
+				for i := 0; i < items; i++ {
+					cents := 1 + rng.Int63n(10_000_000) // up to 100,000.00
+					currency := currencies[rng.Intn(len(currencies))]
+					// Credits only once the total can cover them at any rate
+					credit := rng.Intn(5) == 0 && want > cents*3
+					itemType := model.LineItemTypeCharge
+					if credit {
+						itemType = model.LineItemTypeCredit
+					}
+
+					var err error
+					bill, err = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{
+						Description: "Fee",
+						Amount:      float64(cents) / 100,
+						Currency:    currency,
+						Type:        itemType,
+						Force:       true,
+					})
+					if err != nil {
+						t.Fatalf("item %d: AddLineItem() error = %v", i, err)
+					}
+					if added := bill.LineItems[len(bill.LineItems)-1]; added.Amount != cents {
+						t.Fatalf("item %d: expected %d cents stored, got %d", i, cents, added.Amount)
+					}
+
+					want += expectedNet(t, rates, cents, currency, billCurrency, credit, markup)
+				}
+
+				if bill.TotalAmount != want {
+					t.Errorf("expected total %d cents, got %d (off by %d)", want, bill.TotalAmount, bill.TotalAmount-want)
+				}
+				recalculated, _, err := svc.RecalculateTotal(ctx, bill.ID)
+				if err != nil {
+					t.Fatalf("RecalculateTotal() error = %v", err)
+				}
+				if recalculated.TotalAmount != want {
+					t.Errorf("expected recalculated total %d cents, got %d", want, recalculated.TotalAmount)
+				}
+			})
+		}
+	}
+}
+
+// FuzzAddLineItem checks that a single line item adds exactly its expected amount
+// to a bill's total, whatever the amount and currency
+func FuzzAddLineItem(f *testing.F) {
+	f.Add(int64(1), false, false)
+	f.Add(int64(10), true, false)
+	f.Add(int64(333), true, true)
+	f.Add(int64(99_999_999), false, true)
+	f.Add(int64(123_456_789_01), true, false)
+
+	f.Fuzz(func(t *testing.T, cents int64, gel, credit bool) {
+		// Amounts arrive as float64 units; near 2^53 cents they no longer round-trip
+		// to whole cents, a limit of the request type rather than of the sum, so
+		// stay within a trillion units
+		if cents <= 0 || cents > 100_000_000_000_000 {
+			t.Skip()
+		}
+		ctx := testContext()
+		rates := accumulationRates()
+		svc := newTestBillingService(t, newMockBillRepository(), WithRateProvider(rates), WithFXMarkup(0.025))
+
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		// A base charge large enough that any credit can be applied
+		base := cents * 3
+		bill, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Base", Amount: float64(base) / 100, Currency: model.CurrencyUSD})
+		if err != nil {
+			t.Skip()
+		}
+
+		currency := model.CurrencyUSD
+		if gel {
+			currency = model.CurrencyGEL
+		}
+		itemType := model.LineItemTypeCharge
+		if credit {
+			itemType = model.LineItemTypeCredit
+		}
+		before := bill.TotalAmount
+		bill, err = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{
+			Description: "Fee",
+			Amount:      float64(cents) / 100,
+			Currency:    currency,
+			Type:        itemType,
+			Force:       true,
+		})
+		if err != nil {
+			t.Fatalf("AddLineItem() error = %v", err)
+		}
+
+		want := before + expectedNet(t, rates, cents, currency, model.CurrencyUSD, credit, 0.025)
+		if bill.TotalAmount != want {
+			t.Errorf("expected total %d cents, got %d", want, bill.TotalAmount)
+		}
+	})
+}
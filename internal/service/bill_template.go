@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// CreateBillTemplate validates and registers a bill template for the caller's org
+func (s *BillingService) CreateBillTemplate(ctx context.Context, req *model.CreateBillTemplateRequest) (*model.BillTemplate, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if req.Currency == "" {
+		req.Currency = model.CurrencyUSD
+	}
+	if !req.Currency.IsSupported() {
+		return nil, billingerrors.UnsupportedCurrency(string(req.Currency))
+	}
+	if len(req.LineItems) == 0 {
+		return nil, fmt.Errorf("at least one line item is required")
+	}
+	if len(req.LineItems) > s.maxLineItems {
+		return nil, fmt.Errorf("a template may hold at most %d line items", s.maxLineItems)
+	}
+	now := s.clock.Now()
+	for i := range req.LineItems {
+		if _, err := newLineItem(&req.LineItems[i], now); err != nil {
+			return nil, fmt.Errorf("line item %d: %w", i, err)
+		}
+	}
+
+	template := &model.BillTemplate{
+		ID:        fmt.Sprintf("tpl_%d", now.UnixNano()),
+		OrgID:     orgID,
+		Name:      req.Name,
+		Currency:  req.Currency,
+		LineItems: append([]model.AddLineItemRequest{}, req.LineItems...),
+		CreatedAt: now,
+	}
+	if err := s.templates.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// GetBillTemplate retrieves one of the caller org's bill templates by ID
+func (s *BillingService) GetBillTemplate(ctx context.Context, templateID string) (*model.BillTemplate, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.templates.Get(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil || template.OrgID != orgID {
+		return nil, billingerrors.BillTemplateNotFound(templateID)
+	}
+	return template, nil
+}
+
+// applyTemplate adds the template's line items to a new bill, converting each to
+// the bill's currency. The bill must be in the template's currency.
+func (s *BillingService) applyTemplate(bill *model.Bill, template *model.BillTemplate) error {
+	if bill.Currency != template.Currency {
+		return billingerrors.TemplateCurrencyMismatch(template.ID, string(template.Currency), string(bill.Currency))
+	}
+	for i := range template.LineItems {
+		req := &template.LineItems[i]
+		item, err := newLineItem(req, bill.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("template line item %d: %w", i, err)
+		}
+		item.ID = s.ids.NewLineItemID()
+		if existing := findRef(bill.LineItems, item.Ref); existing != nil {
+			return billingerrors.DuplicateLineItemRef(bill.ID, item.Ref, existing.ID)
+		}
+		if req.Proratable {
+			if err := prorate(&item, bill, bill.CreatedAt); err != nil {
+				return fmt.Errorf("template line item %d: %w", i, err)
+			}
+		}
+		if bill.TotalAmount, err = s.convertAndAdd(bill.TotalAmount, bill.Currency, &item); err != nil {
+			return err
+		}
+		bill.AppendLineItem(item)
+	}
+	bill.FXFees = sumFXFees(bill.LineItems)
+	if s.noNegativeTotal && bill.TotalAmount < 0 {
+		return billingerrors.CreditExceedsTotal(bill.ID)
+	}
+	return nil
+}
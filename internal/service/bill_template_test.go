@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"fees-api/internal/model"
+)
+
+func TestCreateBillFromTemplate(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	template, err := svc.CreateBillTemplate(ctx, &model.CreateBillTemplateRequest{
+		Name:     "Standard plan",
+		Currency: model.CurrencyUSD,
+		LineItems: []model.AddLineItemRequest{
+			{Description: "Setup fee", Amount: 25.00, Currency: model.CurrencyUSD, Category: model.CategoryProcessing},
+			{Description: "Base subscription", Amount: 100.00, Currency: model.CurrencyGEL, Category: model.CategorySubscription},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBillTemplate() error = %v", err)
+	}
+
+	bill, err := svc.CreateBill(ctx, &model.CreateBillRequest{TemplateID: template.ID})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if bill.Currency != model.CurrencyUSD {
+		t.Errorf("expected the bill to take the template's currency, got %s", bill.Currency)
+	}
+	if len(bill.LineItems) != 2 || bill.LineItems[0].Description != "Setup fee" || bill.LineItems[1].Category != model.CategorySubscription {
+		t.Fatalf("expected the template's line items, got %+v", bill.LineItems)
+	}
+	if bill.TotalAmount != 2500+3700 {
+		t.Errorf("expected total 6200, got %d", bill.TotalAmount)
+	}
+
+	stored, _ := svc.GetBill(ctx, bill.ID, false)
+	if len(stored.LineItems) != 2 || stored.TotalAmount != bill.TotalAmount {
+		t.Errorf("expected the stored bill to hold the template's items, got %d totalling %d", len(stored.LineItems), stored.TotalAmount)
+	}
+
+	if _, err := svc.CreateBill(ctx, &model.CreateBillRequest{TemplateID: template.ID, Currency: model.CurrencyGEL}); err == nil {
+		t.Error("expected a GEL bill from a USD template to be rejected")
+	}
+	if _, err := svc.CreateBill(ctx, &model.CreateBillRequest{TemplateID: "tpl_missing"}); err == nil {
+		t.Error("expected an unknown template to be rejected")
+	}
+}
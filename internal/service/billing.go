@@ -1,183 +1,1821 @@
 package service
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"fees-api/internal/events"
+	"fees-api/internal/logging"
 	"fees-api/internal/model"
 	"fees-api/internal/repository"
+	"fees-api/internal/tenant"
 	billingerrors "fees-api/pkg/errors"
+	"fees-api/pkg/money"
 )
 
-// Exchange rates to USD (base currency)
+// Default exchange rates to USD (base currency)
 var exchangeRatesToUSD = map[model.Currency]float64{
 	model.CurrencyGEL: 0.37, // 1 GEL = 0.37 USD
 	model.CurrencyUSD: 1.0,
 }
 
+// Line item metadata limits
+const (
+	maxMetadataKeys        = model.MaxMetadataKeys
+	maxMetadataKeyLength   = model.MaxMetadataKeyLength
+	maxMetadataValueLength = model.MaxMetadataValueLength
+)
+
+// defaultMaxLineItems caps how many line items a bill may hold unless overridden
+const defaultMaxLineItems = 1000
+
+// maxCloseBatch caps how many bills a single CloseBills call may close
+const maxCloseBatch = 1000
+
+// defaultBillingPeriodDays is the billing period length when none is requested
+const defaultBillingPeriodDays = 30
+
+// defaultMaxBillingPeriodDays caps a billing period's length unless
+// WithMaxBillingPeriodDays sets another cap
+const defaultMaxBillingPeriodDays = 366
+
+// defaultPaymentTerms is how long after closing a bill falls due
+const defaultPaymentTerms = 30 * 24 * time.Hour
+
+// maxNoteLength caps a bill's free-text note, in characters
+const maxNoteLength = model.MaxNoteLength
+
 // BillingService handles business logic for billing
 type BillingService struct {
-	repo repository.BillRepository
+	repo            repository.BillRepository
+	templates       repository.BillTemplateRepository
+	receipts        repository.ReceiptRepository
+	invoices        repository.InvoiceSequence
+	publisher       events.Publisher
+	rates           ExchangeRateProvider
+	metrics         Metrics
+	notifier        Notifier
+	watcher         *BillWatcher // nil unless watching bills is configured
+	logger          logging.Logger
+	clock           Clock
+	ids             IDGenerator
+	allowEmptyClose bool
+	noNegativeTotal bool
+	maxLineItems    int
+	maxPeriodDays   int           // longest billing period a bill may have
+	maxRateAge      time.Duration // zero disables the staleness check
+	staleRatePolicy StaleRatePolicy
+	dedupWindow     time.Duration // zero disables duplicate line item detection
+	fxMarkup        float64       // fraction charged on cross-currency charges, e.g. 0.02
+	maxItemAmount   int64         // cap on a line item's converted amount (cents); zero is unlimited
+	maxAutoClose    int64         // bills with a larger total (cents) need approval to close; zero disables
+	paymentTerms    time.Duration // how long after closing a bill falls due
+}
+
+// Option configures optional BillingService dependencies
+type Option func(*BillingService)
+
+// WithPublisher sets the publisher that receives bill events
+func WithPublisher(publisher events.Publisher) Option {
+	return func(s *BillingService) {
+		s.publisher = publisher
+	}
+}
+
+// WithRateProvider sets the exchange rate provider used for conversions
+func WithRateProvider(rates ExchangeRateProvider) Option {
+	return func(s *BillingService) {
+		s.rates = rates
+	}
 }
 
-// NewBillingService creates a new billing service
-func NewBillingService(repo repository.BillRepository) *BillingService {
-	return &BillingService{repo: repo}
+// WithMetrics sets the metrics recorder for billing operations
+func WithMetrics(metrics Metrics) Option {
+	return func(s *BillingService) {
+		s.metrics = metrics
+	}
+}
+
+// WithNotifier sets who tells customers their bills have closed
+func WithNotifier(notifier Notifier) Option {
+	return func(s *BillingService) {
+		s.notifier = notifier
+	}
 }
 
-// CreateBill creates a new bill
-func (s *BillingService) CreateBill(req *model.CreateBillRequest) (*model.Bill, error) {
+// WithBillWatcher sets the watcher WatchBill waits on. It must be subscribed to
+// the topic the service publishes to.
+func WithBillWatcher(watcher *BillWatcher) Option {
+	return func(s *BillingService) {
+		s.watcher = watcher
+	}
+}
+
+// WithLogger sets the logger for bill lifecycle events and failures
+func WithLogger(logger logging.Logger) Option {
+	return func(s *BillingService) {
+		s.logger = logger
+	}
+}
+
+// WithClock sets the clock used for timestamps and time-based checks
+func WithClock(clock Clock) Option {
+	return func(s *BillingService) {
+		s.clock = clock
+	}
+}
+
+// WithPaymentTerms sets how long after closing a bill falls due
+func WithPaymentTerms(terms time.Duration) Option {
+	return func(s *BillingService) {
+		s.paymentTerms = terms
+	}
+}
+
+// WithIDGenerator sets how IDs for new bills and line items are issued. By default
+// they're derived from the service clock.
+func WithIDGenerator(ids IDGenerator) Option {
+	return func(s *BillingService) {
+		s.ids = ids
+	}
+}
+
+// WithAllowEmptyClose controls whether bills without line items may be closed.
+// Closing an empty bill is rejected by default unless the request opts in.
+func WithAllowEmptyClose(allow bool) Option {
+	return func(s *BillingService) {
+		s.allowEmptyClose = allow
+	}
+}
+
+// WithNoNegativeTotal rejects credits that would take a bill's total below zero
+func WithNoNegativeTotal(enabled bool) Option {
+	return func(s *BillingService) {
+		s.noNegativeTotal = enabled
+	}
+}
+
+// WithBillTemplates sets where bill templates are stored
+func WithBillTemplates(templates repository.BillTemplateRepository) Option {
+	return func(s *BillingService) {
+		s.templates = templates
+	}
+}
+
+// WithReceipts sets the repository receipts are issued into when bills close
+func WithReceipts(receipts repository.ReceiptRepository) Option {
+	return func(s *BillingService) {
+		s.receipts = receipts
+	}
+}
+
+// WithInvoiceSequence sets the sequence invoice numbers are drawn from when bills
+// first close
+func WithInvoiceSequence(invoices repository.InvoiceSequence) Option {
+	return func(s *BillingService) {
+		s.invoices = invoices
+	}
+}
+
+// WithMaxRateAge sets how old an exchange rate quote may be and what to do with
+// quotes older than that: reject the conversion or fall back to the stale rate
+func WithMaxRateAge(maxAge time.Duration, policy StaleRatePolicy) Option {
+	return func(s *BillingService) {
+		s.maxRateAge = maxAge
+		s.staleRatePolicy = policy
+	}
+}
+
+// WithMaxLineItems sets how many line items a single bill may hold
+func WithMaxLineItems(max int) Option {
+	return func(s *BillingService) {
+		s.maxLineItems = max
+	}
+}
+
+// WithMaxBillingPeriodDays sets the longest billing period, in days, a bill may be
+// created or activated with
+func WithMaxBillingPeriodDays(days int) Option {
+	return func(s *BillingService) {
+		s.maxPeriodDays = days
+	}
+}
+
+// WithDedupWindow rejects a line item with the same description, amount and
+// currency as one added to the bill within the window, unless the request sets Force
+func WithDedupWindow(window time.Duration) Option {
+	return func(s *BillingService) {
+		s.dedupWindow = window
+	}
+}
+
+// WithFXMarkup charges a fee of markup (e.g. 0.02 for 2%) on the converted amount
+// of every charge in a currency other than the bill's. The fee is frozen on the line
+// item and accumulated into the bill's FXFees.
+func WithFXMarkup(markup float64) Option {
+	return func(s *BillingService) {
+		s.fxMarkup = markup
+	}
+}
+
+// WithMaxLineItemAmount caps a line item's amount, in cents of the bill's currency
+// after conversion, to catch fat-finger errors. Items over the cap are rejected
+// unless a manager approves them. Zero, the default, leaves amounts unlimited.
+func WithMaxLineItemAmount(maxCents int64) Option {
+	return func(s *BillingService) {
+		s.maxItemAmount = maxCents
+	}
+}
+
+// WithMaxAutoCloseTotal requires approval to close bills whose total is above
+// maxCents, in cents of the bill's currency, for risk control. Such bills only
+// close with an approver on the close request or after ApproveBill. Zero, the
+// default, lets every bill close.
+func WithMaxAutoCloseTotal(maxCents int64) Option {
+	return func(s *BillingService) {
+		s.maxAutoClose = maxCents
+	}
+}
+
+// NewBillingService creates a new billing service. It fails if the exchange rates
+// don't cover every supported currency.
+func NewBillingService(repo repository.BillRepository, opts ...Option) (*BillingService, error) {
+	s := &BillingService{
+		repo:          repo,
+		templates:     repository.NewInMemoryBillTemplateRepository(),
+		receipts:      repository.NewInMemoryReceiptRepository(),
+		invoices:      repository.NewInMemoryInvoiceSequence(),
+		publisher:     events.NopPublisher{},
+		rates:         NewStaticRateProvider(exchangeRatesToUSD),
+		metrics:       NopMetrics{},
+		notifier:      NopNotifier{},
+		logger:        logging.Nop{},
+		clock:         SystemClock{},
+		maxLineItems:  defaultMaxLineItems,
+		maxPeriodDays: defaultMaxBillingPeriodDays,
+		paymentTerms:  defaultPaymentTerms,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.ids == nil {
+		s.ids = NewTimestampIDGenerator(s.clock)
+	}
+	if err := validateRateProvider(s.rates, model.SupportedCurrencies); err != nil {
+		return nil, err
+	}
+	if s.maxPeriodDays <= 0 {
+		return nil, fmt.Errorf("max billing period must be positive, got %d days", s.maxPeriodDays)
+	}
+	if s.fxMarkup < 0 {
+		return nil, fmt.Errorf("fx markup must not be negative, got %v", s.fxMarkup)
+	}
+	if s.maxItemAmount < 0 {
+		return nil, fmt.Errorf("max line item amount must not be negative, got %d", s.maxItemAmount)
+	}
+	if s.maxAutoClose < 0 {
+		return nil, fmt.Errorf("max auto-close total must not be negative, got %d", s.maxAutoClose)
+	}
+	if s.paymentTerms < 0 {
+		return nil, fmt.Errorf("payment terms must not be negative, got %s", s.paymentTerms)
+	}
+	return s, nil
+}
+
+// CreateBill creates a new bill. With a TemplateID, the template's line items are
+// added before the bill is stored, so it never exists without them.
+func (s *BillingService) CreateBill(ctx context.Context, req *model.CreateBillRequest) (*model.Bill, error) {
+	req.Currency = req.Currency.Normalize()
+	var template *model.BillTemplate
+	if req.TemplateID != "" {
+		var err error
+		if template, err = s.GetBillTemplate(ctx, req.TemplateID); err != nil {
+			return nil, err
+		}
+		if req.Currency == "" {
+			req.Currency = template.Currency
+		}
+	}
 	if req.Currency == "" {
 		req.Currency = model.CurrencyUSD
 	}
-	if req.Currency != model.CurrencyGEL && req.Currency != model.CurrencyUSD {
+	if !req.Currency.IsSupported() {
 		return nil, billingerrors.UnsupportedCurrency(string(req.Currency))
 	}
+	if err := validateNote(req.Note); err != nil {
+		return nil, err
+	}
+	if err := validatePeriod(req); err != nil {
+		return nil, err
+	}
+	if err := s.checkPeriodLength(req); err != nil {
+		return nil, err
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if _, err := model.LoadTimezone(req.Timezone); err != nil {
+		return nil, billingerrors.InvalidTimezone(req.Timezone)
+	}
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	status := model.BillStatusOpen
+	if req.Draft {
+		status = model.BillStatusDraft
+	}
 
 	bill := &model.Bill{
-		ID:        generateID(),
-		Status:    model.BillStatusOpen,
+		ID:        s.ids.NewBillID(),
+		OrgID:     orgID,
+		Status:    status,
 		Currency:  req.Currency,
 		LineItems: []model.LineItem{},
-		CreatedAt: time.Now().UTC(),
+		Note:      req.Note,
+		Timezone:  req.Timezone,
+		CreatedAt: now,
+		Version:   1,
+	}
+	if req.PeriodStart != nil {
+		start, end := *req.PeriodStart, *req.PeriodEnd
+		bill.PeriodStart, bill.PeriodEnd = &start, &end
+	} else if status == model.BillStatusOpen {
+		startPeriod(bill, now, req.BillingPeriodDays)
+	}
+	if template != nil {
+		if err := s.applyTemplate(bill, template); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.repo.Create(bill); err != nil {
-		return nil, err
+	if err := s.repo.Create(ctx, bill); err != nil {
+		s.logger.Error("create bill failed", "org_id", orgID, "error", err)
+		return nil, fmt.Errorf("create bill: %w", err)
 	}
 
+	s.logger.Info("bill created", "bill_id", bill.ID, "org_id", orgID, "currency", bill.Currency, "status", bill.Status)
+	s.metrics.IncBillCreated()
+	s.publish(ctx, events.NewBillEvent(events.EventBillCreated, bill))
+
 	return bill, nil
 }
 
 // AddLineItem adds a line item to a bill
-func (s *BillingService) AddLineItem(billID string, req *model.AddLineItemRequest) (*model.Bill, error) {
-	// Input validation
-	if req.Description == "" {
-		return nil, fmt.Errorf("description is required")
+func (s *BillingService) AddLineItem(ctx context.Context, billID string, req *model.AddLineItemRequest) (*model.Bill, error) {
+	lineItem, err := newLineItem(req, s.clock.Now())
+	if err != nil {
+		return nil, err
 	}
-	if len(req.Description) > 500 {
-		return nil, fmt.Errorf("description too long (max 500 characters)")
+	lineItem.ID = s.ids.NewLineItemID()
+
+	var bill *model.Bill
+	err = s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpAddLineItem)
+		if err != nil {
+			return err
+		}
+		if lineItem.Currency == "" {
+			lineItem.Currency = bill.Currency
+		}
+		if len(bill.LineItems)+1 > s.maxLineItems {
+			return billingerrors.TooManyLineItems(billID, s.maxLineItems)
+		}
+		if existing := findRef(bill.LineItems, lineItem.Ref); existing != nil {
+			return billingerrors.DuplicateLineItemRef(billID, lineItem.Ref, existing.ID)
+		}
+		if !req.Force {
+			if existing := s.recentDuplicate(bill, lineItem); existing != nil {
+				return billingerrors.DuplicateLineItem(billID, existing.ID)
+			}
+		}
+		if req.Proratable {
+			if err := prorate(&lineItem, bill, lineItem.CreatedAt); err != nil {
+				return err
+			}
+		}
+
+		// Update total amount (normalized to bill's currency), freezing the rate used
+		bill.TotalAmount, err = s.convertAndAdd(bill.TotalAmount, bill.Currency, &lineItem)
+		if err != nil {
+			return err
+		}
+		if err := s.checkAmountCap(&lineItem, req, bill.Currency); err != nil {
+			return err
+		}
+		if s.noNegativeTotal && bill.TotalAmount < 0 {
+			return billingerrors.CreditExceedsTotal(billID)
+		}
+
+		bill.AppendLineItem(lineItem)
+		bill.FXFees += lineItem.FXFee
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		s.logger.Warn("add line item failed", "bill_id", billID, "error", err)
+		return nil, fmt.Errorf("add line item: %w", err)
 	}
-	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be positive")
+
+	s.logger.Info("line item added", "bill_id", billID, "line_item_id", lineItem.ID,
+		"amount", lineItem.Amount, "currency", lineItem.Currency, "converted_amount", lineItem.ConvertedAmount)
+	s.metrics.IncLineItemAdded(lineItem.Currency)
+
+	event := events.NewBillEvent(events.EventLineItemAdded, bill)
+	event.LineItemID = lineItem.ID
+	s.publish(ctx, event)
+
+	return bill, nil
+}
+
+// ReplaceLineItems swaps a bill's line items for the given set in one atomic update,
+// for integrations that compute the authoritative list of fees externally. Every
+// item is validated before anything changes, and the total is recomputed from the
+// new set only.
+func (s *BillingService) ReplaceLineItems(ctx context.Context, billID string, reqs []model.AddLineItemRequest) (*model.Bill, error) {
+	if len(reqs) > s.maxLineItems {
+		return nil, billingerrors.TooManyLineItems(billID, s.maxLineItems)
+	}
+
+	now := s.clock.Now()
+	lineItems := make([]model.LineItem, len(reqs))
+	for i := range reqs {
+		lineItem, err := newLineItem(&reqs[i], now)
+		if err != nil {
+			return nil, fmt.Errorf("line item %d: %w", i, err)
+		}
+		lineItem.ID = s.ids.NewLineItemID()
+		lineItem.Sequence = i + 1
+		if existing := findRef(lineItems[:i], lineItem.Ref); existing != nil {
+			return nil, billingerrors.DuplicateLineItemRef(billID, lineItem.Ref, existing.ID)
+		}
+		lineItems[i] = lineItem
 	}
 
-	bill, err := s.repo.Get(billID)
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpReplaceLineItems)
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		for i := range lineItems {
+			if reqs[i].Proratable {
+				if err := prorate(&lineItems[i], bill, now); err != nil {
+					return fmt.Errorf("line item %d: %w", i, err)
+				}
+			}
+			if total, err = s.convertAndAdd(total, bill.Currency, &lineItems[i]); err != nil {
+				return err
+			}
+			if err := s.checkAmountCap(&lineItems[i], &reqs[i], bill.Currency); err != nil {
+				return fmt.Errorf("line item %d: %w", i, err)
+			}
+		}
+
+		if s.noNegativeTotal && total < 0 {
+			return billingerrors.CreditExceedsTotal(billID)
+		}
+
+		bill.LineItems = lineItems
+		bill.TotalAmount = total
+		bill.FXFees = sumFXFees(lineItems)
+
+		return saveBill(ctx, tx, bill)
+	})
 	if err != nil {
+		return nil, fmt.Errorf("replace line items: %w", err)
+	}
+
+	for _, lineItem := range lineItems {
+		s.metrics.IncLineItemAdded(lineItem.Currency)
+	}
+	s.publish(ctx, events.NewBillEvent(events.EventLineItemsReplaced, bill))
+
+	return bill, nil
+}
+
+// UpdateLineItem applies a partial update to a line item of a draft or open bill:
+// only the fields the request sets change. A new amount or currency converts the item
+// again at the current rate, like a newly added one, and moves the total by the
+// difference; any other change leaves the total as it is.
+func (s *BillingService) UpdateLineItem(ctx context.Context, billID, lineItemID string, req *model.UpdateLineItemRequest) (*model.Bill, error) {
+	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	if bill == nil {
-		return nil, billingerrors.BillNotFound(billID)
+	if req.Description != nil {
+		if err := validateDescription(*req.Description); err != nil {
+			return nil, err
+		}
 	}
+	if req.Metadata != nil {
+		if err := validateMetadata(*req.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	var bill *model.Bill
+	repriced := false
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpUpdateLineItem)
+		if err != nil {
+			return err
+		}
+		var item *model.LineItem
+		for i := range bill.LineItems {
+			if bill.LineItems[i].ID == lineItemID {
+				item = &bill.LineItems[i]
+			}
+		}
+		if item == nil {
+			return billingerrors.LineItemNotFound(billID, lineItemID)
+		}
+
+		before := item.NetAmount()
+		if req.Description != nil {
+			item.Description = *req.Description
+		}
+		if req.Category != nil {
+			item.Category = *req.Category
+			if item.Category == "" {
+				item.Category = model.CategoryOther
+			}
+		}
+		if req.Metadata != nil {
+			item.Metadata = nil
+			for key, value := range *req.Metadata {
+				if item.Metadata == nil {
+					item.Metadata = make(map[string]string, len(*req.Metadata))
+				}
+				item.Metadata[key] = value
+			}
+		}
+		if req.Amount != nil {
+			if amount := model.ToCents(*req.Amount); amount != item.Amount {
+				// The new amount is the charge as given, no longer a prorated share
+				item.Amount = amount
+				item.FullAmount = nil
+				repriced = true
+			}
+		}
+		if req.Currency != nil {
+			if currency := req.Currency.Normalize(); currency != item.Currency {
+				item.Currency = currency
+				repriced = true
+			}
+		}
 
-	if bill.Status == model.BillStatusClosed {
-		return nil, billingerrors.BillClosed(billID)
+		if repriced {
+			if _, err := s.convertAndAdd(0, bill.Currency, item); err != nil {
+				return err
+			}
+			// An approval covered the old amount, not whatever the item is changed to;
+			// the update may carry one for the new amount
+			item.ApprovedBy = ""
+			if err := s.checkAmountCap(item, &model.AddLineItemRequest{Approved: req.Approved, ApproverID: req.ApproverID}, bill.Currency); err != nil {
+				return err
+			}
+			bill.TotalAmount += item.NetAmount() - before
+			if s.noNegativeTotal && bill.TotalAmount < 0 {
+				return billingerrors.CreditExceedsTotal(billID)
+			}
+			bill.FXFees = sumFXFees(bill.LineItems)
+		}
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		s.logger.Warn("update line item failed", "bill_id", billID, "line_item_id", lineItemID, "error", err)
+		return nil, fmt.Errorf("update line item: %w", err)
 	}
 
-	if req.Currency != model.CurrencyGEL && req.Currency != model.CurrencyUSD {
-		return nil, billingerrors.UnsupportedCurrency(string(req.Currency))
+	s.logger.Info("line item updated", "bill_id", billID, "line_item_id", lineItemID, "repriced", repriced,
+		"total", bill.TotalAmount)
+	event := events.NewBillEvent(events.EventLineItemUpdated, bill)
+	event.LineItemID = lineItemID
+	s.publish(ctx, event)
+
+	return bill, nil
+}
+
+// CloseBill closes a bill. Closing one that is already closed fails with BillClosed,
+// unless the request is idempotent: then the closed bill is returned unchanged, so
+// clients can retry a close safely. Each close issues a receipt, see GetReceipt.
+func (s *BillingService) CloseBill(ctx context.Context, billID string, req *model.CloseBillRequest) (*model.Bill, error) {
+	var bill *model.Bill
+	alreadyClosed := false
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBill(ctx, tx, billID)
+		if err != nil {
+			return err
+		}
+
+		if bill.Status == model.BillStatusClosed {
+			if req != nil && req.Idempotent {
+				alreadyClosed = true
+				return nil
+			}
+			return billingerrors.BillClosed(billID)
+		}
+		return s.closeLoadedBill(ctx, tx, bill, req)
+	})
+	if err != nil {
+		s.logger.Warn("close bill failed", "bill_id", billID, "error", err)
+		return nil, fmt.Errorf("close bill: %w", err)
+	}
+	if alreadyClosed {
+		// A retried close: the bill is returned as it was closed, with no new event
+		return bill, nil
 	}
 
-	// Convert float64 to int64 cents to avoid floating point errors
-	amountCents := floatToCents(req.Amount)
+	s.logger.Info("bill closed", "bill_id", billID, "total", bill.TotalAmount, "currency", bill.Currency,
+		"line_items", len(bill.LineItems))
+	s.metrics.ObserveBillTotal(bill.Currency, bill.TotalAmount)
+	s.issueReceipt(ctx, bill)
+	s.publish(ctx, events.NewBillEvent(events.EventBillClosed, bill))
+	s.notifyClosed(ctx, bill)
 
-	lineItem := model.LineItem{
-		ID:          generateID(),
-		Description: req.Description,
-		Amount:      amountCents,
-		Currency:    req.Currency,
-		CreatedAt:   time.Now().UTC(),
+	return bill, nil
+}
+
+// CloseBills closes a batch of bills, each in its own transaction, for end-of-period
+// runs. A bill that can't be closed doesn't stop the others: every ID gets a result
+// saying whether it was closed, already closed, not found or failed.
+func (s *BillingService) CloseBills(ctx context.Context, billIDs []string, req *model.CloseBillRequest) ([]model.CloseBillResult, error) {
+	if len(billIDs) > maxCloseBatch {
+		return nil, fmt.Errorf("cannot close more than %d bills at once", maxCloseBatch)
 	}
 
-	bill.LineItems = append(bill.LineItems, lineItem)
+	results := make([]model.CloseBillResult, len(billIDs))
+	for i, billID := range billIDs {
+		result := model.CloseBillResult{BillID: billID, Outcome: model.CloseOutcomeClosed}
 
-	// Update total amount (normalized to bill's currency)
-	bill.TotalAmount = s.convertAndAdd(bill.TotalAmount, bill.Currency, amountCents, req.Currency)
+		var bill *model.Bill
+		err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+			var err error
+			bill, err = loadBill(ctx, tx, billID)
+			if err != nil {
+				return err
+			}
 
-	if err := s.repo.Update(bill); err != nil {
-		return nil, err
+			if bill.Status == model.BillStatusClosed {
+				result.Outcome = model.CloseOutcomeAlreadyClosed
+				return nil
+			}
+			return s.closeLoadedBill(ctx, tx, bill, req)
+		})
+
+		switch {
+		case billingerrors.CodeOf(err) == billingerrors.CodeNotFound:
+			result.Outcome = model.CloseOutcomeNotFound
+		case err != nil:
+			result.Outcome = model.CloseOutcomeFailed
+			result.Error = err.Error()
+			s.logger.Warn("close bill failed", "bill_id", billID, "error", err)
+		default:
+			result.Bill = bill
+		}
+		if result.Outcome == model.CloseOutcomeClosed {
+			s.logger.Info("bill closed", "bill_id", billID, "total", bill.TotalAmount, "currency", bill.Currency,
+				"line_items", len(bill.LineItems))
+			s.metrics.ObserveBillTotal(bill.Currency, bill.TotalAmount)
+			s.issueReceipt(ctx, bill)
+			s.publish(ctx, events.NewBillEvent(events.EventBillClosed, bill))
+			s.notifyClosed(ctx, bill)
+		}
+		results[i] = result
 	}
 
-	return bill, nil
+	return results, nil
 }
 
-// CloseBill closes a bill
-func (s *BillingService) CloseBill(billID string) (*model.Bill, error) {
-	bill, err := s.repo.Get(billID)
+// closeLoadedBill closes an open bill within a transaction, snapshotting its final
+// total. A bill closing for the first time is given the next invoice number; one
+// closing again after a reopen keeps the number it already has.
+func (s *BillingService) closeLoadedBill(ctx context.Context, tx repository.BillRepository, bill *model.Bill, req *model.CloseBillRequest) error {
+	if err := s.finalizeBill(bill, req); err != nil {
+		return err
+	}
+	if bill.InvoiceNumber != "" {
+		return saveBill(ctx, tx, bill)
+	}
+
+	year := bill.ClosedAt.In(bill.Location()).Year()
+	number, err := s.invoices.Next(ctx, year)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("next invoice number: %w", err)
 	}
-	if bill == nil {
-		return nil, billingerrors.BillNotFound(billID)
+	bill.InvoiceNumber = formatInvoiceNumber(year, number)
+	if err := saveBill(ctx, tx, bill); err != nil {
+		if releaseErr := s.invoices.Release(ctx, year, number); releaseErr != nil {
+			s.logger.Error("release invoice number failed", "bill_id", bill.ID,
+				"invoice_number", bill.InvoiceNumber, "error", releaseErr)
+		}
+		return err
 	}
+	return nil
+}
+
+// formatInvoiceNumber renders the year's number-th invoice number, e.g. INV-2024-000123
+func formatInvoiceNumber(year int, number int64) string {
+	return fmt.Sprintf("INV-%d-%06d", year, number)
+}
 
-	if bill.Status == model.BillStatusClosed {
-		return nil, billingerrors.BillClosed(billID)
+// finalizeBill turns an open bill into its closed form in memory, without storing
+// it. Closing and previewing a close both go through it, so a preview is exactly
+// what the close would produce.
+func (s *BillingService) finalizeBill(bill *model.Bill, req *model.CloseBillRequest) error {
+	if req == nil {
+		req = &model.CloseBillRequest{}
+	}
+	if err := checkOperation(bill, OpClose); err != nil {
+		return err
+	}
+	if len(bill.LineItems) == 0 && !s.allowEmptyClose && !req.AllowEmpty {
+		return billingerrors.BillEmpty(bill.ID)
 	}
 
-	now := time.Now().UTC()
+	now := s.clock.Now()
+	if err := s.checkCloseApproval(bill, req.ApprovedBy, now); err != nil {
+		return err
+	}
+	finalTotal := bill.TotalAmount
+	finalLineItemCount := len(bill.LineItems)
 	bill.Status = model.BillStatusClosed
 	bill.ClosedAt = &now
+	bill.FinalTotal = &finalTotal
+	bill.FinalLineItemCount = &finalLineItemCount
+	dueDate := now.Add(s.paymentTerms)
+	bill.DueDate = &dueDate
+	return nil
+}
+
+// PreviewClose returns the bill as CloseBill would close it with the same request,
+// without closing it: nothing is stored and no event is published. Only the close
+// reserves an invoice number, so a bill closing for the first time previews without one.
+func (s *BillingService) PreviewClose(ctx context.Context, billID string, req *model.CloseBillRequest) (*model.Bill, error) {
+	bill, err := loadBill(ctx, s.repo, billID)
+	if err != nil {
+		return nil, fmt.Errorf("preview close: %w", err)
+	}
+	if err := s.finalizeBill(bill, req); err != nil {
+		return nil, fmt.Errorf("preview close: %w", err)
+	}
+	bill.Version++ // the version the close would store
+	return bill, nil
+}
+
+// checkCloseApproval lets a bill close if its total is within the auto-close
+// threshold or covered by an approval: one given with the close, recorded on the
+// bill, or an earlier ApproveBill for at least the current total
+func (s *BillingService) checkCloseApproval(bill *model.Bill, approvedBy string, now time.Time) error {
+	if s.maxAutoClose == 0 || bill.TotalAmount <= s.maxAutoClose {
+		return nil
+	}
+	if approvedBy != "" {
+		bill.CloseApproval = &model.CloseApproval{ApprovedBy: approvedBy, Total: bill.TotalAmount, ApprovedAt: now}
+		return nil
+	}
+	if bill.CloseApproval != nil && bill.TotalAmount <= bill.CloseApproval.Total {
+		return nil
+	}
+	return billingerrors.PendingApproval(bill.ID,
+		money.Format(bill.TotalAmount, bill.Currency), money.Format(s.maxAutoClose, bill.Currency))
+}
+
+// ApproveBill approves closing an open bill whose total is above the auto-close
+// threshold, up to its current total. The approval is recorded on the bill; the
+// close itself still happens through CloseBill or the billing period timer.
+func (s *BillingService) ApproveBill(ctx context.Context, billID, approver string) (*model.Bill, error) {
+	if approver == "" {
+		return nil, fmt.Errorf("approve bill: approver is required")
+	}
+
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpApprove)
+		if err != nil {
+			return err
+		}
 
-	if err := s.repo.Update(bill); err != nil {
+		bill.CloseApproval = &model.CloseApproval{ApprovedBy: approver, Total: bill.TotalAmount, ApprovedAt: s.clock.Now()}
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("approve bill: %w", err)
+	}
+
+	s.logger.Info("bill close approved", "bill_id", billID, "approver", approver, "total", bill.TotalAmount)
+	s.publish(ctx, events.NewBillEvent(events.EventBillApproved, bill))
+
+	return bill, nil
+}
+
+// ReopenBill transitions a closed bill back to open, discarding its final snapshot.
+// The snapshot is taken again on the next close.
+func (s *BillingService) ReopenBill(ctx context.Context, billID string) (*model.Bill, error) {
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpReopen)
+		if err != nil {
+			return err
+		}
+
+		bill.Status = model.BillStatusOpen
+		bill.ClosedAt = nil
+		bill.FinalTotal = nil
+		bill.FinalLineItemCount = nil
+		bill.DueDate = nil
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reopen bill: %w", err)
+	}
+
+	s.publish(ctx, events.NewBillEvent(events.EventBillReopened, bill))
+
+	return bill, nil
+}
+
+// ActivateBill transitions a draft bill to open
+func (s *BillingService) ActivateBill(ctx context.Context, billID string, req *model.ActivateBillRequest) (*model.Bill, error) {
+	var periodDays int
+	if req != nil {
+		periodDays = req.BillingPeriodDays
+	}
+
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpActivate)
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkPeriodDays(periodDays); err != nil {
+			return err
+		}
+		bill.Status = model.BillStatusOpen
+		startPeriod(bill, s.clock.Now(), periodDays)
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("activate bill: %w", err)
+	}
+
+	s.publish(ctx, events.NewBillEvent(events.EventBillActivated, bill))
+
+	return bill, nil
+}
+
+// UpdateNote replaces a bill's note. Notes are internal context rather than part
+// of the billed record, so they can be edited on open, draft and closed bills.
+func (s *BillingService) UpdateNote(ctx context.Context, billID string, note string) (*model.Bill, error) {
+	if err := validateNote(note); err != nil {
 		return nil, err
 	}
 
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpUpdateNote)
+		if err != nil {
+			return err
+		}
+
+		bill.Note = note
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update note: %w", err)
+	}
+
 	return bill, nil
 }
 
-// GetBill retrieves a bill by ID
-func (s *BillingService) GetBill(billID string) (*model.Bill, error) {
-	bill, err := s.repo.Get(billID)
+// ChangeCurrency corrects the currency of an open bill created in the wrong one.
+// Only empty bills can change: existing line items were converted at the rates
+// of their day, and reconverting them would rewrite history.
+func (s *BillingService) ChangeCurrency(ctx context.Context, billID string, to model.Currency) (*model.Bill, error) {
+	if !to.IsSupported() {
+		return nil, billingerrors.UnsupportedCurrency(string(to))
+	}
+
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpChangeCurrency)
+		if err != nil {
+			return err
+		}
+		if len(bill.LineItems) > 0 {
+			return billingerrors.BillHasLineItems(billID)
+		}
+
+		bill.Currency = to
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("change currency: %w", err)
+	}
+
+	s.publish(ctx, events.NewBillEvent(events.EventCurrencyChanged, bill))
+
+	return bill, nil
+}
+
+// GetBill retrieves a bill by ID. Soft-deleted bills are only returned when
+// includeDeleted is set.
+func (s *BillingService) GetBill(ctx context.Context, billID string, includeDeleted bool) (*model.Bill, error) {
+	orgID, err := callerOrg(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if bill == nil {
+	bill, err := s.repo.Get(ctx, orgID, billID)
+	if err != nil {
+		return nil, fmt.Errorf("get bill: %w", err)
+	}
+	if bill == nil || (bill.DeletedAt != nil && !includeDeleted) {
 		return nil, billingerrors.BillNotFound(billID)
 	}
 	return bill, nil
 }
 
-// ListBills lists all bills, optionally filtered by status
-func (s *BillingService) ListBills(status string) ([]model.Bill, error) {
-	return s.repo.List(status)
+// BillExists reports whether the caller's org has a bill with the ID, soft-deleted
+// bills included, without loading it
+func (s *BillingService) BillExists(ctx context.Context, billID string) (bool, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return false, err
+	}
+	exists, err := s.repo.Exists(ctx, orgID, billID)
+	if err != nil {
+		return false, fmt.Errorf("check bill: %w", err)
+	}
+	return exists, nil
+}
+
+// SoftDeleteBill marks a bill as deleted so it is hidden from default reads
+func (s *BillingService) SoftDeleteBill(ctx context.Context, billID string) (*model.Bill, error) {
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBill(ctx, tx, billID)
+		if err != nil {
+			return err
+		}
+
+		now := s.clock.Now()
+		bill.DeletedAt = &now
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("delete bill: %w", err)
+	}
+
+	s.publish(ctx, events.NewBillEvent(events.EventBillDeleted, bill))
+
+	return bill, nil
+}
+
+// RestoreBill reverses a soft delete
+func (s *BillingService) RestoreBill(ctx context.Context, billID string) (*model.Bill, error) {
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		orgID, err := callerOrg(ctx)
+		if err != nil {
+			return err
+		}
+		bill, err = tx.Get(ctx, orgID, billID)
+		if err != nil {
+			return err
+		}
+		if bill == nil {
+			return billingerrors.BillNotFound(billID)
+		}
+
+		if bill.DeletedAt == nil {
+			return billingerrors.BillNotDeleted(billID)
+		}
+
+		bill.DeletedAt = nil
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("restore bill: %w", err)
+	}
+
+	s.publish(ctx, events.NewBillEvent(events.EventBillRestored, bill))
+
+	return bill, nil
+}
+
+// TotalsByCategory sums a bill's line items per category, in the bill's currency (cents)
+func (s *BillingService) TotalsByCategory(ctx context.Context, billID string) (map[model.LineItemCategory]int64, error) {
+	bill, err := s.GetBill(ctx, billID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return CategoryTotals(bill), nil
+}
+
+// CategoryTotals sums a bill's line items per category, in the bill's currency (cents)
+func CategoryTotals(bill *model.Bill) map[model.LineItemCategory]int64 {
+	totals := make(map[model.LineItemCategory]int64)
+	for _, item := range bill.LineItems {
+		totals[item.Category] += item.NetAmount()
+	}
+	return totals
+}
+
+// Bounds on the page size of a paginated bill listing
+const (
+	defaultBillPageSize = 50
+	maxBillPageSize     = 200
+)
+
+// ListBills lists the bills matching the request in (CreatedAt, ID) order. With a
+// limit or a cursor it returns one page, and the cursor for the next page when more
+// bills follow. Pages resume after the last bill's sort key rather than at an
+// offset, so bills created between fetches never shift a page.
+func (s *BillingService) ListBills(ctx context.Context, req *model.ListBillsRequest) ([]model.Bill, string, error) {
+	filter, err := billPageFilter(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	bills, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("list bills: %w", err)
+	}
+	if filter.Limit == 0 || len(bills) < filter.Limit {
+		return bills, "", nil
+	}
+	bills = bills[:filter.Limit-1]
+	return bills, encodeBillCursor(repository.CursorOf(&bills[len(bills)-1])), nil
+}
+
+// ListBillSummaries lists summaries of the bills matching the request, for list
+// views that don't need line items. It pages like ListBills.
+func (s *BillingService) ListBillSummaries(ctx context.Context, req *model.ListBillsRequest) ([]model.BillSummary, string, error) {
+	filter, err := billPageFilter(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	summaries, err := s.repo.ListSummaries(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("list bill summaries: %w", err)
+	}
+	if filter.Limit == 0 || len(summaries) < filter.Limit {
+		return summaries, "", nil
+	}
+	summaries = summaries[:filter.Limit-1]
+	last := summaries[len(summaries)-1]
+	return summaries, encodeBillCursor(repository.BillCursor{CreatedAt: last.CreatedAt, ID: last.ID}), nil
+}
+
+// billPageFilter is billFilter bounded to the requested page. The limit is one more
+// than the page size, so a full result shows another page follows.
+func billPageFilter(ctx context.Context, req *model.ListBillsRequest) (repository.BillFilter, error) {
+	filter, err := billFilter(ctx, req)
+	if err != nil {
+		return filter, err
+	}
+	if req.Cursor != "" {
+		after, err := decodeBillCursor(req.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = &after
+	}
+	if req.Limit > 0 || req.Cursor != "" {
+		size := req.Limit
+		if size <= 0 {
+			size = defaultBillPageSize
+		}
+		if size > maxBillPageSize {
+			size = maxBillPageSize
+		}
+		filter.Limit = size + 1
+	}
+	return filter, nil
+}
+
+// encodeBillCursor renders a sort key as an opaque page cursor
+func encodeBillCursor(cursor repository.BillCursor) string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + ":" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeBillCursor parses a cursor from encodeBillCursor
+func decodeBillCursor(encoded string) (repository.BillCursor, error) {
+	invalid := billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "cursor", Description: "is not a valid cursor"}})
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return repository.BillCursor{}, invalid
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return repository.BillCursor{}, invalid
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return repository.BillCursor{}, invalid
+	}
+	return repository.BillCursor{CreatedAt: time.Unix(0, unixNano).UTC(), ID: id}, nil
+}
+
+// ListBillTotals sums bill totals per currency across every bill matching the request
+func (s *BillingService) ListBillTotals(ctx context.Context, req *model.ListBillsRequest) (map[model.Currency]int64, error) {
+	filter, err := billFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.repo.SumTotals(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("sum bill totals: %w", err)
+	}
+	return totals, nil
+}
+
+// billFilter validates a list request and converts it to a repository filter scoped
+// to the caller's org
+func billFilter(ctx context.Context, req *model.ListBillsRequest) (repository.BillFilter, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return repository.BillFilter{}, err
+	}
+	if req.Currency != "" && !req.Currency.IsSupported() {
+		return repository.BillFilter{}, billingerrors.UnsupportedCurrency(string(req.Currency))
+	}
+	if req.MinTotal < 0 {
+		return repository.BillFilter{}, fmt.Errorf("minTotal must not be negative")
+	}
+	statuses, err := parseStatuses(req.Status)
+	if err != nil {
+		return repository.BillFilter{}, err
+	}
+	return repository.BillFilter{
+		OrgID:          orgID,
+		Statuses:       statuses,
+		Currency:       req.Currency,
+		IncludeDeleted: req.IncludeDeleted,
+		MinTotal:       model.ToCents(req.MinTotal),
+		HasLineItems:   req.HasLineItems,
+	}, nil
+}
+
+// parseStatuses splits a comma-separated status filter and validates each entry
+func parseStatuses(raw string) ([]model.BillStatus, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var statuses []model.BillStatus
+	for _, part := range strings.Split(raw, ",") {
+		status := model.BillStatus(strings.TrimSpace(part))
+		if !status.IsValid() {
+			return nil, billingerrors.UnsupportedStatus(string(status))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// RecalculateTotal re-derives a bill's total from its line items and persists it.
+// It returns the corrected bill along with the previously stored total.
+func (s *BillingService) RecalculateTotal(ctx context.Context, billID string) (*model.Bill, int64, error) {
+	var bill *model.Bill
+	var oldTotal int64
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBill(ctx, tx, billID)
+		if err != nil {
+			return err
+		}
+
+		oldTotal = bill.TotalAmount
+		bill.TotalAmount, err = s.sumLineItems(bill)
+		if err != nil {
+			return err
+		}
+		bill.FXFees = sumFXFees(bill.LineItems)
+
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("recalculate total: %w", err)
+	}
+
+	return bill, oldTotal, nil
+}
+
+// VerifyTotal checks the bill's stored total against the sum of its line items'
+// frozen converted amounts, the same sum RecalculateTotal stores, without changing
+// the bill. A mismatch is logged so corruption shows up on read.
+func (s *BillingService) VerifyTotal(bill *model.Bill) (*model.TotalCheck, error) {
+	computed, err := s.sumLineItems(bill.Clone())
+	if err != nil {
+		return nil, fmt.Errorf("verify total: %w", err)
+	}
+	check := &model.TotalCheck{
+		Consistent:    computed == bill.TotalAmount,
+		StoredTotal:   bill.TotalAmount,
+		ComputedTotal: computed,
+	}
+	if !check.Consistent {
+		s.logger.Error("bill total does not match its line items", "bill_id", bill.ID, "org_id", bill.OrgID,
+			"stored_total", bill.TotalAmount, "computed_total", computed)
+	}
+	return check, nil
+}
+
+// RecalculateOpenBills re-converts, at the current rate, every cross-currency line
+// item priced in or billed in the given currency on bills that aren't closed, and
+// re-derives their totals. It's meant to follow a rate update; closed bills keep
+// their frozen rates. Running it again without another rate change updates
+// nothing. It returns how many bills changed.
+func (s *BillingService) RecalculateOpenBills(ctx context.Context, currency model.Currency) (int, error) {
+	if !currency.IsSupported() {
+		return 0, billingerrors.UnsupportedCurrency(string(currency))
+	}
+
+	bills, err := s.repo.List(ctx, repository.BillFilter{
+		Statuses:     []model.BillStatus{model.BillStatusDraft, model.BillStatusOpen},
+		HasLineItems: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for i := range bills {
+		changed, err := s.reconvertBill(ctx, bills[i].OrgID, bills[i].ID, currency)
+		if err != nil {
+			return updated, fmt.Errorf("recalculate bill %s: %w", bills[i].ID, err)
+		}
+		if changed {
+			updated++
+		}
+	}
+
+	actor, _ := tenant.ActorFromContext(ctx)
+	s.logger.Info("open bills recalculated", "actor", actor, "currency", currency, "updated", updated)
+	return updated, nil
+}
+
+// reconvertBill refreshes the rates of a bill's line items that convert to or from
+// currency and reports whether anything on the bill changed
+func (s *BillingService) reconvertBill(ctx context.Context, orgID, billID string, currency model.Currency) (bool, error) {
+	changed := false
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		bill, err := tx.Get(ctx, orgID, billID)
+		if err != nil {
+			return err
+		}
+		// The bill may have closed since it was listed
+		if bill == nil || bill.DeletedAt != nil || bill.Status == model.BillStatusClosed {
+			return nil
+		}
+
+		var total int64
+		for i := range bill.LineItems {
+			item := &bill.LineItems[i]
+			crossCurrency := item.Currency != bill.Currency && (item.Currency == currency || bill.Currency == currency)
+			if !crossCurrency {
+				total += item.NetAmount()
+				continue
+			}
+			before := *item
+			if total, err = s.convertAndAdd(total, bill.Currency, item); err != nil {
+				return err
+			}
+			if item.AppliedRate != before.AppliedRate || item.ConvertedAmount != before.ConvertedAmount || item.FXFee != before.FXFee {
+				changed = true
+			} else {
+				// Keep the original rate timestamp so a no-op run leaves the bill untouched
+				*item = before
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		bill.TotalAmount = total
+		bill.FXFees = sumFXFees(bill.LineItems)
+		return saveBill(ctx, tx, bill)
+	})
+	return changed, err
 }
 
 // ConvertToUSD converts amount (in cents) from one currency to USD cents
-func (s *BillingService) ConvertToUSD(amountCents int64, currency model.Currency) int64 {
-	if currency == model.CurrencyUSD {
-		return amountCents
+func (s *BillingService) ConvertToUSD(amountCents int64, currency model.Currency) (int64, error) {
+	quote, err := s.quote(currency, model.CurrencyUSD)
+	if err != nil {
+		return 0, err
 	}
-	// Convert GEL cents to USD cents using exchange rate
-	usdFloat := float64(amountCents) * exchangeRatesToUSD[model.CurrencyGEL]
-	return int64(math.Round(usdFloat))
+	return applyRate(amountCents, quote.Rate), nil
 }
 
-// convertAndAdd converts amount (in cents) to bill's currency and adds to total (also in cents)
-func (s *BillingService) convertAndAdd(totalCents int64, billCurrency model.Currency, amountCents int64, amountCurrency model.Currency) int64 {
-	if billCurrency == amountCurrency {
-		return totalCents + amountCents
+// ConvertCurrency previews converting an amount between supported currencies. It
+// uses the same rates and rounding AddLineItem applies, so the result matches what
+// a line item with this amount would add to a bill in the target currency.
+func (s *BillingService) ConvertCurrency(req *model.ConvertCurrencyRequest) (*model.Conversion, error) {
+	if !req.From.IsSupported() {
+		return nil, billingerrors.UnsupportedCurrency(string(req.From))
 	}
-	// Convert amount to USD cents first, then to bill's currency
-	usdCents := s.ConvertToUSD(amountCents, amountCurrency)
-	
-	// Convert from USD to bill's currency
-	if billCurrency == model.CurrencyUSD {
-		return totalCents + usdCents
+	if !req.To.IsSupported() {
+		return nil, billingerrors.UnsupportedCurrency(string(req.To))
+	}
+	quote, err := s.quote(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := model.ToCents(req.Amount)
+	return &model.Conversion{
+		From:            req.From,
+		To:              req.To,
+		Rate:            quote.Rate,
+		RateAsOf:        quote.AsOf,
+		Amount:          amount,
+		ConvertedAmount: applyRate(amount, quote.Rate),
+	}, nil
+}
+
+// DisplayAmounts converts a bill's total and each line item's net amount into the
+// display currency at the current rate, leaving the bill untouched. Each amount is
+// rounded to the cent on its own, so the line items may not sum exactly to the total.
+func (s *BillingService) DisplayAmounts(bill *model.Bill, to model.Currency) (*model.DisplayAmounts, error) {
+	display, err := s.displayTotal(bill.Currency, bill.TotalAmount, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(bill.LineItems) > 0 {
+		display.LineItems = make([]model.DisplayLineItem, len(bill.LineItems))
+		for i, item := range bill.LineItems {
+			display.LineItems[i] = model.DisplayLineItem{ID: item.ID, NetAmount: applyRate(item.NetAmount(), display.Rate)}
+		}
+	}
+	return display, nil
+}
+
+// DisplaySummaryAmounts is DisplayAmounts for a bill summary, which has a total only
+func (s *BillingService) DisplaySummaryAmounts(summary *model.BillSummary, to model.Currency) (*model.DisplayAmounts, error) {
+	return s.displayTotal(summary.Currency, summary.TotalAmount, to)
+}
+
+func (s *BillingService) displayTotal(from model.Currency, total int64, to model.Currency) (*model.DisplayAmounts, error) {
+	if !to.IsSupported() {
+		return nil, billingerrors.UnsupportedCurrency(string(to))
+	}
+	quote, err := s.quote(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &model.DisplayAmounts{
+		Currency:    to,
+		Rate:        quote.Rate,
+		RateAsOf:    quote.AsOf,
+		TotalAmount: applyRate(total, quote.Rate),
+	}, nil
+}
+
+// ListCurrencies describes every supported currency with its current USD rate
+// from the service's rate provider, in SupportedCurrencies order
+func (s *BillingService) ListCurrencies() ([]model.CurrencyInfo, error) {
+	currencies := make([]model.CurrencyInfo, 0, len(model.SupportedCurrencies))
+	for _, currency := range model.SupportedCurrencies {
+		quote, err := s.quote(currency, model.CurrencyUSD)
+		if err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, model.CurrencyInfo{
+			Code:          currency,
+			DecimalPlaces: currency.DecimalPlaces(),
+			RateToUSD:     quote.Rate,
+			RateAsOf:      quote.AsOf,
+		})
 	}
-	
-	// USD to GEL: divide by rate (1 GEL = 0.37 USD, so 1 USD = 1/0.37 GEL)
-	gelFloat := float64(usdCents) / exchangeRatesToUSD[model.CurrencyGEL]
-	return totalCents + int64(math.Round(gelFloat))
+	return currencies, nil
 }
 
-// floatToCents converts a float64 dollar amount to int64 cents
-func floatToCents(amount float64) int64 {
-	return int64(math.Round(amount * 100))
+// SetExchangeRate overrides the USD value of one unit of a supported currency in the
+// rate provider, for ops correcting a bad rate. Only later conversions use it; rates
+// frozen on existing line items don't change. The change is audit logged with the
+// operator on ctx.
+func (s *BillingService) SetExchangeRate(ctx context.Context, currency model.Currency, rateToUSD float64) (model.CurrencyInfo, error) {
+	if !currency.IsSupported() {
+		return model.CurrencyInfo{}, billingerrors.UnsupportedCurrency(string(currency))
+	}
+	if rateToUSD <= 0 || math.IsInf(rateToUSD, 0) || math.IsNaN(rateToUSD) {
+		return model.CurrencyInfo{}, fmt.Errorf("exchange rate must be a positive number, got %v", rateToUSD)
+	}
+	if currency == model.CurrencyUSD && rateToUSD != 1.0 {
+		return model.CurrencyInfo{}, fmt.Errorf("exchange rate for USD must be 1.0, got %v", rateToUSD)
+	}
+	setter, ok := s.rates.(RateSetter)
+	if !ok {
+		return model.CurrencyInfo{}, fmt.Errorf("the configured rate provider does not support overrides")
+	}
+
+	old, _ := s.rates.Quote(currency, model.CurrencyUSD)
+	setter.SetRate(currency, rateToUSD)
+	actor, _ := tenant.ActorFromContext(ctx)
+	s.logger.Info("exchange rate overridden", "actor", actor, "currency", currency,
+		"old_rate", old.Rate, "new_rate", rateToUSD)
+
+	quote, err := s.rates.Quote(currency, model.CurrencyUSD)
+	if err != nil {
+		return model.CurrencyInfo{}, err
+	}
+	return model.CurrencyInfo{
+		Code:          currency,
+		DecimalPlaces: currency.DecimalPlaces(),
+		RateToUSD:     quote.Rate,
+		RateAsOf:      quote.AsOf,
+	}, nil
+}
+
+// convertAndAdd converts the line item's amount (in cents) to the bill's currency and
+// adds it to total (also in cents). The applied rate, converted amount and FX fee are
+// frozen on the line item so later rate or markup changes don't alter the bill. An
+// item added without a currency is in the bill's. Items in the bill's currency
+// aren't quoted at all: they record a rate of exactly 1.0 and their own amount as
+// converted, whatever the rate provider would say, so reports can treat every item
+// alike.
+func (s *BillingService) convertAndAdd(totalCents int64, billCurrency model.Currency, item *model.LineItem) (int64, error) {
+	if item.Currency == "" {
+		item.Currency = billCurrency
+	}
+	quote := RateQuote{Rate: 1.0, AsOf: s.clock.Now()}
+	if item.Currency != billCurrency {
+		var err error
+		if quote, err = s.quote(item.Currency, billCurrency); err != nil {
+			return 0, err
+		}
+	}
+	asOf := quote.AsOf
+	item.AppliedRate = quote.Rate
+	item.RateAsOf = &asOf
+	item.ConvertedAmount = applyRate(item.Amount, quote.Rate)
+	item.FXFee = 0
+	if item.Currency != billCurrency && item.Type != model.LineItemTypeCredit {
+		item.FXFee = applyRate(item.ConvertedAmount, s.fxMarkup)
+	}
+	return totalCents + item.NetAmount(), nil
+}
+
+// sumFXFees sums the FX fees frozen on the line items
+func sumFXFees(items []model.LineItem) int64 {
+	var fees int64
+	for _, item := range items {
+		fees += item.FXFee
+	}
+	return fees
+}
+
+// quote fetches a conversion rate from the provider and applies the staleness policy.
+// Every conversion goes through it, so a rate that isn't positive and finite is
+// refused here whatever the provider, rather than producing a zero or infinite amount.
+func (s *BillingService) quote(from, to model.Currency) (RateQuote, error) {
+	quote, err := s.rates.Quote(from, to)
+	if err != nil {
+		return RateQuote{}, err
+	}
+	if !validRate(quote.Rate) {
+		return RateQuote{}, billingerrors.InvalidRate(string(from), string(to), quote.Rate)
+	}
+	if s.maxRateAge > 0 && s.clock.Now().Sub(quote.AsOf) > s.maxRateAge && s.staleRatePolicy == StaleRateReject {
+		return RateQuote{}, billingerrors.StaleRate(string(from), string(to), quote.AsOf)
+	}
+	return quote, nil
+}
+
+// applyRate converts an amount in cents at the given rate, rounding to the nearest cent
+func applyRate(amountCents int64, rate float64) int64 {
+	return int64(math.Round(float64(amountCents) * rate))
+}
+
+// sumLineItems computes a bill's total (in cents) from its line items' frozen
+// converted amounts. Items without a frozen rate are converted at the current rate.
+func (s *BillingService) sumLineItems(bill *model.Bill) (int64, error) {
+	var total int64
+	for i := range bill.LineItems {
+		item := &bill.LineItems[i]
+		if item.AppliedRate == 0 {
+			var err error
+			if total, err = s.convertAndAdd(total, bill.Currency, item); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		total += item.NetAmount()
+	}
+	return total, nil
+}
+
+// newLineItem validates the request and builds the line item it describes. The
+// caller assigns the item's ID once it's kept.
+func newLineItem(req *model.AddLineItemRequest, now time.Time) (model.LineItem, error) {
+	if req.Description == "" {
+		return model.LineItem{}, fmt.Errorf("description is required")
+	}
+	if err := validateDescription(req.Description); err != nil {
+		return model.LineItem{}, err
+	}
+	if req.Amount <= 0 {
+		return model.LineItem{}, fmt.Errorf("amount must be positive")
+	}
+
+	// An empty currency is left for convertAndAdd to default to the bill's
+	req.Currency = req.Currency.Normalize()
+	if req.Currency != "" && !req.Currency.IsSupported() {
+		return model.LineItem{}, billingerrors.UnsupportedCurrency(string(req.Currency))
+	}
+
+	category := req.Category
+	if category == "" {
+		category = model.CategoryOther
+	}
+	if !category.IsValid() {
+		return model.LineItem{}, billingerrors.UnsupportedCategory(string(category))
+	}
+
+	itemType := req.Type
+	if itemType == "" {
+		itemType = model.LineItemTypeCharge
+	}
+	if !itemType.IsValid() {
+		return model.LineItem{}, billingerrors.UnsupportedLineItemType(string(itemType))
+	}
+
+	if err := validateMetadata(req.Metadata); err != nil {
+		return model.LineItem{}, err
+	}
+	if req.Approved && req.ApproverID == "" {
+		return model.LineItem{}, fmt.Errorf("approverId is required when approved")
+	}
+
+	var metadata map[string]string
+	if len(req.Metadata) > 0 {
+		metadata = make(map[string]string, len(req.Metadata))
+		for key, value := range req.Metadata {
+			metadata[key] = value
+		}
+	}
+
+	return model.LineItem{
+		Description: req.Description,
+		// Convert float64 to int64 cents to avoid floating point errors
+		Amount:        model.ToCents(req.Amount),
+		Currency:      req.Currency,
+		Category:      category,
+		Type:          itemType,
+		Metadata:      metadata,
+		Ref:           req.Ref,
+		CreatedAt:     now,
+		EffectiveDate: req.EffectiveDate,
+	}, nil
+}
+
+// startPeriod opens the bill's billing period at now, lasting the given number of
+// days or the default if that isn't positive
+func startPeriod(bill *model.Bill, now time.Time, days int) {
+	if days <= 0 {
+		days = defaultBillingPeriodDays
+	}
+	end := now.AddDate(0, 0, days)
+	bill.PeriodStart = &now
+	bill.PeriodEnd = &end
+}
+
+// checkPeriodDays checks a requested billing period length: zero means the
+// default, and it may not be negative or longer than the configured maximum
+func (s *BillingService) checkPeriodDays(days int) error {
+	if days < 0 {
+		return billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "billingPeriodDays", Description: "must not be negative"}})
+	}
+	if days > s.maxPeriodDays {
+		return billingerrors.InvalidArgument([]billingerrors.FieldViolation{{
+			Field:       "billingPeriodDays",
+			Description: fmt.Sprintf("must be at most %d", s.maxPeriodDays),
+		}})
+	}
+	return nil
+}
+
+// checkPeriodLength checks a new bill's billing period, explicit or in days,
+// against the configured maximum
+func (s *BillingService) checkPeriodLength(req *model.CreateBillRequest) error {
+	if req.PeriodStart == nil || req.PeriodEnd == nil {
+		return s.checkPeriodDays(req.BillingPeriodDays)
+	}
+	if req.PeriodEnd.After(req.PeriodStart.AddDate(0, 0, s.maxPeriodDays)) {
+		return billingerrors.InvalidArgument([]billingerrors.FieldViolation{{
+			Field:       "periodEnd",
+			Description: fmt.Sprintf("must be at most %d days after periodStart", s.maxPeriodDays),
+		}})
+	}
+	return nil
+}
+
+// validatePeriod checks an explicit billing period requested for a new bill
+func validatePeriod(req *model.CreateBillRequest) error {
+	if req.PeriodStart == nil && req.PeriodEnd == nil {
+		return nil
+	}
+	if req.PeriodStart == nil || req.PeriodEnd == nil {
+		return fmt.Errorf("periodStart and periodEnd must be given together")
+	}
+	if !req.PeriodEnd.After(*req.PeriodStart) {
+		return fmt.Errorf("periodEnd must be after periodStart")
+	}
+	if req.Draft {
+		return fmt.Errorf("drafts get their billing period on activation")
+	}
+	return nil
+}
+
+// prorate reduces the line item to the share of its amount covering the days left
+// in the bill's billing period from its effective date (now if unset), keeping the
+// full amount alongside. Partial days count as whole days; an effective date before
+// the period charges the full amount.
+func prorate(item *model.LineItem, bill *model.Bill, now time.Time) error {
+	if bill.PeriodStart == nil || bill.PeriodEnd == nil {
+		return billingerrors.NoBillingPeriod(bill.ID)
+	}
+	effective := now
+	if item.EffectiveDate != nil {
+		effective = *item.EffectiveDate
+	}
+	if effective.After(*bill.PeriodEnd) {
+		return fmt.Errorf("effective date %s is after the billing period ends", effective.Format(time.RFC3339))
+	}
+	if effective.Before(*bill.PeriodStart) {
+		effective = *bill.PeriodStart
+	}
+
+	periodDays := daysBetween(*bill.PeriodStart, *bill.PeriodEnd)
+	remainingDays := daysBetween(effective, *bill.PeriodEnd)
+	full := item.Amount
+	item.FullAmount = &full
+	item.Amount = int64(math.Round(float64(full) * float64(remainingDays) / float64(periodDays)))
+	return nil
+}
+
+// recentDuplicate returns a line item on the bill with the same description, amount
+// and currency as item, added within the dedup window, or nil. Prorated items are
+// compared by their full amount.
+func (s *BillingService) recentDuplicate(bill *model.Bill, item model.LineItem) *model.LineItem {
+	if s.dedupWindow <= 0 {
+		return nil
+	}
+	for i := len(bill.LineItems) - 1; i >= 0; i-- {
+		existing := &bill.LineItems[i]
+		if item.CreatedAt.Sub(existing.CreatedAt) >= s.dedupWindow {
+			continue
+		}
+		amount := existing.Amount
+		if existing.FullAmount != nil {
+			amount = *existing.FullAmount
+		}
+		if existing.Description == item.Description && amount == item.Amount && existing.Currency == item.Currency {
+			return existing
+		}
+	}
+	return nil
+}
+
+// checkAmountCap rejects a converted line item over the per-item amount cap unless
+// the request carries a manager's approval, which is recorded on the item
+func (s *BillingService) checkAmountCap(item *model.LineItem, req *model.AddLineItemRequest, billCurrency model.Currency) error {
+	if s.maxItemAmount <= 0 || item.ConvertedAmount <= s.maxItemAmount {
+		return nil
+	}
+	if !req.Approved {
+		return billingerrors.AmountOverCap(money.Format(s.maxItemAmount, billCurrency))
+	}
+	item.ApprovedBy = req.ApproverID
+	s.logger.Info("line item over amount cap approved", "line_item_id", item.ID, "approver", req.ApproverID,
+		"converted_amount", item.ConvertedAmount, "cap", s.maxItemAmount)
+	return nil
+}
+
+// findRef returns the line item among items with the given external reference, or
+// nil. Empty refs never match.
+func findRef(items []model.LineItem, ref string) *model.LineItem {
+	if ref == "" {
+		return nil
+	}
+	for i := range items {
+		if items[i].Ref == ref {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+// daysBetween counts the days from one time to another, rounding partial days up
+func daysBetween(from, to time.Time) int {
+	return int(math.Ceil(to.Sub(from).Hours() / 24))
+}
+
+// validateDescription enforces the length limit on a line item description
+func validateDescription(description string) error {
+	if utf8.RuneCountInString(description) > model.MaxDescriptionLength {
+		return tooLong("description", fmt.Sprintf("must be at most %d characters", model.MaxDescriptionLength))
+	}
+	return nil
+}
+
+// validateNote enforces the length limit on a bill's note
+func validateNote(note string) error {
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return tooLong("note", fmt.Sprintf("must be at most %d characters", maxNoteLength))
+	}
+	return nil
+}
+
+// validateMetadata enforces size limits on line item metadata
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataKeys {
+		return tooLong("metadata", fmt.Sprintf("must have at most %d keys", maxMetadataKeys))
+	}
+	for key, value := range metadata {
+		if key == "" {
+			return fmt.Errorf("metadata keys must not be empty")
+		}
+		if utf8.RuneCountInString(key) > maxMetadataKeyLength {
+			return tooLong("metadata."+key, fmt.Sprintf("key must be at most %d characters", maxMetadataKeyLength))
+		}
+		if utf8.RuneCountInString(value) > maxMetadataValueLength {
+			return tooLong("metadata."+key, fmt.Sprintf("must be at most %d characters", maxMetadataValueLength))
+		}
+	}
+	return nil
+}
+
+// tooLong reports a single field over its size limit
+func tooLong(field, description string) error {
+	return billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: field, Description: description}})
+}
+
+// loadBill fetches a bill for modification, treating soft-deleted bills as missing
+func loadBill(ctx context.Context, repo repository.BillRepository, billID string) (*model.Bill, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bill, err := repo.Get(ctx, orgID, billID)
+	if err != nil {
+		return nil, err
+	}
+	if bill == nil || bill.DeletedAt != nil {
+		return nil, billingerrors.BillNotFound(billID)
+	}
+	return bill, nil
+}
+
+// callerOrg returns the organization the request acts for. Every bill operation
+// is scoped to it, so bills of other orgs look like they don't exist.
+func callerOrg(ctx context.Context) (string, error) {
+	orgID, ok := tenant.OrgIDFromContext(ctx)
+	if !ok {
+		return "", billingerrors.Unauthenticated("organization is required")
+	}
+	return orgID, nil
+}
+
+// publish publishes a bill event; delivery failures never fail the operation
+func (s *BillingService) publish(ctx context.Context, event events.BillEvent) {
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		s.logger.Error("publish bill event failed", "event_type", event.Type, "bill_id", event.BillID, "error", err)
+	}
 }
 
-// generateID generates a unique ID
-func generateID() string {
-	return fmt.Sprintf("bill_%d", time.Now().UnixNano())
+// saveBill writes a changed bill within a transaction, moving it to its next Version
+func saveBill(ctx context.Context, tx repository.BillRepository, bill *model.Bill) error {
+	bill.Version++
+	return tx.Update(ctx, bill)
 }
@@ -1,11 +1,42 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"fees-api/internal/events"
 	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
 )
 
+// allowEmptyClose lets tests close bills they created without line items
+var allowEmptyClose = &model.CloseBillRequest{AllowEmpty: true}
+
+// newTestBillingService creates a billing service, failing the test on error
+func newTestBillingService(t *testing.T, repo repository.BillRepository, opts ...Option) *BillingService {
+	t.Helper()
+	svc, err := NewBillingService(repo, opts...)
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	return svc
+}
+
+// testOrgID is the org every test request acts for
+const testOrgID = "org_test"
+
+// testContext returns a context scoped to testOrgID
+func testContext() context.Context {
+	return tenant.WithOrgID(context.Background(), testOrgID)
+}
+
 // mockBillRepository is a mock implementation of BillRepository for testing
 type mockBillRepository struct {
 	bills map[string]model.Bill
@@ -17,33 +48,70 @@ func newMockBillRepository() *mockBillRepository {
 	}
 }
 
-func (m *mockBillRepository) Create(bill *model.Bill) error {
+func (m *mockBillRepository) Create(ctx context.Context, bill *model.Bill) error {
 	m.bills[bill.ID] = *bill
 	return nil
 }
 
-func (m *mockBillRepository) Get(id string) (*model.Bill, error) {
+func (m *mockBillRepository) Get(ctx context.Context, orgID, id string) (*model.Bill, error) {
 	bill, ok := m.bills[id]
-	if !ok {
+	if !ok || bill.OrgID != orgID {
 		return nil, nil
 	}
 	return &bill, nil
 }
 
-func (m *mockBillRepository) Update(bill *model.Bill) error {
+func (m *mockBillRepository) Exists(ctx context.Context, orgID, id string) (bool, error) {
+	bill, ok := m.bills[id]
+	return ok && bill.OrgID == orgID, nil
+}
+
+func (m *mockBillRepository) Update(ctx context.Context, bill *model.Bill) error {
 	m.bills[bill.ID] = *bill
 	return nil
 }
 
-func (m *mockBillRepository) List(status string) ([]model.Bill, error) {
+func (m *mockBillRepository) QueryBills(ctx context.Context, criteria repository.BillQuery) ([]model.Bill, error) {
 	var result []model.Bill
 	for _, bill := range m.bills {
-		if status != "" && string(bill.Status) != status {
+		if !criteria.Matches(&bill) {
 			continue
 		}
 		result = append(result, bill)
 	}
-	return result, nil
+	return criteria.Paginate(result), nil
+}
+
+func (m *mockBillRepository) List(ctx context.Context, filter repository.BillFilter) ([]model.Bill, error) {
+	return m.QueryBills(ctx, filter.Query())
+}
+
+func (m *mockBillRepository) ListSummaries(ctx context.Context, filter repository.BillFilter) ([]model.BillSummary, error) {
+	var result []model.BillSummary
+	for _, bill := range m.bills {
+		if filter.Matches(&bill) {
+			result = append(result, bill.Summary())
+		}
+	}
+	return filter.PaginateSummaries(result), nil
+}
+
+func (m *mockBillRepository) SumTotals(ctx context.Context, filter repository.BillFilter) (map[model.Currency]int64, error) {
+	totals := make(map[model.Currency]int64)
+	for _, bill := range m.bills {
+		if filter.Matches(&bill) {
+			totals[bill.Currency] += bill.TotalAmount
+		}
+	}
+	return totals, nil
+}
+
+func (m *mockBillRepository) WithTransaction(ctx context.Context, fn func(tx repository.BillRepository) error) error {
+	return fn(m)
+}
+
+func (m *mockBillRepository) Ping(ctx context.Context) error {
+	return nil
 }
 
 // ============ Table-Driven Tests ============
@@ -102,10 +170,10 @@ func TestCreateBill(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := newMockBillRepository()
-			svc := NewBillingService(repo)
+			svc := newTestBillingService(t, repo)
 
 			req := &model.CreateBillRequest{Currency: tt.currency}
-			bill, err := svc.CreateBill(req)
+			bill, err := svc.CreateBill(testContext(), req)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateBill() error = %v, wantErr %v", err, tt.wantErr)
@@ -130,7 +198,7 @@ func TestAddLineItem(t *testing.T) {
 		{
 			name: "adds line item successfully",
 			setupBill: func(svc *BillingService) string {
-				bill, _ := svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
 				return bill.ID
 			},
 			req: &model.AddLineItemRequest{
@@ -152,7 +220,7 @@ func TestAddLineItem(t *testing.T) {
 		{
 			name: "converts GEL to USD",
 			setupBill: func(svc *BillingService) string {
-				bill, _ := svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
 				return bill.ID
 			},
 			req: &model.AddLineItemRequest{
@@ -168,11 +236,51 @@ func TestAddLineItem(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "converts USD to GEL",
+			setupBill: func(svc *BillingService) string {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyGEL})
+				return bill.ID
+			},
+			req: &model.AddLineItemRequest{
+				Description: "Service fee",
+				Amount:      37.00,
+				Currency:    model.CurrencyUSD,
+			},
+			wantErr: false,
+			checkBill: func(t *testing.T, bill *model.Bill) {
+				// 37 USD / 0.37 = 100 GEL = 10000 cents
+				if bill.TotalAmount != 10000 {
+					t.Errorf("expected 10000, got %d", bill.TotalAmount)
+				}
+				if item := bill.LineItems[0]; item.ConvertedAmount != 10000 || item.Amount != 3700 {
+					t.Errorf("expected 3700 USD cents converted to 10000 GEL cents, got %d to %d", item.Amount, item.ConvertedAmount)
+				}
+			},
+		},
+		{
+			name: "keeps same-currency amounts as is",
+			setupBill: func(svc *BillingService) string {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyGEL})
+				return bill.ID
+			},
+			req: &model.AddLineItemRequest{
+				Description: "Service fee",
+				Amount:      12.34,
+				Currency:    model.CurrencyGEL,
+			},
+			wantErr: false,
+			checkBill: func(t *testing.T, bill *model.Bill) {
+				if bill.TotalAmount != 1234 || bill.LineItems[0].AppliedRate != 1.0 {
+					t.Errorf("expected 1234 at rate 1.0, got %d at %v", bill.TotalAmount, bill.LineItems[0].AppliedRate)
+				}
+			},
+		},
 		{
 			name: "fails for closed bill",
 			setupBill: func(svc *BillingService) string {
-				bill, _ := svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
-				svc.CloseBill(bill.ID)
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CloseBill(testContext(), bill.ID, allowEmptyClose)
 				return bill.ID
 			},
 			req: &model.AddLineItemRequest{
@@ -201,10 +309,10 @@ func TestAddLineItem(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := newMockBillRepository()
-			svc := NewBillingService(repo)
+			svc := newTestBillingService(t, repo)
 
 			billID := tt.setupBill(svc)
-			bill, err := svc.AddLineItem(billID, tt.req)
+			bill, err := svc.AddLineItem(testContext(), billID, tt.req)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AddLineItem() error = %v, wantErr %v", err, tt.wantErr)
@@ -221,14 +329,17 @@ func TestAddLineItem(t *testing.T) {
 func TestCloseBill(t *testing.T) {
 	tests := []struct {
 		name      string
+		opts      []Option
 		setupBill func(*BillingService) string
+		req       *model.CloseBillRequest
 		wantErr   bool
 		checkBill func(*testing.T, *model.Bill)
 	}{
 		{
 			name: "closes open bill",
 			setupBill: func(svc *BillingService) string {
-				bill, _ := svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
 				return bill.ID
 			},
 			wantErr: false,
@@ -241,13 +352,45 @@ func TestCloseBill(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "rejects empty bill by default",
+			setupBill: func(svc *BillingService) string {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				return bill.ID
+			},
+			wantErr: true,
+		},
+		{
+			name: "closes empty bill when request allows it",
+			setupBill: func(svc *BillingService) string {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				return bill.ID
+			},
+			req:     &model.CloseBillRequest{AllowEmpty: true},
+			wantErr: false,
+			checkBill: func(t *testing.T, bill *model.Bill) {
+				if bill.Status != model.BillStatusClosed || *bill.FinalTotal != 0 {
+					t.Errorf("expected closed bill with zero total, got %v %d", bill.Status, *bill.FinalTotal)
+				}
+			},
+		},
+		{
+			name: "closes empty bill when service allows it",
+			opts: []Option{WithAllowEmptyClose(true)},
+			setupBill: func(svc *BillingService) string {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				return bill.ID
+			},
+			wantErr: false,
+		},
 		{
 			name: "fails for already closed bill",
 			setupBill: func(svc *BillingService) string {
-				bill, _ := svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
-				svc.CloseBill(bill.ID)
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CloseBill(testContext(), bill.ID, allowEmptyClose)
 				return bill.ID
 			},
+			req:       allowEmptyClose,
 			wantErr:   true,
 			checkBill: nil,
 		},
@@ -264,10 +407,10 @@ func TestCloseBill(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := newMockBillRepository()
-			svc := NewBillingService(repo)
+			svc := newTestBillingService(t, repo, tt.opts...)
 
 			billID := tt.setupBill(svc)
-			bill, err := svc.CloseBill(billID)
+			bill, err := svc.CloseBill(testContext(), billID, tt.req)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CloseBill() error = %v, wantErr %v", err, tt.wantErr)
@@ -281,6 +424,166 @@ func TestCloseBill(t *testing.T) {
 	}
 }
 
+func TestPreviewCloseMatchesClose(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []model.AddLineItemRequest
+		draft   bool
+		req     *model.CloseBillRequest
+		wantErr bool
+	}{
+		{
+			name: "bill with converted line items",
+			items: []model.AddLineItemRequest{
+				{Description: "Setup", Amount: 20.00, Currency: model.CurrencyUSD},
+				{Description: "Usage", Amount: 100.00, Currency: model.CurrencyGEL},
+			},
+		},
+		{name: "empty bill allowed by the request", req: allowEmptyClose},
+		{name: "empty bill", wantErr: true},
+		{name: "draft bill", draft: true, req: allowEmptyClose, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext()
+			var published []events.BillEvent
+			topic := events.NewTopic()
+			topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+				published = append(published, event)
+				return nil
+			})
+			clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock), WithPublisher(topic))
+
+			bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: tt.draft})
+			for i := range tt.items {
+				svc.AddLineItem(ctx, bill.ID, &tt.items[i])
+			}
+			clock.Advance(time.Hour)
+			published = nil
+
+			preview, previewErr := svc.PreviewClose(ctx, bill.ID, tt.req)
+			stored, _ := svc.GetBill(ctx, bill.ID, false)
+			if stored.Status == model.BillStatusClosed || stored.FinalTotal != nil {
+				t.Fatalf("expected the preview to leave the bill as it was, got %s", stored.Status)
+			}
+			if len(published) != 0 {
+				t.Fatalf("expected no events from a preview, got %+v", published)
+			}
+
+			closed, closeErr := svc.CloseBill(ctx, bill.ID, tt.req)
+			if (previewErr != nil) != tt.wantErr || (closeErr != nil) != tt.wantErr {
+				t.Fatalf("expected preview and close errors %v, got %v and %v", tt.wantErr, previewErr, closeErr)
+			}
+			if tt.wantErr {
+				if strings.TrimPrefix(previewErr.Error(), "preview close: ") != strings.TrimPrefix(closeErr.Error(), "close bill: ") {
+					t.Errorf("expected the same error from preview and close, got %v and %v", previewErr, closeErr)
+				}
+				return
+			}
+			if closed.InvoiceNumber == "" || preview.InvoiceNumber != "" {
+				t.Errorf("expected only the close to assign an invoice number, got %q and %q", preview.InvoiceNumber, closed.InvoiceNumber)
+			}
+			closed.InvoiceNumber = ""
+			if !reflect.DeepEqual(preview, closed) {
+				t.Errorf("expected the preview to match the close\npreview: %+v\nclosed:  %+v", preview, closed)
+			}
+		})
+	}
+}
+
+func TestLineItemOrderSurvivesReload(t *testing.T) {
+	repo := repository.NewInMemoryBillRepository()
+	svc := newTestBillingService(t, repo)
+	ctx := testContext()
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	descriptions := []string{"Setup", "Usage", "Support", "Storage", "Egress"}
+	for _, description := range descriptions {
+		if _, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: description, Amount: 1.00, Currency: model.CurrencyUSD}); err != nil {
+			t.Fatalf("AddLineItem() error = %v", err)
+		}
+	}
+
+	reloaded, err := svc.GetBill(ctx, bill.ID, false)
+	if err != nil {
+		t.Fatalf("GetBill() error = %v", err)
+	}
+	for i, item := range reloaded.LineItems {
+		if item.Sequence != i+1 || item.Description != descriptions[i] {
+			t.Errorf("line item %d: expected %s at sequence %d, got %s at %d", i, descriptions[i], i+1, item.Description, item.Sequence)
+		}
+	}
+
+	listed, _, _ := svc.ListBills(ctx, &model.ListBillsRequest{})
+	if len(listed) != 1 || listed[0].LineItems[4].Description != "Egress" {
+		t.Errorf("expected listed bills to keep the insertion order, got %+v", listed)
+	}
+}
+
+func TestCurrencyInputIsNormalized(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+	ctx := testContext()
+
+	bill, err := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: "usd"})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if bill.Currency != model.CurrencyUSD {
+		t.Errorf("expected currency USD, got %q", bill.Currency)
+	}
+
+	for _, currency := range []model.Currency{"Usd", " GEL ", "gel"} {
+		bill, err = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee " + string(currency), Amount: 1.00, Currency: currency})
+		if err != nil {
+			t.Fatalf("AddLineItem(%q) error = %v", currency, err)
+		}
+		if got := bill.LineItems[len(bill.LineItems)-1].Currency; got != currency.Normalize() || !got.IsSupported() {
+			t.Errorf("expected %q stored as %q, got %q", currency, currency.Normalize(), got)
+		}
+	}
+
+	if _, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: " eur "}); err == nil {
+		t.Error("expected an unknown currency to still be rejected")
+	}
+}
+
+func TestLineItemCurrencyDefaultsToBill(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+	ctx := testContext()
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyGEL})
+	bill, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00})
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+	item := bill.LineItems[0]
+	if item.Currency != model.CurrencyGEL || item.ConvertedAmount != 1000 || item.FXFee != 0 || bill.TotalAmount != 1000 {
+		t.Errorf("expected a GEL item adding 1000 without conversion, got %s converted to %d with fee %d, total %d",
+			item.Currency, item.ConvertedAmount, item.FXFee, bill.TotalAmount)
+	}
+
+	replaced, err := svc.ReplaceLineItems(ctx, bill.ID, []model.AddLineItemRequest{
+		{Description: "Setup", Amount: 5.00},
+		{Description: "Usage", Amount: 1.00, Currency: model.CurrencyUSD},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceLineItems() error = %v", err)
+	}
+	if got := replaced.LineItems[0].Currency; got != model.CurrencyGEL {
+		t.Errorf("expected a replaced item without a currency in GEL, got %q", got)
+	}
+	if got := replaced.LineItems[1].Currency; got != model.CurrencyUSD {
+		t.Errorf("expected an explicit currency to be kept, got %q", got)
+	}
+
+	_, err = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: "EUR"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported currency: EUR") {
+		t.Errorf("expected an explicit unsupported currency to be rejected, got %v", err)
+	}
+}
+
 func TestGetBill(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -292,7 +595,7 @@ func TestGetBill(t *testing.T) {
 		{
 			name: "retrieves existing bill",
 			setupBill: func(svc *BillingService) string {
-				bill, _ := svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
 				return bill.ID
 			},
 			billID:  "",
@@ -315,14 +618,14 @@ func TestGetBill(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := newMockBillRepository()
-			svc := NewBillingService(repo)
+			svc := newTestBillingService(t, repo)
 
 			expectedID := tt.setupBill(svc)
 			if tt.billID != "" {
 				expectedID = tt.billID
 			}
 
-			bill, err := svc.GetBill(expectedID)
+			bill, err := svc.GetBill(testContext(), expectedID, false)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetBill() error = %v, wantErr %v", err, tt.wantErr)
@@ -336,18 +639,51 @@ func TestGetBill(t *testing.T) {
 	}
 }
 
+func TestBillExists(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	deleted, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.SoftDeleteBill(ctx, deleted.ID)
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		billID string
+		want   bool
+	}{
+		{name: "existing bill", ctx: ctx, billID: bill.ID, want: true},
+		{name: "soft-deleted bill", ctx: ctx, billID: deleted.ID, want: true},
+		{name: "missing bill", ctx: ctx, billID: "bill_missing", want: false},
+		{name: "another org's bill", ctx: tenant.WithOrgID(context.Background(), "org_other"), billID: bill.ID, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.BillExists(tt.ctx, tt.billID)
+			if err != nil || got != tt.want {
+				t.Errorf("BillExists() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
 func TestListBills(t *testing.T) {
 	tests := []struct {
 		name      string
 		setupBills func(*BillingService)
 		status    string
+		currency  model.Currency
+		minTotal  float64
+		hasItems  bool
 		wantCount int
+		wantErr   bool
 	}{
 		{
 			name: "lists all bills",
 			setupBills: func(svc *BillingService) {
-				svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
-				svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyGEL})
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyGEL})
 			},
 			status:    "",
 			wantCount: 2,
@@ -355,9 +691,9 @@ func TestListBills(t *testing.T) {
 		{
 			name: "filters by open status",
 			setupBills: func(svc *BillingService) {
-				bill, _ := svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
-				svc.CloseBill(bill.ID)
-				svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CloseBill(testContext(), bill.ID, allowEmptyClose)
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
 			},
 			status:    "open",
 			wantCount: 1,
@@ -365,25 +701,150 @@ func TestListBills(t *testing.T) {
 		{
 			name: "filters by closed status",
 			setupBills: func(svc *BillingService) {
-				bill, _ := svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
-				svc.CloseBill(bill.ID)
-				svc.CreateBill(&model.CreateBillRequest{Currency: model.CurrencyUSD})
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CloseBill(testContext(), bill.ID, allowEmptyClose)
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			},
+			status:    "closed",
+			wantCount: 1,
+		},
+		{
+			name: "filters by currency",
+			setupBills: func(svc *BillingService) {
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyGEL})
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyGEL})
+			},
+			currency:  model.CurrencyGEL,
+			wantCount: 2,
+		},
+		{
+			name: "combines currency and status filters",
+			setupBills: func(svc *BillingService) {
+				gel, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyGEL})
+				svc.CloseBill(testContext(), gel.ID, allowEmptyClose)
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyGEL})
+				usd, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CloseBill(testContext(), usd.ID, allowEmptyClose)
+			},
+			status:    "closed",
+			currency:  model.CurrencyGEL,
+			wantCount: 1,
+		},
+		{
+			name: "rejects unsupported currency",
+			setupBills: func(svc *BillingService) {
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			},
+			currency: "EUR",
+			wantErr:  true,
+		},
+		{
+			name: "rejects misspelled status",
+			setupBills: func(svc *BillingService) {
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			},
+			status:  "opne",
+			wantErr: true,
+		},
+		{
+			name: "rejects unknown status in a list",
+			setupBills: func(svc *BillingService) {
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			},
+			status:  "open,clsoed",
+			wantErr: true,
+		},
+		{
+			name: "matches any of several statuses",
+			setupBills: func(svc *BillingService) {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CloseBill(testContext(), bill.ID, allowEmptyClose)
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+			},
+			status:    "open, closed",
+			wantCount: 2,
+		},
+		{
+			name: "includes empty bills without a threshold",
+			setupBills: func(svc *BillingService) {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD})
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			},
+			wantCount: 2,
+		},
+		{
+			name: "excludes bills under minTotal",
+			setupBills: func(svc *BillingService) {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD})
+				small, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.AddLineItem(testContext(), small.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 0.50, Currency: model.CurrencyUSD})
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			},
+			minTotal:  1.00,
+			wantCount: 1,
+		},
+		{
+			name: "applies minTotal in each bill's own currency",
+			setupBills: func(svc *BillingService) {
+				usd, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.AddLineItem(testContext(), usd.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyGEL}) // 3.70 USD
+				gel, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyGEL})
+				svc.AddLineItem(testContext(), gel.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyGEL})
+			},
+			minTotal:  5.00,
+			wantCount: 1,
+		},
+		{
+			name: "combines minTotal with status",
+			setupBills: func(svc *BillingService) {
+				closed, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.AddLineItem(testContext(), closed.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD})
+				svc.CloseBill(testContext(), closed.ID, nil)
+				open, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.AddLineItem(testContext(), open.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD})
 			},
 			status:    "closed",
+			minTotal:  0.01,
 			wantCount: 1,
 		},
+		{
+			name: "filters to bills with line items",
+			setupBills: func(svc *BillingService) {
+				bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD})
+				svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			},
+			hasItems:  true,
+			wantCount: 1,
+		},
+		{
+			name: "rejects negative minTotal",
+			setupBills: func(svc *BillingService) {
+			},
+			minTotal: -1,
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := newMockBillRepository()
-			svc := NewBillingService(repo)
+			svc := newTestBillingService(t, repo)
 
 			tt.setupBills(svc)
-			bills, err := svc.ListBills(tt.status)
+			bills, _, err := svc.ListBills(testContext(), &model.ListBillsRequest{
+				Status:       tt.status,
+				Currency:     tt.currency,
+				MinTotal:     tt.minTotal,
+				HasLineItems: tt.hasItems,
+			})
 
-			if err != nil {
-				t.Errorf("ListBills() error = %v", err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListBills() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
@@ -393,3 +854,1650 @@ func TestListBills(t *testing.T) {
 		})
 	}
 }
+
+func TestRecalculateTotal(t *testing.T) {
+	repo := newMockBillRepository()
+	svc := newTestBillingService(t, repo)
+
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL})
+
+	// Simulate drift, e.g. from a data migration that touched the stored total
+	stored := repo.bills[bill.ID]
+	stored.TotalAmount = 1
+	repo.bills[bill.ID] = stored
+
+	fixed, oldTotal, err := svc.RecalculateTotal(testContext(), bill.ID)
+	if err != nil {
+		t.Fatalf("RecalculateTotal() error = %v", err)
+	}
+	if oldTotal != 1 {
+		t.Errorf("expected old total 1, got %d", oldTotal)
+	}
+	// 1000 cents + (10000 GEL cents * 0.37) = 4700 cents
+	if fixed.TotalAmount != 4700 {
+		t.Errorf("expected new total 4700, got %d", fixed.TotalAmount)
+	}
+	if repo.bills[bill.ID].TotalAmount != 4700 {
+		t.Errorf("expected corrected total to be persisted, got %d", repo.bills[bill.ID].TotalAmount)
+	}
+
+	if _, _, err := svc.RecalculateTotal(testContext(), "nonexistent"); err == nil {
+		t.Error("expected error for nonexistent bill")
+	}
+}
+
+func TestLineItemRateIsFrozen(t *testing.T) {
+	repo := newMockBillRepository()
+	rates := NewStaticRateProvider(map[model.Currency]float64{
+		model.CurrencyGEL: 0.37,
+		model.CurrencyUSD: 1.0,
+	})
+	svc := newTestBillingService(t, repo, WithRateProvider(rates))
+
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	bill, err := svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL})
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+
+	item := bill.LineItems[0]
+	if item.AppliedRate != 0.37 {
+		t.Errorf("expected applied rate 0.37, got %v", item.AppliedRate)
+	}
+	if item.ConvertedAmount != 3700 {
+		t.Errorf("expected converted amount 3700, got %d", item.ConvertedAmount)
+	}
+
+	rates.SetRate(model.CurrencyGEL, 0.5)
+
+	bill, _, err = svc.RecalculateTotal(testContext(), bill.ID)
+	if err != nil {
+		t.Fatalf("RecalculateTotal() error = %v", err)
+	}
+	if bill.TotalAmount != 3700 {
+		t.Errorf("expected total to stay 3700 after rate change, got %d", bill.TotalAmount)
+	}
+	if bill.LineItems[0].AppliedRate != 0.37 {
+		t.Errorf("expected frozen rate 0.37, got %v", bill.LineItems[0].AppliedRate)
+	}
+}
+
+func TestDirectPairRateTakesPrecedence(t *testing.T) {
+	rates := NewStaticRateProvider(map[model.Currency]float64{
+		model.CurrencyGEL: 0.37,
+		model.CurrencyUSD: 1.0,
+	})
+	negotiatedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rates.SetPairQuote(model.CurrencyGEL, model.CurrencyUSD, 0.40, negotiatedAt)
+	svc := newTestBillingService(t, newMockBillRepository(), WithRateProvider(rates))
+	ctx := testContext()
+
+	usdBill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	usdBill, err := svc.AddLineItem(ctx, usdBill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL})
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+	item := usdBill.LineItems[0]
+	if item.AppliedRate != 0.40 || item.ConvertedAmount != 4000 || !item.RateAsOf.Equal(negotiatedAt) {
+		t.Errorf("expected the direct GEL->USD rate 0.40 (4000 as of %v), got %v (%d as of %v)",
+			negotiatedAt, item.AppliedRate, item.ConvertedAmount, item.RateAsOf)
+	}
+
+	// No USD->GEL pair is set, so that direction still pivots through USD
+	gelBill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyGEL})
+	gelBill, err = svc.AddLineItem(ctx, gelBill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+	if item := gelBill.LineItems[0]; item.AppliedRate != 1.0/0.37 || item.ConvertedAmount != 2703 {
+		t.Errorf("expected the pivoted rate %v (2703), got %v (%d)", 1.0/0.37, item.AppliedRate, item.ConvertedAmount)
+	}
+}
+
+func TestPairRatesMustBePositive(t *testing.T) {
+	rates := NewStaticRateProvider(exchangeRatesToUSD)
+	rates.SetPairRate(model.CurrencyGEL, model.CurrencyUSD, 0)
+
+	if _, err := NewBillingService(newMockBillRepository(), WithRateProvider(rates)); err == nil {
+		t.Error("expected a zero pair rate to be rejected")
+	}
+}
+
+func TestRecalculateOpenBills(t *testing.T) {
+	repo := newMockBillRepository()
+	rates := NewStaticRateProvider(map[model.Currency]float64{
+		model.CurrencyGEL: 0.37,
+		model.CurrencyUSD: 1.0,
+	})
+	svc := newTestBillingService(t, repo, WithRateProvider(rates))
+	otherOrg := tenant.WithOrgID(context.Background(), "org_other")
+	gelItem := &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL}
+
+	first, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(testContext(), first.ID, gelItem)
+	svc.AddLineItem(testContext(), first.ID, &model.AddLineItemRequest{Description: "Base", Amount: 10.00, Currency: model.CurrencyUSD})
+	second, _ := svc.CreateBill(otherOrg, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(otherOrg, second.ID, gelItem)
+	closed, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(testContext(), closed.ID, gelItem)
+	svc.CloseBill(testContext(), closed.ID, &model.CloseBillRequest{})
+
+	rates.SetRate(model.CurrencyGEL, 0.5)
+
+	updated, err := svc.RecalculateOpenBills(testContext(), model.CurrencyGEL)
+	if err != nil {
+		t.Fatalf("RecalculateOpenBills() error = %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("expected 2 bills updated, got %d", updated)
+	}
+
+	if got := repo.bills[first.ID]; got.TotalAmount != 6000 || got.LineItems[0].AppliedRate != 0.5 {
+		t.Errorf("expected first bill total 6000 at rate 0.5, got %d at %v", got.TotalAmount, got.LineItems[0].AppliedRate)
+	}
+	if got := repo.bills[second.ID]; got.TotalAmount != 5000 {
+		t.Errorf("expected second bill total 5000, got %d", got.TotalAmount)
+	}
+	if got := repo.bills[closed.ID]; got.TotalAmount != 3700 || got.LineItems[0].AppliedRate != 0.37 {
+		t.Errorf("expected closed bill to keep 3700 at rate 0.37, got %d at %v", got.TotalAmount, got.LineItems[0].AppliedRate)
+	}
+
+	updated, err = svc.RecalculateOpenBills(testContext(), model.CurrencyGEL)
+	if err != nil {
+		t.Fatalf("RecalculateOpenBills() error = %v", err)
+	}
+	if updated != 0 {
+		t.Errorf("expected a second run to update nothing, got %d", updated)
+	}
+}
+
+// failingWriteRepository is a repository stub whose writes fail
+type failingWriteRepository struct {
+	*mockBillRepository
+	err error
+}
+
+func (r failingWriteRepository) Update(ctx context.Context, bill *model.Bill) error {
+	return r.err
+}
+
+func (r failingWriteRepository) WithTransaction(ctx context.Context, fn func(tx repository.BillRepository) error) error {
+	return fn(r)
+}
+
+func TestErrorsKeepContextWhenWrapped(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+	item := &model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: model.CurrencyUSD}
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.CloseBill(ctx, bill.ID, allowEmptyClose)
+	_, err := svc.AddLineItem(ctx, bill.ID, item)
+	if !strings.HasPrefix(err.Error(), "add line item: ") {
+		t.Errorf("expected the error to name the operation, got %q", err)
+	}
+	if !errors.Is(err, billingerrors.ErrBillClosed) {
+		t.Errorf("expected errors.Is(err, ErrBillClosed), got %v", err)
+	}
+
+	_, err = svc.AddLineItem(ctx, "bill_missing", item)
+	if !errors.Is(err, billingerrors.ErrBillNotFound) || billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+		t.Errorf("expected a not_found error matching ErrBillNotFound, got %v", err)
+	}
+
+	errWrite := errors.New("disk full")
+	failing := newTestBillingService(t, failingWriteRepository{mockBillRepository: newMockBillRepository(), err: errWrite})
+	open, _ := failing.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	_, err = failing.AddLineItem(ctx, open.ID, item)
+	if !errors.Is(err, errWrite) || err.Error() != "add line item: disk full" {
+		t.Errorf("expected the repository error wrapped with context, got %v", err)
+	}
+}
+
+func TestTotalsByCategory(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	items := []*model.AddLineItemRequest{
+		{Description: "Card fee", Amount: 2.50, Currency: model.CurrencyUSD, Category: model.CategoryProcessing},
+		{Description: "Card fee", Amount: 100.00, Currency: model.CurrencyGEL, Category: model.CategoryProcessing},
+		{Description: "Late fee", Amount: 15.00, Currency: model.CurrencyUSD, Category: model.CategoryPenalty},
+		{Description: "Misc", Amount: 1.00, Currency: model.CurrencyUSD},
+	}
+	for _, item := range items {
+		if _, err := svc.AddLineItem(testContext(), bill.ID, item); err != nil {
+			t.Fatalf("AddLineItem() error = %v", err)
+		}
+	}
+
+	totals, err := svc.TotalsByCategory(testContext(), bill.ID)
+	if err != nil {
+		t.Fatalf("TotalsByCategory() error = %v", err)
+	}
+
+	want := map[model.LineItemCategory]int64{
+		model.CategoryProcessing: 250 + 3700,
+		model.CategoryPenalty:    1500,
+		model.CategoryOther:      100,
+	}
+	var sum int64
+	for category, amount := range want {
+		if totals[category] != amount {
+			t.Errorf("category %s: expected %d, got %d", category, amount, totals[category])
+		}
+		sum += totals[category]
+	}
+
+	bill, _ = svc.GetBill(testContext(), bill.ID, false)
+	if sum != bill.TotalAmount {
+		t.Errorf("category totals sum to %d, bill total is %d", sum, bill.TotalAmount)
+	}
+}
+
+func TestAddLineItemRejectsUnknownCategory(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+	_, err := svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{
+		Description: "Fee",
+		Amount:      10.00,
+		Currency:    model.CurrencyUSD,
+		Category:    "bribes",
+	})
+	if err == nil {
+		t.Error("expected error for unknown category")
+	}
+}
+
+func TestDraftBillLifecycle(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, err := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if bill.Status != model.BillStatusDraft {
+		t.Fatalf("expected draft, got %v", bill.Status)
+	}
+
+	if _, err := svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Setup", Amount: 5.00, Currency: model.CurrencyUSD}); err != nil {
+		t.Errorf("expected drafts to accept line items, got %v", err)
+	}
+
+	if _, err := svc.CloseBill(testContext(), bill.ID, nil); err == nil {
+		t.Error("expected close on a draft to be rejected")
+	}
+
+	drafts, _, _ := svc.ListBills(testContext(), &model.ListBillsRequest{Status: string(model.BillStatusDraft)})
+	if len(drafts) != 1 {
+		t.Errorf("expected 1 draft bill, got %d", len(drafts))
+	}
+
+	bill, err = svc.ActivateBill(testContext(), bill.ID, nil)
+	if err != nil {
+		t.Fatalf("ActivateBill() error = %v", err)
+	}
+	if bill.Status != model.BillStatusOpen {
+		t.Errorf("expected open after activation, got %v", bill.Status)
+	}
+
+	if _, err := svc.ActivateBill(testContext(), bill.ID, nil); err == nil {
+		t.Error("expected activating an open bill to fail")
+	}
+
+	bill, err = svc.CloseBill(testContext(), bill.ID, nil)
+	if err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+	if bill.Status != model.BillStatusClosed || bill.TotalAmount != 500 {
+		t.Errorf("expected closed bill with total 500, got %v %d", bill.Status, bill.TotalAmount)
+	}
+}
+
+func TestCloseBillSnapshotsFinalTotal(t *testing.T) {
+	rates := NewStaticRateProvider(map[model.Currency]float64{
+		model.CurrencyGEL: 0.37,
+		model.CurrencyUSD: 1.0,
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithRateProvider(rates))
+
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL})
+	svc.CloseBill(testContext(), bill.ID, nil)
+
+	rates.SetRate(model.CurrencyGEL, 0.5)
+	svc.RecalculateTotal(testContext(), bill.ID)
+
+	bill, _ = svc.GetBill(testContext(), bill.ID, false)
+	if bill.FinalTotal == nil || *bill.FinalTotal != 3700 {
+		t.Errorf("expected final total 3700, got %v", bill.FinalTotal)
+	}
+	if bill.FinalLineItemCount == nil || *bill.FinalLineItemCount != 1 {
+		t.Errorf("expected final line item count 1, got %v", bill.FinalLineItemCount)
+	}
+}
+
+func TestIdempotentCloseBill(t *testing.T) {
+	ctx := testContext()
+	topic := events.NewTopic()
+	var closedEvents int
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		if event.Type == events.EventBillClosed {
+			closedEvents++
+		}
+		return nil
+	})
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock), WithPublisher(topic))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	first, err := svc.CloseBill(ctx, bill.ID, &model.CloseBillRequest{Idempotent: true})
+	if err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+
+	clock.Advance(time.Hour)
+	again, err := svc.CloseBill(ctx, bill.ID, &model.CloseBillRequest{Idempotent: true})
+	if err != nil {
+		t.Fatalf("expected a repeat idempotent close to succeed, got %v", err)
+	}
+	if !reflect.DeepEqual(again, first) {
+		t.Errorf("expected the repeat close to return the bill as first closed, got %+v, want %+v", again, first)
+	}
+	if closedEvents != 1 {
+		t.Errorf("expected one closed event, got %d", closedEvents)
+	}
+
+	if _, err := svc.CloseBill(ctx, bill.ID, nil); !errors.Is(err, billingerrors.ErrBillClosed) {
+		t.Errorf("expected a non-idempotent repeat close to fail with ErrBillClosed, got %v", err)
+	}
+
+	// A bill in a state that can't be closed still conflicts
+	draft, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+	if _, err := svc.CloseBill(ctx, draft.ID, &model.CloseBillRequest{Idempotent: true, AllowEmpty: true}); err == nil {
+		t.Error("expected an idempotent close of a draft to fail")
+	}
+}
+
+func TestReopenBillClearsSnapshot(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	svc.CloseBill(testContext(), bill.ID, nil)
+
+	bill, err := svc.ReopenBill(testContext(), bill.ID)
+	if err != nil {
+		t.Fatalf("ReopenBill() error = %v", err)
+	}
+	if bill.Status != model.BillStatusOpen || bill.ClosedAt != nil {
+		t.Errorf("expected open bill without ClosedAt, got %v %v", bill.Status, bill.ClosedAt)
+	}
+	if bill.FinalTotal != nil || bill.FinalLineItemCount != nil {
+		t.Error("expected snapshot to be cleared on reopen")
+	}
+
+	if _, err := svc.ReopenBill(testContext(), bill.ID); err == nil {
+		t.Error("expected reopening an open bill to fail")
+	}
+
+	svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD})
+	bill, _ = svc.CloseBill(testContext(), bill.ID, nil)
+	if bill.FinalTotal == nil || *bill.FinalTotal != 1500 {
+		t.Errorf("expected re-snapshotted final total 1500, got %v", bill.FinalTotal)
+	}
+	if bill.FinalLineItemCount == nil || *bill.FinalLineItemCount != 2 {
+		t.Errorf("expected re-snapshotted count 2, got %v", bill.FinalLineItemCount)
+	}
+}
+
+func TestSoftDeleteAndRestoreBill(t *testing.T) {
+	ctx := testContext()
+	topic := events.NewTopic()
+	var published []events.EventType
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		published = append(published, event.Type)
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(topic))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+	deleted, err := svc.SoftDeleteBill(ctx, bill.ID)
+	if err != nil {
+		t.Fatalf("SoftDeleteBill() error = %v", err)
+	}
+	if deleted.DeletedAt == nil {
+		t.Error("expected DeletedAt to be set")
+	}
+
+	if _, err := svc.GetBill(ctx, bill.ID, false); err == nil {
+		t.Error("expected soft-deleted bill to be hidden from GetBill")
+	}
+	if _, err := svc.GetBill(ctx, bill.ID, true); err != nil {
+		t.Errorf("expected includeDeleted to surface the bill, got %v", err)
+	}
+	if _, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: model.CurrencyUSD}); err == nil {
+		t.Error("expected soft-deleted bill to reject line items")
+	}
+
+	bills, _, _ := svc.ListBills(ctx, &model.ListBillsRequest{})
+	if len(bills) != 1 {
+		t.Errorf("expected 1 bill in default listing, got %d", len(bills))
+	}
+	bills, _, _ = svc.ListBills(ctx, &model.ListBillsRequest{IncludeDeleted: true})
+	if len(bills) != 2 {
+		t.Errorf("expected 2 bills with includeDeleted, got %d", len(bills))
+	}
+
+	restored, err := svc.RestoreBill(ctx, bill.ID)
+	if err != nil {
+		t.Fatalf("RestoreBill() error = %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Error("expected DeletedAt to be cleared")
+	}
+	if _, err := svc.RestoreBill(ctx, bill.ID); err == nil {
+		t.Error("expected restoring a live bill to fail")
+	}
+
+	bills, _, _ = svc.ListBills(ctx, &model.ListBillsRequest{})
+	if len(bills) != 2 {
+		t.Errorf("expected restored bill back in listing, got %d", len(bills))
+	}
+
+	want := []events.EventType{events.EventBillCreated, events.EventBillCreated, events.EventBillDeleted, events.EventBillRestored}
+	if len(published) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, published)
+	}
+	for i := range want {
+		if published[i] != want[i] {
+			t.Errorf("event %d: expected %v, got %v", i, want[i], published[i])
+		}
+	}
+}
+
+func TestLineItemMetadata(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+	metadata := map[string]string{"orderId": "ord_123", "sku": "PLAN-PRO"}
+	_, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{
+		Description: "Subscription",
+		Amount:      49.00,
+		Currency:    model.CurrencyUSD,
+		Metadata:    metadata,
+	})
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+	metadata["orderId"] = "mutated"
+
+	stored, _ := svc.GetBill(ctx, bill.ID, false)
+	got := stored.LineItems[0].Metadata
+	if len(got) != 2 || got["orderId"] != "ord_123" || got["sku"] != "PLAN-PRO" {
+		t.Errorf("expected metadata to round-trip unchanged, got %v", got)
+	}
+}
+
+func TestLineItemMetadataLimits(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i <= maxMetadataKeys; i++ {
+		tooMany[string(rune('a'+i))] = "v"
+	}
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		wantErr  bool
+	}{
+		{name: "at key limit", metadata: map[string]string{"k": strings.Repeat("v", maxMetadataValueLength)}, wantErr: false},
+		{name: "too many keys", metadata: tooMany, wantErr: true},
+		{name: "key too long", metadata: map[string]string{strings.Repeat("k", maxMetadataKeyLength+1): "v"}, wantErr: true},
+		{name: "multibyte key at limit", metadata: map[string]string{strings.Repeat("ქ", maxMetadataKeyLength): "v"}, wantErr: false},
+		{name: "multibyte key too long", metadata: map[string]string{strings.Repeat("ქ", maxMetadataKeyLength+1): "v"}, wantErr: true},
+		{name: "value too long", metadata: map[string]string{"k": strings.Repeat("v", maxMetadataValueLength+1)}, wantErr: true},
+		{name: "empty key", metadata: map[string]string{"": "v"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext()
+			svc := newTestBillingService(t, newMockBillRepository())
+			bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+			_, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{
+				Description: "Fee",
+				Amount:      1.00,
+				Currency:    model.CurrencyUSD,
+				Metadata:    tt.metadata,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddLineItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDescriptionTooLong(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+	_, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{
+		Description: strings.Repeat("x", model.MaxDescriptionLength+1),
+		Amount:      1.00,
+		Currency:    model.CurrencyUSD,
+	})
+	if billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+		t.Fatalf("expected invalid_argument, got %v", err)
+	}
+	if fields := billingerrors.FieldsOf(err); len(fields) != 1 || fields[0].Field != "description" {
+		t.Errorf("expected a violation for description, got %+v", fields)
+	}
+}
+
+func TestListBillTotals(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	amounts := []struct {
+		currency model.Currency
+		amount   float64
+	}{
+		{model.CurrencyUSD, 10.00},
+		{model.CurrencyUSD, 2.50},
+		{model.CurrencyGEL, 100.00},
+		{model.CurrencyGEL, 0.75},
+	}
+	want := make(map[model.Currency]int64)
+	for _, a := range amounts {
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: a.currency})
+		svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: a.amount, Currency: a.currency})
+		want[a.currency] += int64(a.amount * 100)
+	}
+
+	totals, err := svc.ListBillTotals(ctx, &model.ListBillsRequest{})
+	if err != nil {
+		t.Fatalf("ListBillTotals() error = %v", err)
+	}
+	for currency, amount := range want {
+		if totals[currency] != amount {
+			t.Errorf("%s: expected %d, got %d", currency, amount, totals[currency])
+		}
+	}
+
+	totals, _ = svc.ListBillTotals(ctx, &model.ListBillsRequest{Currency: model.CurrencyGEL})
+	if len(totals) != 1 || totals[model.CurrencyGEL] != want[model.CurrencyGEL] {
+		t.Errorf("expected only GEL totals, got %v", totals)
+	}
+}
+
+// Run with -race: concurrent creates and reads must not race or lose bills
+func TestConcurrentCreateAndGetBill(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, repository.NewInMemoryBillRepository())
+
+	const workers = 16
+	const perWorker = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*perWorker)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				bill, err := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+				if err != nil {
+					errs <- err
+					continue
+				}
+				if _, err := svc.GetBill(ctx, bill.ID, false); err != nil {
+					errs <- err
+				}
+				if _, _, err := svc.ListBills(ctx, &model.ListBillsRequest{}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	bills, _, _ := svc.ListBills(ctx, &model.ListBillsRequest{})
+	if len(bills) != workers*perWorker {
+		t.Errorf("expected %d bills, got %d", workers*perWorker, len(bills))
+	}
+}
+
+func TestConvertCurrency(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *model.ConvertCurrencyRequest
+		wantConverted int64
+		wantErr       bool
+	}{
+		{
+			name:          "USD to GEL",
+			req:           &model.ConvertCurrencyRequest{Amount: 37.00, From: model.CurrencyUSD, To: model.CurrencyGEL},
+			wantConverted: 10000,
+		},
+		{
+			name:          "GEL to USD",
+			req:           &model.ConvertCurrencyRequest{Amount: 100.00, From: model.CurrencyGEL, To: model.CurrencyUSD},
+			wantConverted: 3700,
+		},
+		{
+			name:          "same currency",
+			req:           &model.ConvertCurrencyRequest{Amount: 12.34, From: model.CurrencyUSD, To: model.CurrencyUSD},
+			wantConverted: 1234,
+		},
+		{
+			name:    "unsupported source currency",
+			req:     &model.ConvertCurrencyRequest{Amount: 10.00, From: "EUR", To: model.CurrencyUSD},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported target currency",
+			req:     &model.ConvertCurrencyRequest{Amount: 10.00, From: model.CurrencyGEL, To: "EUR"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestBillingService(t, newMockBillRepository())
+
+			conversion, err := svc.ConvertCurrency(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertCurrency() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if conversion.ConvertedAmount != tt.wantConverted {
+				t.Errorf("expected %d, got %d", tt.wantConverted, conversion.ConvertedAmount)
+			}
+
+			// The preview must match what AddLineItem freezes on a bill in the target currency
+			bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: tt.req.To})
+			bill, _ = svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: tt.req.Amount, Currency: tt.req.From})
+			if bill.LineItems[0].ConvertedAmount != conversion.ConvertedAmount || bill.LineItems[0].AppliedRate != conversion.Rate {
+				t.Errorf("AddLineItem froze %d at %v, preview gave %d at %v",
+					bill.LineItems[0].ConvertedAmount, bill.LineItems[0].AppliedRate, conversion.ConvertedAmount, conversion.Rate)
+			}
+		})
+	}
+}
+
+func TestListCurrencies(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	currencies, err := svc.ListCurrencies()
+	if err != nil {
+		t.Fatalf("ListCurrencies() error = %v", err)
+	}
+	if len(currencies) != len(model.SupportedCurrencies) {
+		t.Fatalf("expected %d currencies, got %d", len(model.SupportedCurrencies), len(currencies))
+	}
+	for i, currency := range model.SupportedCurrencies {
+		info := currencies[i]
+		if info.Code != currency || info.DecimalPlaces != currency.DecimalPlaces() {
+			t.Errorf("expected %s with %d decimal places, got %+v", currency, currency.DecimalPlaces(), info)
+		}
+		quote, _ := svc.quote(currency, model.CurrencyUSD)
+		if info.RateToUSD != quote.Rate {
+			t.Errorf("expected %s rate %v from the provider, got %v", currency, quote.Rate, info.RateToUSD)
+		}
+	}
+}
+
+func TestSetExchangeRate(t *testing.T) {
+	ctx := testContext()
+	logger := &capturingLogger{}
+	svc := newTestBillingService(t, newMockBillRepository(), WithLogger(logger))
+	gel := model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL}
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &gel)
+
+	info, err := svc.SetExchangeRate(tenant.WithActor(ctx, "alice"), model.CurrencyGEL, 0.40)
+	if err != nil {
+		t.Fatalf("SetExchangeRate() error = %v", err)
+	}
+	if info.RateToUSD != 0.40 {
+		t.Errorf("expected the new rate 0.40, got %v", info.RateToUSD)
+	}
+	if _, actor, ok := logger.find("exchange rate overridden", "actor"); !ok || actor != "alice" {
+		t.Errorf("expected the override to be audit logged with actor alice, got %v", actor)
+	}
+
+	bill, _ = svc.AddLineItem(ctx, bill.ID, &gel)
+	if bill.LineItems[0].ConvertedAmount != 3700 || bill.LineItems[1].ConvertedAmount != 4000 {
+		t.Errorf("expected the existing item to keep 3700 and the new one to use 4000, got %d and %d",
+			bill.LineItems[0].ConvertedAmount, bill.LineItems[1].ConvertedAmount)
+	}
+
+	invalid := []struct {
+		name     string
+		currency model.Currency
+		rate     float64
+	}{
+		{name: "zero rate", currency: model.CurrencyGEL, rate: 0},
+		{name: "negative rate", currency: model.CurrencyGEL, rate: -0.37},
+		{name: "unsupported currency", currency: "EUR", rate: 1.08},
+		{name: "USD other than 1", currency: model.CurrencyUSD, rate: 1.1},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SetExchangeRate(ctx, tt.currency, tt.rate); err == nil {
+				t.Error("expected an error")
+			}
+			if quote, _ := svc.quote(model.CurrencyGEL, model.CurrencyUSD); quote.Rate != 0.40 {
+				t.Errorf("expected the GEL rate to stay 0.40, got %v", quote.Rate)
+			}
+		})
+	}
+}
+
+func TestBillNote(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, err := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Note: "VIP account"})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if bill.Note != "VIP account" {
+		t.Errorf("expected note from create request, got %q", bill.Note)
+	}
+
+	// Notes stay editable after close
+	svc.CloseBill(ctx, bill.ID, allowEmptyClose)
+	if _, err := svc.UpdateNote(ctx, bill.ID, "disputed"); err != nil {
+		t.Fatalf("UpdateNote() on closed bill error = %v", err)
+	}
+
+	bill, _ = svc.GetBill(ctx, bill.ID, false)
+	if bill.Note != "disputed" {
+		t.Errorf("expected updated note, got %q", bill.Note)
+	}
+
+	if _, err := svc.UpdateNote(ctx, bill.ID, strings.Repeat("n", maxNoteLength+1)); err == nil {
+		t.Error("expected error for an overlong note")
+	}
+	if _, err := svc.CreateBill(ctx, &model.CreateBillRequest{Note: strings.Repeat("n", maxNoteLength+1)}); err == nil {
+		t.Error("expected CreateBill to reject an overlong note")
+	}
+	if _, err := svc.UpdateNote(ctx, "nonexistent", "note"); err == nil {
+		t.Error("expected error for nonexistent bill")
+	}
+}
+
+func TestNewBillingServiceValidatesRates(t *testing.T) {
+	tests := []struct {
+		name    string
+		rates   map[model.Currency]float64
+		wantErr bool
+	}{
+		{name: "complete rates", rates: map[model.Currency]float64{model.CurrencyUSD: 1.0, model.CurrencyGEL: 0.37}},
+		{name: "missing GEL", rates: map[model.Currency]float64{model.CurrencyUSD: 1.0}, wantErr: true},
+		{name: "zero rate", rates: map[model.Currency]float64{model.CurrencyUSD: 1.0, model.CurrencyGEL: 0}, wantErr: true},
+		{name: "USD not pivot", rates: map[model.Currency]float64{model.CurrencyUSD: 1.1, model.CurrencyGEL: 0.37}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBillingService(newMockBillRepository(), WithRateProvider(NewStaticRateProvider(tt.rates)))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewBillingService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplaceLineItems(t *testing.T) {
+	ctx := testContext()
+	topic := events.NewTopic()
+	var published []events.EventType
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		published = append(published, event.Type)
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(topic))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	bill, _ = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Old fee", Amount: 50.00, Currency: model.CurrencyUSD})
+	oldItemID := bill.LineItems[0].ID
+
+	bill, err := svc.ReplaceLineItems(ctx, bill.ID, []model.AddLineItemRequest{
+		{Description: "Processing", Amount: 10.00, Currency: model.CurrencyUSD},
+		{Description: "Penalty", Amount: 100.00, Currency: model.CurrencyGEL, Category: model.CategoryPenalty},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceLineItems() error = %v", err)
+	}
+
+	if len(bill.LineItems) != 2 {
+		t.Fatalf("expected 2 line items, got %d", len(bill.LineItems))
+	}
+	for _, item := range bill.LineItems {
+		if item.ID == oldItemID {
+			t.Errorf("expected old line item %s to be removed", oldItemID)
+		}
+	}
+	// 10.00 USD + 100 GEL (37.00 USD); the old 50.00 USD no longer counts
+	if bill.TotalAmount != 4700 {
+		t.Errorf("expected total 4700, got %d", bill.TotalAmount)
+	}
+	if published[len(published)-1] != events.EventLineItemsReplaced {
+		t.Errorf("expected line_items_replaced event, got %v", published)
+	}
+
+	// An invalid item rejects the whole set and leaves the bill untouched
+	if _, err := svc.ReplaceLineItems(ctx, bill.ID, []model.AddLineItemRequest{
+		{Description: "Valid", Amount: 1.00, Currency: model.CurrencyUSD},
+		{Description: "Invalid", Amount: -1.00, Currency: model.CurrencyUSD},
+	}); err == nil {
+		t.Error("expected error for an invalid line item")
+	}
+	stored, _ := svc.GetBill(ctx, bill.ID, false)
+	if len(stored.LineItems) != 2 || stored.TotalAmount != 4700 {
+		t.Errorf("expected bill unchanged after rejected replace, got %d items total %d", len(stored.LineItems), stored.TotalAmount)
+	}
+
+	svc.CloseBill(ctx, bill.ID, nil)
+	if _, err := svc.ReplaceLineItems(ctx, bill.ID, nil); err == nil {
+		t.Error("expected error replacing line items on a closed bill")
+	}
+}
+
+func TestMaxLineItems(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository(), WithMaxLineItems(3))
+	item := model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: model.CurrencyUSD}
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	for i := 0; i < 3; i++ {
+		if _, err := svc.AddLineItem(ctx, bill.ID, &item); err != nil {
+			t.Fatalf("AddLineItem() %d error = %v", i+1, err)
+		}
+	}
+
+	_, err := svc.AddLineItem(ctx, bill.ID, &item)
+	if err == nil || !strings.Contains(err.Error(), "more than 3 line items") {
+		t.Fatalf("expected line item cap error, got %v", err)
+	}
+	stored, _ := svc.GetBill(ctx, bill.ID, false)
+	if len(stored.LineItems) != 3 || stored.TotalAmount != 300 {
+		t.Errorf("expected bill to stay at 3 items totalling 300, got %d totalling %d", len(stored.LineItems), stored.TotalAmount)
+	}
+
+	if _, err := svc.ReplaceLineItems(ctx, bill.ID, []model.AddLineItemRequest{item, item, item, item}); err == nil {
+		t.Error("expected ReplaceLineItems to reject a set over the cap")
+	}
+	if _, err := svc.ReplaceLineItems(ctx, bill.ID, []model.AddLineItemRequest{item, item, item}); err != nil {
+		t.Errorf("expected ReplaceLineItems to accept a set at the cap, got %v", err)
+	}
+}
+
+func TestCreditLineItems(t *testing.T) {
+	ctx := testContext()
+	charge := model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD}
+	credit := model.AddLineItemRequest{Description: "Refund", Amount: 4.00, Currency: model.CurrencyUSD, Type: model.LineItemTypeCredit}
+	bigCredit := model.AddLineItemRequest{Description: "Refund", Amount: 15.00, Currency: model.CurrencyUSD, Type: model.LineItemTypeCredit}
+
+	t.Run("charge defaults and credit subtracts", func(t *testing.T) {
+		svc := newTestBillingService(t, newMockBillRepository())
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		svc.AddLineItem(ctx, bill.ID, &charge)
+		bill, err := svc.AddLineItem(ctx, bill.ID, &credit)
+		if err != nil {
+			t.Fatalf("AddLineItem() error = %v", err)
+		}
+		if bill.LineItems[0].Type != model.LineItemTypeCharge || bill.LineItems[1].Type != model.LineItemTypeCredit {
+			t.Errorf("expected types charge and credit, got %q and %q", bill.LineItems[0].Type, bill.LineItems[1].Type)
+		}
+		if bill.TotalAmount != 600 {
+			t.Errorf("expected total 600, got %d", bill.TotalAmount)
+		}
+	})
+
+	t.Run("credit may go negative by default", func(t *testing.T) {
+		svc := newTestBillingService(t, newMockBillRepository())
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		svc.AddLineItem(ctx, bill.ID, &charge)
+		bill, err := svc.AddLineItem(ctx, bill.ID, &bigCredit)
+		if err != nil || bill.TotalAmount != -500 {
+			t.Errorf("expected total -500 without error, got %v (err %v)", bill, err)
+		}
+	})
+
+	t.Run("NoNegativeTotal rejects an oversized credit", func(t *testing.T) {
+		svc := newTestBillingService(t, newMockBillRepository(), WithNoNegativeTotal(true))
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		svc.AddLineItem(ctx, bill.ID, &charge)
+
+		_, err := svc.AddLineItem(ctx, bill.ID, &bigCredit)
+		if err == nil || !strings.Contains(err.Error(), "credit exceeds") {
+			t.Fatalf("expected credit exceeds total error, got %v", err)
+		}
+		stored, _ := svc.GetBill(ctx, bill.ID, false)
+		if len(stored.LineItems) != 1 || stored.TotalAmount != 1000 {
+			t.Errorf("expected bill unchanged at 1000, got %d items totalling %d", len(stored.LineItems), stored.TotalAmount)
+		}
+
+		if _, err := svc.ReplaceLineItems(ctx, bill.ID, []model.AddLineItemRequest{charge, bigCredit}); err == nil {
+			t.Error("expected ReplaceLineItems to reject a negative total")
+		}
+		if _, err := svc.AddLineItem(ctx, bill.ID, &credit); err != nil {
+			t.Errorf("expected a credit within the total to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("unknown type is rejected", func(t *testing.T) {
+		svc := newTestBillingService(t, newMockBillRepository())
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		_, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 1, Currency: model.CurrencyUSD, Type: "debit"})
+		if err == nil || !strings.Contains(err.Error(), "unsupported line item type") {
+			t.Errorf("expected unsupported type error, got %v", err)
+		}
+	})
+}
+
+func TestDuplicateLineItemDetection(t *testing.T) {
+	ctx := testContext()
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock), WithDedupWindow(10*time.Second))
+	item := model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD}
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	first, err := svc.AddLineItem(ctx, bill.ID, &item)
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+
+	clock.Advance(3 * time.Second)
+	_, err = svc.AddLineItem(ctx, bill.ID, &item)
+	if billingerrors.CodeOf(err) != billingerrors.CodeConflict || !strings.Contains(err.Error(), first.LineItems[0].ID) {
+		t.Fatalf("expected a conflict naming %s, got %v", first.LineItems[0].ID, err)
+	}
+
+	other := item
+	other.Currency = model.CurrencyGEL
+	if _, err := svc.AddLineItem(ctx, bill.ID, &other); err != nil {
+		t.Errorf("expected an item in another currency to be accepted, got %v", err)
+	}
+
+	forced := item
+	forced.Force = true
+	if _, err := svc.AddLineItem(ctx, bill.ID, &forced); err != nil {
+		t.Errorf("expected force to override the dedup check, got %v", err)
+	}
+
+	clock.Advance(10 * time.Second)
+	bill, err = svc.AddLineItem(ctx, bill.ID, &item)
+	if err != nil {
+		t.Fatalf("expected the item to be accepted once the window passed, got %v", err)
+	}
+	if len(bill.LineItems) != 4 {
+		t.Errorf("expected 4 line items, got %d", len(bill.LineItems))
+	}
+}
+
+func TestLineItemRefIsUniquePerBill(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+	item := model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD, Ref: "ext_1"}
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	first, err := svc.AddLineItem(ctx, bill.ID, &item)
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+	if first.LineItems[0].Ref != "ext_1" {
+		t.Errorf("expected ref ext_1, got %q", first.LineItems[0].Ref)
+	}
+
+	// Force only skips the dedup window; it doesn't lift the ref constraint
+	reused := model.AddLineItemRequest{Description: "Other fee", Amount: 7.00, Currency: model.CurrencyUSD, Ref: "ext_1", Force: true}
+	_, err = svc.AddLineItem(ctx, bill.ID, &reused)
+	if billingerrors.CodeOf(err) != billingerrors.CodeConflict || !strings.Contains(err.Error(), first.LineItems[0].ID) {
+		t.Fatalf("expected a conflict naming %s, got %v", first.LineItems[0].ID, err)
+	}
+
+	other, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if _, err := svc.AddLineItem(ctx, other.ID, &item); err != nil {
+		t.Errorf("expected the ref to be accepted on another bill, got %v", err)
+	}
+
+	_, err = svc.ReplaceLineItems(ctx, other.ID, []model.AddLineItemRequest{
+		{Description: "A", Amount: 1.00, Currency: model.CurrencyUSD, Ref: "ext_2"},
+		{Description: "B", Amount: 2.00, Currency: model.CurrencyUSD, Ref: "ext_2"},
+	})
+	if billingerrors.CodeOf(err) != billingerrors.CodeConflict {
+		t.Errorf("expected a conflict replacing with a repeated ref, got %v", err)
+	}
+}
+
+func TestLineItemEmptyRefIsUnconstrained(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	for _, description := range []string{"First", "Second", "Third"} {
+		var err error
+		bill, err = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: description, Amount: 1.00, Currency: model.CurrencyUSD})
+		if err != nil {
+			t.Fatalf("AddLineItem(%s) error = %v", description, err)
+		}
+	}
+	if len(bill.LineItems) != 3 {
+		t.Errorf("expected 3 line items without refs, got %d", len(bill.LineItems))
+	}
+}
+
+func TestMaxLineItemAmount(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            model.AddLineItemRequest
+		wantErr        bool
+		wantCode       billingerrors.Code
+		wantApprovedBy string
+	}{
+		{name: "under cap", req: model.AddLineItemRequest{Amount: 500.00, Currency: model.CurrencyUSD}},
+		{name: "converted under cap", req: model.AddLineItemRequest{Amount: 1000.00, Currency: model.CurrencyGEL}},
+		{name: "over cap rejected", req: model.AddLineItemRequest{Amount: 500.01, Currency: model.CurrencyUSD}, wantErr: true, wantCode: billingerrors.CodeInvalidArgument},
+		{
+			name:           "over cap with override",
+			req:            model.AddLineItemRequest{Amount: 5000.00, Currency: model.CurrencyUSD, Approved: true, ApproverID: "mgr_1"},
+			wantApprovedBy: "mgr_1",
+		},
+		{name: "override without approver", req: model.AddLineItemRequest{Amount: 5000.00, Currency: model.CurrencyUSD, Approved: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext()
+			svc := newTestBillingService(t, newMockBillRepository(), WithMaxLineItemAmount(50000))
+			bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+			tt.req.Description = "Fee"
+			bill, err := svc.AddLineItem(ctx, bill.ID, &tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if tt.wantCode != "" && billingerrors.CodeOf(err) != tt.wantCode {
+					t.Errorf("expected code %q, got %v", tt.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddLineItem() error = %v", err)
+			}
+			if got := bill.LineItems[0].ApprovedBy; got != tt.wantApprovedBy {
+				t.Errorf("expected approvedBy %q, got %q", tt.wantApprovedBy, got)
+			}
+		})
+	}
+}
+
+func TestMaxAutoCloseTotal(t *testing.T) {
+	tests := []struct {
+		name           string
+		amount         float64
+		approve        string // approver passed to ApproveBill before closing
+		closeApprover  string // approver on the close request
+		addAfter       float64
+		wantPending    bool
+		wantApprovedBy string
+	}{
+		{name: "under threshold closes", amount: 999.99},
+		{name: "at threshold closes", amount: 1000.00},
+		{name: "over threshold needs approval", amount: 1000.01, wantPending: true},
+		{name: "approved on close", amount: 5000.00, closeApprover: "mgr_1", wantApprovedBy: "mgr_1"},
+		{name: "approved then closed", amount: 5000.00, approve: "mgr_2", wantApprovedBy: "mgr_2"},
+		{name: "approval lapses when the bill grows", amount: 5000.00, approve: "mgr_2", addAfter: 1.00, wantPending: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext()
+			svc := newTestBillingService(t, newMockBillRepository(), WithMaxAutoCloseTotal(100000))
+			bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: tt.amount, Currency: model.CurrencyUSD})
+
+			if tt.approve != "" {
+				approved, err := svc.ApproveBill(ctx, bill.ID, tt.approve)
+				if err != nil {
+					t.Fatalf("ApproveBill() error = %v", err)
+				}
+				if approved.Status != model.BillStatusOpen || approved.CloseApproval == nil || approved.CloseApproval.ApprovedBy != tt.approve {
+					t.Fatalf("expected an open bill approved by %s, got %s %+v", tt.approve, approved.Status, approved.CloseApproval)
+				}
+			}
+			if tt.addAfter > 0 {
+				svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Extra", Amount: tt.addAfter, Currency: model.CurrencyUSD})
+			}
+
+			closed, err := svc.CloseBill(ctx, bill.ID, &model.CloseBillRequest{ApprovedBy: tt.closeApprover})
+			if tt.wantPending {
+				if !errors.Is(err, billingerrors.ErrPendingApproval) || billingerrors.CodeOf(err) != billingerrors.CodeConflict {
+					t.Fatalf("expected a pending approval conflict, got %v", err)
+				}
+				if stored, _ := svc.GetBill(ctx, bill.ID, false); stored.Status != model.BillStatusOpen {
+					t.Errorf("expected the bill to stay open, got %s", stored.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CloseBill() error = %v", err)
+			}
+			if tt.wantApprovedBy == "" && closed.CloseApproval != nil {
+				t.Errorf("expected no approval recorded, got %+v", closed.CloseApproval)
+			}
+			if tt.wantApprovedBy != "" && (closed.CloseApproval == nil || closed.CloseApproval.ApprovedBy != tt.wantApprovedBy || closed.CloseApproval.Total != closed.TotalAmount) {
+				t.Errorf("expected approval by %s for %d recorded, got %+v", tt.wantApprovedBy, closed.TotalAmount, closed.CloseApproval)
+			}
+		})
+	}
+}
+
+func TestApproveBillRequiresOpenBill(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository(), WithMaxAutoCloseTotal(100000))
+
+	draft, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+	if _, err := svc.ApproveBill(ctx, draft.ID, "mgr_1"); err == nil {
+		t.Error("expected approving a draft to fail")
+	}
+	closed, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.CloseBill(ctx, closed.ID, allowEmptyClose)
+	if _, err := svc.ApproveBill(ctx, closed.ID, "mgr_1"); !errors.Is(err, billingerrors.ErrBillClosed) {
+		t.Errorf("expected approving a closed bill to fail with ErrBillClosed, got %v", err)
+	}
+	open, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if _, err := svc.ApproveBill(ctx, open.ID, ""); err == nil {
+		t.Error("expected an approver to be required")
+	}
+}
+
+func TestFXMarkup(t *testing.T) {
+	ctx := testContext()
+	usd := model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD}
+	gel := model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL}
+
+	t.Run("no markup by default", func(t *testing.T) {
+		svc := newTestBillingService(t, newMockBillRepository())
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		bill, _ = svc.AddLineItem(ctx, bill.ID, &gel)
+		if bill.FXFees != 0 || bill.TotalAmount != 3700 {
+			t.Errorf("expected no FX fees and total 3700, got %d and %d", bill.FXFees, bill.TotalAmount)
+		}
+	})
+
+	t.Run("markup applies only to converted items", func(t *testing.T) {
+		svc := newTestBillingService(t, newMockBillRepository(), WithFXMarkup(0.02))
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		svc.AddLineItem(ctx, bill.ID, &usd)
+		bill, err := svc.AddLineItem(ctx, bill.ID, &gel)
+		if err != nil {
+			t.Fatalf("AddLineItem() error = %v", err)
+		}
+
+		if bill.LineItems[0].FXFee != 0 {
+			t.Errorf("expected no fee on the same-currency item, got %d", bill.LineItems[0].FXFee)
+		}
+		if bill.LineItems[1].FXFee != 74 {
+			t.Errorf("expected a 2%% fee of 74 on 3700, got %d", bill.LineItems[1].FXFee)
+		}
+		if bill.FXFees != 74 || bill.TotalAmount != 1000+3700+74 {
+			t.Errorf("expected FX fees 74 and total 4774, got %d and %d", bill.FXFees, bill.TotalAmount)
+		}
+
+		breakdown := Breakdown(bill)
+		if breakdown.FXFees != 74 || breakdown.Subtotal != 4700 || breakdown.GrandTotal != bill.TotalAmount {
+			t.Errorf("expected breakdown fees 74, subtotal 4700 and grand total %d, got %+v", bill.TotalAmount, breakdown)
+		}
+
+		recalculated, _, _ := svc.RecalculateTotal(ctx, bill.ID)
+		if recalculated.TotalAmount != bill.TotalAmount || recalculated.FXFees != 74 {
+			t.Errorf("expected recalculation to keep the frozen fee, got total %d fees %d", recalculated.TotalAmount, recalculated.FXFees)
+		}
+	})
+
+	t.Run("negative markup is rejected", func(t *testing.T) {
+		if _, err := NewBillingService(newMockBillRepository(), WithFXMarkup(-0.01)); err == nil {
+			t.Error("expected an error for a negative markup")
+		}
+	})
+}
+
+func TestRateStaleness(t *testing.T) {
+	stale := time.Now().UTC().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name       string
+		gelAsOf    time.Time
+		policy     StaleRatePolicy
+		wantErr    bool
+		wantAsOf   time.Time
+		wantAmount int64
+	}{
+		{name: "fresh quote", gelAsOf: time.Now().UTC(), policy: StaleRateReject, wantAmount: 3700},
+		{name: "stale quote falls back to last known rate", gelAsOf: stale, policy: StaleRateFallback, wantAsOf: stale, wantAmount: 3700},
+		{name: "stale quote rejected", gelAsOf: stale, policy: StaleRateReject, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext()
+			rates := NewStaticRateProvider(exchangeRatesToUSD)
+			rates.SetQuote(model.CurrencyGEL, 0.37, tt.gelAsOf)
+			svc := newTestBillingService(t, newMockBillRepository(), WithRateProvider(rates), WithMaxRateAge(time.Hour, tt.policy))
+
+			bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			bill, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddLineItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			item := bill.LineItems[0]
+			if item.ConvertedAmount != tt.wantAmount {
+				t.Errorf("expected converted amount %d, got %d", tt.wantAmount, item.ConvertedAmount)
+			}
+			if item.RateAsOf == nil {
+				t.Fatal("expected RateAsOf to be recorded")
+			}
+			if !tt.wantAsOf.IsZero() && !item.RateAsOf.Equal(tt.wantAsOf) {
+				t.Errorf("expected RateAsOf %v, got %v", tt.wantAsOf, *item.RateAsOf)
+			}
+		})
+	}
+}
+
+func TestBillsAreScopedToOrg(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+	bill, err := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if bill.OrgID != testOrgID {
+		t.Errorf("expected bill org %q, got %q", testOrgID, bill.OrgID)
+	}
+
+	otherOrg := tenant.WithOrgID(context.Background(), "org_other")
+	if _, err := svc.GetBill(otherOrg, bill.ID, false); err == nil {
+		t.Error("expected another org's GetBill to fail")
+	}
+	if _, err := svc.AddLineItem(otherOrg, bill.ID, &model.AddLineItemRequest{Amount: 10, Currency: model.CurrencyUSD, Description: "x"}); err == nil {
+		t.Error("expected another org's AddLineItem to fail")
+	}
+	bills, _, err := svc.ListBills(otherOrg, &model.ListBillsRequest{})
+	if err != nil {
+		t.Fatalf("ListBills() error = %v", err)
+	}
+	if len(bills) != 0 {
+		t.Errorf("expected another org to see no bills, got %d", len(bills))
+	}
+
+	if _, err := svc.GetBill(context.Background(), bill.ID, false); err == nil {
+		t.Error("expected GetBill without an org to fail")
+	}
+}
+
+func TestCreateBillWithExplicitPeriod(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	bill, err := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, PeriodStart: &start, PeriodEnd: &end})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	got, err := svc.GetBill(ctx, bill.ID, false)
+	if err != nil {
+		t.Fatalf("GetBill() error = %v", err)
+	}
+	if got.PeriodStart == nil || !got.PeriodStart.Equal(start) || got.PeriodEnd == nil || !got.PeriodEnd.Equal(end) {
+		t.Errorf("expected period %s..%s, got %v..%v", start, end, got.PeriodStart, got.PeriodEnd)
+	}
+
+	invalid := []struct {
+		name string
+		req  model.CreateBillRequest
+	}{
+		{"end before start", model.CreateBillRequest{PeriodStart: &end, PeriodEnd: &start}},
+		{"empty period", model.CreateBillRequest{PeriodStart: &start, PeriodEnd: &start}},
+		{"start without end", model.CreateBillRequest{PeriodStart: &start}},
+		{"draft", model.CreateBillRequest{PeriodStart: &start, PeriodEnd: &end, Draft: true}},
+	}
+	for _, tt := range invalid {
+		if _, err := svc.CreateBill(ctx, &tt.req); err == nil {
+			t.Errorf("%s: expected CreateBill to reject the period", tt.name)
+		}
+		if err := tt.req.Validate(); err == nil {
+			t.Errorf("%s: expected Validate to reject the period", tt.name)
+		}
+	}
+}
+
+func TestMaxBillingPeriodDays(t *testing.T) {
+	ctx := testContext()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(newFakeClock(now)), WithMaxBillingPeriodDays(90))
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	longEnd := start.AddDate(0, 0, 91)
+	maxEnd := start.AddDate(0, 0, 90)
+
+	rejected := []struct {
+		name string
+		req  model.CreateBillRequest
+	}{
+		{"negative days", model.CreateBillRequest{BillingPeriodDays: -1}},
+		{"days over the max", model.CreateBillRequest{BillingPeriodDays: 91}},
+		{"explicit period over the max", model.CreateBillRequest{PeriodStart: &start, PeriodEnd: &longEnd}},
+	}
+	for _, tt := range rejected {
+		if _, err := svc.CreateBill(ctx, &tt.req); billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+			t.Errorf("%s: expected invalid_argument, got %v", tt.name, err)
+		}
+	}
+
+	bill, err := svc.CreateBill(ctx, &model.CreateBillRequest{BillingPeriodDays: 90})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if want := now.AddDate(0, 0, 90); bill.PeriodEnd == nil || !bill.PeriodEnd.Equal(want) {
+		t.Errorf("expected the period to end at %s, got %v", want, bill.PeriodEnd)
+	}
+	if _, err := svc.CreateBill(ctx, &model.CreateBillRequest{PeriodStart: &start, PeriodEnd: &maxEnd}); err != nil {
+		t.Errorf("expected an explicit period of the max to be accepted, got %v", err)
+	}
+	// Zero still means the default period
+	if bill, err := svc.CreateBill(ctx, &model.CreateBillRequest{}); err != nil || !bill.PeriodEnd.Equal(now.AddDate(0, 0, defaultBillingPeriodDays)) {
+		t.Errorf("expected the default period for zero days, got %v, %v", bill, err)
+	}
+
+	draft, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Draft: true})
+	if _, err := svc.ActivateBill(ctx, draft.ID, &model.ActivateBillRequest{BillingPeriodDays: 91}); billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+		t.Errorf("expected activating with a period over the max to fail with invalid_argument, got %v", err)
+	}
+	if stored, _ := svc.GetBill(ctx, draft.ID, false); stored.Status != model.BillStatusDraft {
+		t.Errorf("expected the draft left as it was, got %s", stored.Status)
+	}
+
+	if _, err := NewBillingService(newMockBillRepository(), WithMaxBillingPeriodDays(0)); err == nil {
+		t.Error("expected a max billing period of zero days to be refused")
+	}
+}
+
+func TestCreateBillTimezone(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(newFakeClock(time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC))))
+
+	tests := []struct {
+		name     string
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{name: "defaults to UTC", timezone: "", want: "UTC"},
+		{name: "IANA zone", timezone: "Asia/Tbilisi", want: "Asia/Tbilisi"},
+		{name: "unknown zone", timezone: "Mars/Olympus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bill, err := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Timezone: tt.timezone})
+			if tt.wantErr {
+				if billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+					t.Errorf("expected an invalid argument error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateBill() error = %v", err)
+			}
+			if bill.Timezone != tt.want {
+				t.Errorf("expected timezone %s, got %s", tt.want, bill.Timezone)
+			}
+			stored, _ := svc.GetBill(ctx, bill.ID, false)
+			if stored.CreatedAt.Location() != time.UTC {
+				t.Errorf("expected CreatedAt stored in UTC, got %s", stored.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestProration(t *testing.T) {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		t := start.AddDate(0, 0, days)
+		return &t
+	}
+
+	tests := []struct {
+		name          string
+		elapsed       time.Duration // how far into the period the item is added
+		effectiveDate *time.Time
+		draft         bool
+		wantAmount    int64
+		wantErr       bool
+	}{
+		{name: "full period", effectiveDate: at(0), wantAmount: 3000},
+		{name: "mid-period", effectiveDate: at(15), wantAmount: 1500},
+		{name: "defaults to now", elapsed: 10 * 24 * time.Hour, wantAmount: 2000},
+		{name: "partial day counts as a whole day", elapsed: 29*24*time.Hour + 12*time.Hour, wantAmount: 100},
+		{name: "effective before period charges in full", effectiveDate: at(-5), wantAmount: 3000},
+		{name: "effective after period", effectiveDate: at(31), wantErr: true},
+		{name: "draft has no period", draft: true, effectiveDate: at(0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock(start)
+			svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock))
+			bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD, BillingPeriodDays: 30, Draft: tt.draft})
+			clock.Advance(tt.elapsed)
+
+			bill, err := svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{
+				Description:   "Subscription",
+				Amount:        30.00,
+				Currency:      model.CurrencyUSD,
+				EffectiveDate: tt.effectiveDate,
+				Proratable:    true,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddLineItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			item := bill.LineItems[0]
+			if item.Amount != tt.wantAmount {
+				t.Errorf("expected prorated amount %d, got %d", tt.wantAmount, item.Amount)
+			}
+			if item.FullAmount == nil || *item.FullAmount != 3000 {
+				t.Errorf("expected full amount 3000, got %v", item.FullAmount)
+			}
+			if bill.TotalAmount != tt.wantAmount {
+				t.Errorf("expected total %d, got %d", tt.wantAmount, bill.TotalAmount)
+			}
+		})
+	}
+}
+
+func TestCloseBills(t *testing.T) {
+	topic := events.NewTopic()
+	var mu sync.Mutex
+	var closedEvents []string
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		if event.Type == events.EventBillClosed {
+			mu.Lock()
+			closedEvents = append(closedEvents, event.BillID)
+			mu.Unlock()
+		}
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(topic))
+
+	open, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(testContext(), open.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10, Currency: model.CurrencyUSD})
+	closed, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.CloseBill(testContext(), closed.ID, allowEmptyClose)
+	empty, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+	mu.Lock()
+	closedEvents = nil
+	mu.Unlock()
+
+	results, err := svc.CloseBills(testContext(), []string{open.ID, closed.ID, "nonexistent", empty.ID}, nil)
+	if err != nil {
+		t.Fatalf("CloseBills() error = %v", err)
+	}
+
+	want := []model.CloseOutcome{
+		model.CloseOutcomeClosed,
+		model.CloseOutcomeAlreadyClosed,
+		model.CloseOutcomeNotFound,
+		model.CloseOutcomeFailed,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for i, result := range results {
+		if result.Outcome != want[i] {
+			t.Errorf("result %d (%s): expected outcome %q, got %q", i, result.BillID, want[i], result.Outcome)
+		}
+	}
+	if results[0].Bill == nil || results[0].Bill.Status != model.BillStatusClosed {
+		t.Errorf("expected the closed bill in the result, got %+v", results[0].Bill)
+	}
+	if results[3].Error == "" {
+		t.Error("expected an error message for the failed close")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(closedEvents) != 1 || closedEvents[0] != open.ID {
+		t.Errorf("expected one closed event for %s, got %v", open.ID, closedEvents)
+	}
+}
+
+func TestChangeCurrency(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	bill, err := svc.ChangeCurrency(ctx, bill.ID, model.CurrencyGEL)
+	if err != nil {
+		t.Fatalf("ChangeCurrency() error = %v", err)
+	}
+	if bill.Currency != model.CurrencyGEL {
+		t.Errorf("expected currency GEL, got %s", bill.Currency)
+	}
+
+	// Items added afterwards are converted into the new currency
+	bill, _ = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 37.00, Currency: model.CurrencyUSD})
+	if bill.TotalAmount != 10000 {
+		t.Errorf("expected total 10000 GEL cents, got %d", bill.TotalAmount)
+	}
+
+	if _, err := svc.ChangeCurrency(ctx, bill.ID, model.CurrencyUSD); err == nil {
+		t.Error("expected error changing the currency of a bill with line items")
+	}
+	bill, _ = svc.GetBill(ctx, bill.ID, false)
+	if bill.Currency != model.CurrencyGEL {
+		t.Errorf("expected rejected change to leave currency GEL, got %s", bill.Currency)
+	}
+
+	empty, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if _, err := svc.ChangeCurrency(ctx, empty.ID, "EUR"); err == nil {
+		t.Error("expected error for unsupported currency")
+	}
+	svc.CloseBill(ctx, empty.ID, allowEmptyClose)
+	if _, err := svc.ChangeCurrency(ctx, empty.ID, model.CurrencyGEL); err == nil {
+		t.Error("expected error changing the currency of a closed bill")
+	}
+}
+
+// capturingLogger records log entries for assertions
+type capturingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+type logEntry struct {
+	level   string
+	msg     string
+	keyvals []any
+}
+
+func (l *capturingLogger) log(level, msg string, keyvals []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, keyvals: keyvals})
+}
+
+func (l *capturingLogger) Info(msg string, keyvals ...any)  { l.log("info", msg, keyvals) }
+func (l *capturingLogger) Warn(msg string, keyvals ...any)  { l.log("warn", msg, keyvals) }
+func (l *capturingLogger) Error(msg string, keyvals ...any) { l.log("error", msg, keyvals) }
+
+// find returns the first entry with the message, and the value logged under key
+func (l *capturingLogger) find(msg, key string) (logEntry, any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range l.entries {
+		if entry.msg != msg {
+			continue
+		}
+		for i := 0; i+1 < len(entry.keyvals); i += 2 {
+			if entry.keyvals[i] == key {
+				return entry, entry.keyvals[i+1], true
+			}
+		}
+		return entry, nil, true
+	}
+	return logEntry{}, nil, false
+}
+
+func TestServiceLogsBillLifecycle(t *testing.T) {
+	logger := &capturingLogger{}
+	svc := newTestBillingService(t, newMockBillRepository(), WithLogger(logger))
+
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	entry, billID, ok := logger.find("bill created", "bill_id")
+	if !ok {
+		t.Fatal("expected a \"bill created\" log entry")
+	}
+	if entry.level != "info" || billID != bill.ID {
+		t.Errorf("expected info entry with bill_id %s, got %s entry with %v", bill.ID, entry.level, billID)
+	}
+
+	svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10, Currency: model.CurrencyUSD})
+	if _, amount, ok := logger.find("line item added", "amount"); !ok || amount != int64(1000) {
+		t.Errorf("expected \"line item added\" with amount 1000, got %v", amount)
+	}
+
+	svc.CloseBill(testContext(), "nonexistent", nil)
+	if entry, _, ok := logger.find("close bill failed", "error"); !ok || entry.level != "warn" {
+		t.Errorf("expected a warn entry for the failed close, got %+v", entry)
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import "fees-api/internal/model"
+
+// Breakdown computes a bill's financial breakdown from its frozen line item amounts.
+// It's shared by every rendering of a bill (JSON, CSV, PDF) so they agree.
+//
+// Bills don't carry discounts or taxes yet, so both totals are zero and the
+// grand total equals the subtotal; they belong here once they're modelled.
+func Breakdown(bill *model.Bill) *model.BillBreakdown {
+	breakdown := &model.BillBreakdown{
+		Currency: bill.Currency,
+		Lines:    []model.BreakdownLine{},
+		Credits:  []model.BreakdownLine{},
+	}
+
+	for _, item := range bill.LineItems {
+		line := model.BreakdownLine{
+			LineItemID:      item.ID,
+			Description:     item.Description,
+			Category:        item.Category,
+			Amount:          item.Amount,
+			Currency:        item.Currency,
+			ConvertedAmount: item.ConvertedAmount,
+		}
+		if item.Type == model.LineItemTypeCredit {
+			breakdown.Credits = append(breakdown.Credits, line)
+			breakdown.CreditTotal += item.ConvertedAmount
+			continue
+		}
+		breakdown.Lines = append(breakdown.Lines, line)
+		breakdown.Subtotal += item.ConvertedAmount
+		breakdown.FXFees += item.FXFee
+	}
+
+	breakdown.GrandTotal = breakdown.Subtotal - breakdown.CreditTotal + breakdown.FXFees - breakdown.DiscountTotal + breakdown.TaxTotal
+	return breakdown
+}
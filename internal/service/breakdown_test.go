@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"fees-api/internal/model"
+)
+
+func TestBreakdown(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.01, Currency: model.CurrencyUSD})
+	bill, _ = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 0.99, Currency: model.CurrencyGEL})
+
+	breakdown := Breakdown(bill)
+
+	if len(breakdown.Lines) != 2 {
+		t.Fatalf("expected 2 breakdown lines, got %d", len(breakdown.Lines))
+	}
+	if breakdown.Lines[1].Amount != 99 || breakdown.Lines[1].ConvertedAmount != bill.LineItems[1].ConvertedAmount {
+		t.Errorf("unexpected GEL line %+v", breakdown.Lines[1])
+	}
+
+	var lineSum int64
+	for _, line := range breakdown.Lines {
+		lineSum += line.ConvertedAmount
+	}
+	if breakdown.Subtotal != lineSum {
+		t.Errorf("expected subtotal %d to equal the sum of lines %d", breakdown.Subtotal, lineSum)
+	}
+	if breakdown.Subtotal-breakdown.CreditTotal+breakdown.FXFees-breakdown.DiscountTotal+breakdown.TaxTotal != breakdown.GrandTotal {
+		t.Errorf("subtotal %d - credits %d + fx fees %d - discount %d + tax %d != grand total %d",
+			breakdown.Subtotal, breakdown.CreditTotal, breakdown.FXFees, breakdown.DiscountTotal, breakdown.TaxTotal, breakdown.GrandTotal)
+	}
+	if breakdown.GrandTotal != bill.TotalAmount {
+		t.Errorf("expected grand total to match bill total %d, got %d", bill.TotalAmount, breakdown.GrandTotal)
+	}
+}
+
+func TestBreakdownListsCreditsSeparately(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10, Currency: model.CurrencyUSD})
+	bill, _ = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{
+		Description: "Goodwill", Amount: 2.50, Currency: model.CurrencyUSD, Type: model.LineItemTypeCredit,
+	})
+
+	breakdown := Breakdown(bill)
+
+	if len(breakdown.Lines) != 1 || len(breakdown.Credits) != 1 {
+		t.Fatalf("expected 1 charge and 1 credit, got %d and %d", len(breakdown.Lines), len(breakdown.Credits))
+	}
+	if breakdown.Subtotal != 1000 || breakdown.CreditTotal != 250 || breakdown.GrandTotal != 750 {
+		t.Errorf("expected subtotal 1000, credits 250, grand total 750, got %d, %d, %d",
+			breakdown.Subtotal, breakdown.CreditTotal, breakdown.GrandTotal)
+	}
+	if breakdown.GrandTotal != bill.TotalAmount {
+		t.Errorf("expected grand total to match bill total %d, got %d", bill.TotalAmount, breakdown.GrandTotal)
+	}
+}
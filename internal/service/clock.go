@@ -0,0 +1,16 @@
+package service
+
+import "time"
+
+// Clock tells the service the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the system clock, in UTC
+type SystemClock struct{}
+
+// Now returns the current UTC time
+func (SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
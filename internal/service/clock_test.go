@@ -0,0 +1,64 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+)
+
+// fakeClock is a Clock whose time only moves when the test advances it
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestServiceUsesClock(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock))
+
+	bill, err := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if !bill.CreatedAt.Equal(start) {
+		t.Errorf("expected CreatedAt %v, got %v", start, bill.CreatedAt)
+	}
+
+	clock.Advance(time.Hour)
+	bill, err = svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10, Currency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+	if want := start.Add(time.Hour); !bill.LineItems[0].CreatedAt.Equal(want) {
+		t.Errorf("expected line item CreatedAt %v, got %v", want, bill.LineItems[0].CreatedAt)
+	}
+
+	clock.Advance(30 * 24 * time.Hour)
+	bill, err = svc.CloseBill(testContext(), bill.ID, nil)
+	if err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+	want := start.Add(time.Hour + 30*24*time.Hour)
+	if bill.ClosedAt == nil || !bill.ClosedAt.Equal(want) {
+		t.Errorf("expected ClosedAt %v, got %v", want, bill.ClosedAt)
+	}
+}
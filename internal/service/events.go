@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+
+	"fees-api/internal/events"
+	"fees-api/internal/repository"
+)
+
+// Bounds on how many events RecentBillEvents returns
+const (
+	defaultRecentEvents = 10
+	maxRecentEvents     = 50
+)
+
+// EventService records the bill event stream and serves per-bill history
+type EventService struct {
+	store repository.EventStore
+}
+
+// NewEventService creates a new event service
+func NewEventService(store repository.EventStore) *EventService {
+	return &EventService{store: store}
+}
+
+// HandleEvent is a bill event subscriber that appends the event to the store
+func (s *EventService) HandleEvent(ctx context.Context, event events.BillEvent) error {
+	return s.store.Append(ctx, event)
+}
+
+// GetBillEvents returns a bill's events ordered by time. Only events of bills in
+// the caller's org are returned.
+func (s *EventService) GetBillEvents(ctx context.Context, billID string) ([]events.BillEvent, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	billEvents, err := s.store.ListByBill(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]events.BillEvent, 0, len(billEvents))
+	for _, event := range billEvents {
+		if event.Bill.OrgID == orgID {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+// RecentBillEvents returns a bill's last limit events, most recent first. A limit of
+// zero or less means the default of 10; limits above 50 are capped at 50.
+func (s *EventService) RecentBillEvents(ctx context.Context, billID string, limit int) ([]events.BillEvent, error) {
+	if limit <= 0 {
+		limit = defaultRecentEvents
+	}
+	if limit > maxRecentEvents {
+		limit = maxRecentEvents
+	}
+
+	billEvents, err := s.GetBillEvents(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	if len(billEvents) > limit {
+		billEvents = billEvents[len(billEvents)-limit:]
+	}
+	recent := make([]events.BillEvent, len(billEvents))
+	for i, event := range billEvents {
+		recent[len(billEvents)-1-i] = event
+	}
+	return recent, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+)
+
+func TestGetBillEvents(t *testing.T) {
+	ctx := testContext()
+	eventSvc := NewEventService(repository.NewInMemoryEventStore())
+	topic := events.NewTopic()
+	topic.Subscribe(eventSvc.HandleEvent)
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(topic))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	other, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	bill, _ = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	svc.CloseBill(ctx, bill.ID, nil)
+
+	got, err := eventSvc.GetBillEvents(ctx, bill.ID)
+	if err != nil {
+		t.Fatalf("GetBillEvents() error = %v", err)
+	}
+
+	want := []events.EventType{events.EventBillCreated, events.EventLineItemAdded, events.EventBillClosed}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(got))
+	}
+	for i, eventType := range want {
+		if got[i].Type != eventType || got[i].BillID != bill.ID {
+			t.Errorf("event %d: expected %s for %s, got %s for %s", i, eventType, bill.ID, got[i].Type, got[i].BillID)
+		}
+		if i > 0 && got[i].OccurredAt.Before(got[i-1].OccurredAt) {
+			t.Errorf("event %d occurred before event %d", i, i-1)
+		}
+	}
+	if got[1].LineItemID != bill.LineItems[0].ID {
+		t.Errorf("expected line_item_added to reference %s, got %s", bill.LineItems[0].ID, got[1].LineItemID)
+	}
+
+	otherEvents, _ := eventSvc.GetBillEvents(ctx, other.ID)
+	if len(otherEvents) != 1 {
+		t.Errorf("expected 1 event for the other bill, got %d", len(otherEvents))
+	}
+}
+
+func TestBulkAddPublishesOneBatch(t *testing.T) {
+	ctx := testContext()
+	topic := events.NewTopic()
+	var batches []events.BillEventsBatch
+	topic.SubscribeBatch(func(ctx context.Context, batch events.BillEventsBatch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	publisher := events.NewBufferedPublisher(topic, time.Hour)
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(publisher))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	for _, description := range []string{"Setup", "Usage", "Support"} {
+		bill, _ = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: description, Amount: 1.00, Currency: model.CurrencyUSD})
+	}
+	if len(batches) != 0 {
+		t.Fatalf("expected events to be held until flushed, got %d batches", len(batches))
+	}
+	if err := publisher.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if len(batches) != 1 {
+		t.Fatalf("expected 1 batched message, got %d", len(batches))
+	}
+	ids := batches[0].LineItemIDs()
+	if len(ids) != len(bill.LineItems) {
+		t.Fatalf("expected %d line item IDs in the batch, got %v", len(bill.LineItems), ids)
+	}
+	for i, item := range bill.LineItems {
+		if ids[i] != item.ID {
+			t.Errorf("batch line item %d: expected %s, got %s", i, item.ID, ids[i])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// ForceCloseBill closes a bill at once for an operator, e.g. one whose period-end
+// close keeps failing while it awaits approval. The operator on the context is
+// recorded as the close approval, and the bill closes even without line items. A
+// bill that is already closed is returned as it was, with no new event.
+func (s *BillingService) ForceCloseBill(ctx context.Context, billID string) (*model.Bill, error) {
+	actor, ok := tenant.ActorFromContext(ctx)
+	if !ok {
+		return nil, billingerrors.Unauthenticated("force close needs an operator")
+	}
+
+	var bill *model.Bill
+	alreadyClosed := false
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBill(ctx, tx, billID)
+		if err != nil {
+			return err
+		}
+
+		if bill.Status == model.BillStatusClosed {
+			alreadyClosed = true
+			return nil
+		}
+		return s.closeLoadedBill(ctx, tx, bill, &model.CloseBillRequest{AllowEmpty: true, ApprovedBy: actor})
+	})
+	if err != nil {
+		s.logger.Warn("force close bill failed", "bill_id", billID, "actor", actor, "error", err)
+		return nil, fmt.Errorf("force close bill: %w", err)
+	}
+	if alreadyClosed {
+		return bill, nil
+	}
+
+	s.logger.Warn("bill force closed", "bill_id", billID, "actor", actor, "total", bill.TotalAmount,
+		"currency", bill.Currency, "line_items", len(bill.LineItems))
+	s.metrics.ObserveBillTotal(bill.Currency, bill.TotalAmount)
+	s.issueReceipt(ctx, bill)
+	s.publish(ctx, events.NewBillEvent(events.EventBillClosed, bill))
+	s.notifyClosed(ctx, bill)
+
+	return bill, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestForceCloseBill(t *testing.T) {
+	ctx := testContext()
+	topic := events.NewTopic()
+	var closedEvents int
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		if event.Type == events.EventBillClosed {
+			closedEvents++
+		}
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithMaxAutoCloseTotal(500), WithPublisher(topic))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+
+	if _, err := svc.ForceCloseBill(ctx, bill.ID); billingerrors.CodeOf(err) != billingerrors.CodeUnauthenticated {
+		t.Fatalf("expected an unauthenticated error without an operator, got %v", err)
+	}
+
+	opsCtx := tenant.WithActor(ctx, "ops_1")
+	closed, err := svc.ForceCloseBill(opsCtx, bill.ID)
+	if err != nil {
+		t.Fatalf("ForceCloseBill() error = %v", err)
+	}
+	if closed.Status != model.BillStatusClosed || closed.CloseApproval == nil || closed.CloseApproval.ApprovedBy != "ops_1" {
+		t.Errorf("expected the bill awaiting approval closed with ops_1's approval, got %s with %+v", closed.Status, closed.CloseApproval)
+	}
+
+	again, err := svc.ForceCloseBill(opsCtx, bill.ID)
+	if err != nil {
+		t.Fatalf("ForceCloseBill() on a closed bill error = %v", err)
+	}
+	if again.InvoiceNumber != closed.InvoiceNumber {
+		t.Errorf("expected the closed bill returned as it was, got invoice %q, want %q", again.InvoiceNumber, closed.InvoiceNumber)
+	}
+	if closedEvents != 1 {
+		t.Errorf("expected one closed event, got %d", closedEvents)
+	}
+}
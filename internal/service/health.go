@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// healthCheckTimeout bounds how long a dependency check may take
+const healthCheckTimeout = 2 * time.Second
+
+// CheckHealth verifies the service's dependencies are reachable. It returns an
+// Unavailable error alongside the report if any dependency is down.
+func (s *BillingService) CheckHealth(ctx context.Context) (*model.HealthResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	resp := &model.HealthResponse{
+		Status:       model.HealthStatusOK,
+		Dependencies: map[string]string{"repository": string(model.HealthStatusOK)},
+	}
+
+	if err := s.repo.Ping(ctx); err != nil {
+		resp.Status = model.HealthStatusUnavailable
+		resp.Dependencies["repository"] = err.Error()
+		return resp, billingerrors.Unavailable("repository unreachable: %v", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// unreachableRepository is a repository stub whose datastore can't be reached
+type unreachableRepository struct {
+	*mockBillRepository
+}
+
+func (r unreachableRepository) Ping(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestCheckHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		svc        *BillingService
+		wantStatus model.HealthStatus
+		wantCode   billingerrors.Code
+	}{
+		{
+			name:       "healthy repository",
+			svc:        newTestBillingService(t, newMockBillRepository()),
+			wantStatus: model.HealthStatusOK,
+		},
+		{
+			name:       "unreachable repository",
+			svc:        newTestBillingService(t, unreachableRepository{newMockBillRepository()}),
+			wantStatus: model.HealthStatusUnavailable,
+			wantCode:   billingerrors.CodeUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.svc.CheckHealth(context.Background())
+
+			if tt.wantCode == "" && err != nil {
+				t.Fatalf("CheckHealth() unexpected error = %v", err)
+			}
+			if tt.wantCode != "" && billingerrors.CodeOf(err) != tt.wantCode {
+				t.Errorf("expected code %v, got %v", tt.wantCode, billingerrors.CodeOf(err))
+			}
+			if resp.Status != tt.wantStatus {
+				t.Errorf("expected status %v, got %v", tt.wantStatus, resp.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// IDGenerator issues IDs for new bills and line items
+type IDGenerator interface {
+	NewBillID() string
+	NewLineItemID() string
+}
+
+// TimestampIDGenerator issues IDs from the clock's time in nanoseconds, bumped
+// when needed so concurrent callers never collide
+type TimestampIDGenerator struct {
+	Clock Clock
+	last  atomic.Int64
+}
+
+// NewTimestampIDGenerator creates a generator reading time from clock
+func NewTimestampIDGenerator(clock Clock) *TimestampIDGenerator {
+	return &TimestampIDGenerator{Clock: clock}
+}
+
+// NewBillID returns a unique bill ID
+func (g *TimestampIDGenerator) NewBillID() string {
+	return fmt.Sprintf("bill_%d", g.next(g.Clock.Now()))
+}
+
+// NewLineItemID returns a unique line item ID
+func (g *TimestampIDGenerator) NewLineItemID() string {
+	return fmt.Sprintf("bill_%d", g.next(g.Clock.Now()))
+}
+
+func (g *TimestampIDGenerator) next(now time.Time) int64 {
+	for {
+		last := g.last.Load()
+		next := now.UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+		if g.last.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
+
+// SequentialIDGenerator issues predictable, counter-based IDs (bill_1, item_1, ...),
+// for tests that assert on IDs
+type SequentialIDGenerator struct {
+	bills atomic.Int64
+	items atomic.Int64
+}
+
+// NewBillID returns the next bill ID in sequence
+func (g *SequentialIDGenerator) NewBillID() string {
+	return fmt.Sprintf("bill_%d", g.bills.Add(1))
+}
+
+// NewLineItemID returns the next line item ID in sequence
+func (g *SequentialIDGenerator) NewLineItemID() string {
+	return fmt.Sprintf("item_%d", g.items.Add(1))
+}
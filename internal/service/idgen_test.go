@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+)
+
+func TestServiceUsesIDGenerator(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository(), WithIDGenerator(&SequentialIDGenerator{}))
+	ctx := testContext()
+
+	first, err := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	second, err := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if first.ID != "bill_1" || second.ID != "bill_2" {
+		t.Errorf("expected bill IDs bill_1 and bill_2, got %s and %s", first.ID, second.ID)
+	}
+
+	if _, err := svc.AddLineItem(ctx, first.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10, Currency: model.CurrencyUSD}); err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+	bill, err := svc.ReplaceLineItems(ctx, second.ID, []model.AddLineItemRequest{
+		{Description: "Setup", Amount: 5, Currency: model.CurrencyUSD},
+		{Description: "Support", Amount: 7, Currency: model.CurrencyUSD},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceLineItems() error = %v", err)
+	}
+	if got := []string{bill.LineItems[0].ID, bill.LineItems[1].ID}; got[0] != "item_2" || got[1] != "item_3" {
+		t.Errorf("expected line item IDs item_2 and item_3, got %v", got)
+	}
+}
+
+func TestTimestampIDGeneratorNeverRepeats(t *testing.T) {
+	ids := NewTimestampIDGenerator(newFakeClock(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := ids.NewBillID()
+		if i%2 == 1 {
+			id = ids.NewLineItemID()
+		}
+		if seen[id] {
+			t.Fatalf("ID %s issued twice", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// maxLineItemImportRows caps how many rows a single line item CSV import may hold
+const maxLineItemImportRows = 10000
+
+// lineItemCSVColumns are the columns a line item CSV must have, in any order
+var lineItemCSVColumns = []string{"billID", "description", "amount", "currency"}
+
+// ImportBill stores a historical bill as it stands, already closed, for migrating
+// invoices from another system. Line items are converted at their historical rate
+// (or the current one if none is given) and must add up to the stated total. The
+// bill gets an imported event rather than the live lifecycle events.
+func (s *BillingService) ImportBill(ctx context.Context, req *model.ImportBillRequest) (*model.Bill, error) {
+	if req.OrgID == "" {
+		return nil, fmt.Errorf("orgId is required")
+	}
+	if !req.Currency.IsSupported() {
+		return nil, billingerrors.UnsupportedCurrency(string(req.Currency))
+	}
+	if req.Status != "" && req.Status != model.BillStatusClosed {
+		return nil, fmt.Errorf("only closed bills can be imported, got %s", req.Status)
+	}
+	if req.CreatedAt.IsZero() || req.ClosedAt == nil || req.ClosedAt.Before(req.CreatedAt) {
+		return nil, fmt.Errorf("createdAt and a closedAt no earlier than it are required")
+	}
+	if err := validateNote(req.Note); err != nil {
+		return nil, err
+	}
+	if len(req.LineItems) > s.maxLineItems {
+		return nil, fmt.Errorf("an imported bill cannot hold more than %d line items", s.maxLineItems)
+	}
+
+	lineItems := make([]model.LineItem, len(req.LineItems))
+	var total int64
+	for i := range req.LineItems {
+		imported := &req.LineItems[i]
+		createdAt := req.CreatedAt
+		if imported.CreatedAt != nil {
+			createdAt = *imported.CreatedAt
+		}
+		item, err := newLineItem(&imported.AddLineItemRequest, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("line item %d: %w", i, err)
+		}
+		item.ID = s.ids.NewLineItemID()
+		item.Sequence = i + 1
+		if existing := findRef(lineItems[:i], item.Ref); existing != nil {
+			return nil, fmt.Errorf("line item %d: ref %q is already used by line item %s", i, item.Ref, existing.ID)
+		}
+
+		if item.Currency == "" {
+			item.Currency = req.Currency
+		}
+		rate, asOf := imported.AppliedRate, createdAt
+		if item.Currency == req.Currency {
+			rate = 1.0
+		} else if rate == 0 {
+			quote, err := s.quote(item.Currency, req.Currency)
+			if err != nil {
+				return nil, fmt.Errorf("line item %d: %w", i, err)
+			}
+			rate, asOf = quote.Rate, quote.AsOf
+		}
+		item.AppliedRate = rate
+		item.RateAsOf = &asOf
+		item.ConvertedAmount = applyRate(item.Amount, rate)
+		total += item.NetAmount()
+		lineItems[i] = item
+	}
+	if stated := model.ToCents(req.Total); stated != total {
+		return nil, billingerrors.ImportTotalMismatch(stated, total)
+	}
+
+	closedAt := *req.ClosedAt
+	finalLineItemCount := len(lineItems)
+	bill := &model.Bill{
+		ID:                 s.ids.NewBillID(),
+		OrgID:              req.OrgID,
+		Status:             model.BillStatusClosed,
+		Currency:           req.Currency,
+		TotalAmount:        total,
+		LineItems:          lineItems,
+		Note:               req.Note,
+		CreatedAt:          req.CreatedAt,
+		ClosedAt:           &closedAt,
+		FinalTotal:         &total,
+		FinalLineItemCount: &finalLineItemCount,
+		Version:            1,
+	}
+	if err := s.repo.Create(ctx, bill); err != nil {
+		s.logger.Error("import bill failed", "org_id", req.OrgID, "error", err)
+		return nil, err
+	}
+
+	actor, _ := tenant.ActorFromContext(ctx)
+	s.logger.Info("bill imported", "bill_id", bill.ID, "org_id", bill.OrgID, "actor", actor,
+		"line_items", len(lineItems), "total", total)
+	s.publish(ctx, events.NewBillEvent(events.EventBillImported, bill))
+
+	return bill, nil
+}
+
+// csvLineItem is a parsed CSV row waiting to be added to its bill
+type csvLineItem struct {
+	report int // index into the report's rows
+	req    model.AddLineItemRequest
+	item   model.LineItem
+}
+
+// ImportLineItemsCSV adds line items from a CSV of external charges with billID,
+// description, amount and currency columns. Each row is validated on its own, then
+// the valid rows are grouped by bill and each bill's rows are added in a single
+// transaction: a bill that can't take them (closed, not found, over its limits)
+// fails all of its rows and no others. Every row gets a result in the report.
+func (s *BillingService) ImportLineItemsCSV(ctx context.Context, r io.Reader) (*model.LineItemImportReport, error) {
+	if _, err := callerOrg(ctx); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, billingerrors.InvalidCSV("cannot read header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range lineItemCSVColumns {
+		if _, ok := columns[strings.ToLower(name)]; !ok {
+			return nil, billingerrors.InvalidCSV("missing the %s column", name)
+		}
+	}
+	reader.FieldsPerRecord = len(header)
+
+	report := &model.LineItemImportReport{}
+	fail := func(i int, err error) {
+		report.Rows[i].Error = err.Error()
+		report.Failed++
+	}
+
+	now := s.clock.Now()
+	var billIDs []string
+	byBill := make(map[string][]csvLineItem)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if len(report.Rows) == maxLineItemImportRows {
+			return nil, billingerrors.InvalidCSV("cannot import more than %d rows at once", maxLineItemImportRows)
+		}
+		report.Rows = append(report.Rows, model.LineItemImportRow{Row: line})
+		i := len(report.Rows) - 1
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+
+		field := func(name string) string {
+			return strings.TrimSpace(record[columns[strings.ToLower(name)]])
+		}
+		billID := field("billID")
+		report.Rows[i].BillID = billID
+		if billID == "" {
+			fail(i, fmt.Errorf("billID is required"))
+			continue
+		}
+		amount, err := strconv.ParseFloat(field("amount"), 64)
+		if err != nil {
+			fail(i, fmt.Errorf("invalid amount %q", field("amount")))
+			continue
+		}
+		req := model.AddLineItemRequest{
+			Description: field("description"),
+			Amount:      amount,
+			Currency:    model.Currency(field("currency")).Normalize(),
+		}
+		item, err := newLineItem(&req, now)
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+
+		if _, seen := byBill[billID]; !seen {
+			billIDs = append(billIDs, billID)
+		}
+		byBill[billID] = append(byBill[billID], csvLineItem{report: i, req: req, item: item})
+	}
+
+	for _, billID := range billIDs {
+		rows := byBill[billID]
+		bill, err := s.addCSVLineItems(ctx, billID, rows)
+		if err != nil {
+			s.logger.Warn("csv line items rejected", "bill_id", billID, "rows", len(rows), "error", err)
+			for _, row := range rows {
+				fail(row.report, err)
+			}
+			continue
+		}
+
+		for _, row := range rows {
+			report.Rows[row.report].LineItemID = row.item.ID
+			report.Imported++
+			s.metrics.IncLineItemAdded(row.item.Currency)
+
+			event := events.NewBillEvent(events.EventLineItemAdded, bill)
+			event.LineItemID = row.item.ID
+			s.publish(ctx, event)
+		}
+	}
+
+	actor, _ := tenant.ActorFromContext(ctx)
+	s.logger.Info("line items imported", "actor", actor, "imported", report.Imported, "failed", report.Failed)
+	return report, nil
+}
+
+// addCSVLineItems adds one bill's imported rows in a single transaction, assigning
+// each row's item its ID. The dedup window doesn't apply: the CSV is authoritative.
+func (s *BillingService) addCSVLineItems(ctx context.Context, billID string, rows []csvLineItem) (*model.Bill, error) {
+	var bill *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpImportLineItems)
+		if err != nil {
+			return err
+		}
+		if len(bill.LineItems)+len(rows) > s.maxLineItems {
+			return billingerrors.TooManyLineItems(billID, s.maxLineItems)
+		}
+
+		for i := range rows {
+			row := &rows[i]
+			row.item.ID = s.ids.NewLineItemID()
+			if bill.TotalAmount, err = s.convertAndAdd(bill.TotalAmount, bill.Currency, &row.item); err != nil {
+				return err
+			}
+			if err := s.checkAmountCap(&row.item, &row.req, bill.Currency); err != nil {
+				return err
+			}
+			bill.AppendLineItem(row.item)
+			bill.FXFees += row.item.FXFee
+		}
+
+		return saveBill(ctx, tx, bill)
+	})
+	return bill, err
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestImportBill(t *testing.T) {
+	ctx := testContext()
+	var published []events.BillEvent
+	topic := events.NewTopic()
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		published = append(published, event)
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(topic))
+
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	closedAt := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	req := &model.ImportBillRequest{
+		OrgID:     testOrgID,
+		Currency:  model.CurrencyUSD,
+		Status:    model.BillStatusClosed,
+		CreatedAt: createdAt,
+		ClosedAt:  &closedAt,
+		LineItems: []model.ImportLineItem{
+			{AddLineItemRequest: model.AddLineItemRequest{Description: "Setup", Amount: 20.00, Currency: model.CurrencyUSD}},
+			{AddLineItemRequest: model.AddLineItemRequest{Description: "Usage", Amount: 100.00, Currency: model.CurrencyGEL}, AppliedRate: 0.35},
+		},
+		Total: 55.00,
+	}
+
+	bill, err := svc.ImportBill(ctx, req)
+	if err != nil {
+		t.Fatalf("ImportBill() error = %v", err)
+	}
+
+	stored, err := svc.GetBill(ctx, bill.ID, false)
+	if err != nil {
+		t.Fatalf("GetBill() error = %v", err)
+	}
+	if stored.Status != model.BillStatusClosed || !stored.CreatedAt.Equal(createdAt) || !stored.ClosedAt.Equal(closedAt) {
+		t.Errorf("expected a closed bill with the imported dates, got %s %v %v", stored.Status, stored.CreatedAt, stored.ClosedAt)
+	}
+	if len(stored.LineItems) != 2 || stored.LineItems[1].AppliedRate != 0.35 || stored.LineItems[1].ConvertedAmount != 3500 {
+		t.Errorf("expected the GEL item converted at its historical rate, got %+v", stored.LineItems)
+	}
+	if stored.TotalAmount != 5500 || stored.FinalTotal == nil || *stored.FinalTotal != 5500 {
+		t.Errorf("expected total and final total 5500, got %d and %v", stored.TotalAmount, stored.FinalTotal)
+	}
+	if len(published) != 1 || published[0].Type != events.EventBillImported {
+		t.Errorf("expected only an imported event, got %+v", published)
+	}
+
+	req.Total = 60.00
+	if _, err := svc.ImportBill(ctx, req); err == nil {
+		t.Error("expected a mismatched total to be rejected")
+	}
+	// A total summed in floats by the exporting system matches to the cent
+	req.LineItems = []model.ImportLineItem{
+		{AddLineItemRequest: model.AddLineItemRequest{Description: "Card fee", Amount: 0.1, Currency: model.CurrencyUSD}},
+		{AddLineItemRequest: model.AddLineItemRequest{Description: "Card fee", Amount: 0.2, Currency: model.CurrencyUSD}},
+	}
+	req.Total = 0.1 + 0.2
+	if summed, err := svc.ImportBill(ctx, req); err != nil || summed.TotalAmount != 30 {
+		t.Errorf("expected a float-summed total of 0.30 to match, got %v, %v", summed, err)
+	}
+	req.Total = 0.31
+	if _, err := svc.ImportBill(ctx, req); err == nil {
+		t.Error("expected a total a cent off to be rejected")
+	}
+	req.Total = 0.30
+	req.Status = model.BillStatusOpen
+	if _, err := svc.ImportBill(ctx, req); err == nil {
+		t.Error("expected an open bill to be rejected")
+	}
+}
+
+func TestImportLineItemsCSV(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	first, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	second, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	closed, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, closed.ID, &model.AddLineItemRequest{Description: "Setup", Amount: 5.00, Currency: model.CurrencyUSD})
+	if _, err := svc.CloseBill(ctx, closed.ID, nil); err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+
+	csv := "billID,description,amount,currency\n" +
+		first.ID + ",Wire fee,12.50,USD\n" +
+		second.ID + ",Card fee,3.00,EUR\n" +
+		closed.ID + ",Late fee,7.00,USD\n" +
+		second.ID + ",Card fee,100.00,gel\n" +
+		first.ID + ",Courier,2.00,USD\n" +
+		first.ID + ",Refund,abc,USD\n"
+
+	report, err := svc.ImportLineItemsCSV(ctx, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ImportLineItemsCSV() error = %v", err)
+	}
+	if report.Imported != 3 || report.Failed != 3 || len(report.Rows) != 6 {
+		t.Fatalf("expected 3 imported and 3 failed of 6 rows, got %+v", report)
+	}
+
+	for i, wantOK := range []bool{true, false, false, true, true, false} {
+		row := report.Rows[i]
+		if row.Row != i+2 {
+			t.Errorf("row %d: expected record number %d, got %d", i, i+2, row.Row)
+		}
+		if ok := row.Error == "" && row.LineItemID != ""; ok != wantOK {
+			t.Errorf("row %d: expected success %v, got %+v", row.Row, wantOK, row)
+		}
+	}
+	if !strings.Contains(report.Rows[1].Error, "EUR") {
+		t.Errorf("expected the bad currency named in the error, got %q", report.Rows[1].Error)
+	}
+	if !strings.Contains(report.Rows[2].Error, "closed") {
+		t.Errorf("expected a closed bill error, got %q", report.Rows[2].Error)
+	}
+
+	stored, _ := svc.GetBill(ctx, first.ID, false)
+	if len(stored.LineItems) != 2 || stored.TotalAmount != 1450 {
+		t.Errorf("expected 2 items totalling 1450 on the first bill, got %d items totalling %d", len(stored.LineItems), stored.TotalAmount)
+	}
+	stored, _ = svc.GetBill(ctx, second.ID, false)
+	if len(stored.LineItems) != 1 || stored.LineItems[0].ID != report.Rows[3].LineItemID || stored.TotalAmount != 3700 {
+		t.Errorf("expected only the GEL item converted on the second bill, got %+v", stored.LineItems)
+	}
+	stored, _ = svc.GetBill(ctx, closed.ID, false)
+	if len(stored.LineItems) != 1 {
+		t.Errorf("expected the closed bill untouched, got %d items", len(stored.LineItems))
+	}
+}
+
+func TestImportLineItemsCSVRequiresColumns(t *testing.T) {
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	_, err := svc.ImportLineItemsCSV(testContext(), strings.NewReader("billID,description,amount\nbill_1,Fee,1.00\n"))
+	if billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+		t.Fatalf("expected invalid argument for a missing column, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+)
+
+func TestInvoiceNumbers(t *testing.T) {
+	ctx := testContext()
+	clock := newFakeClock(time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC))
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock))
+
+	closeNew := func() *model.Bill {
+		t.Helper()
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		closed, err := svc.CloseBill(ctx, bill.ID, allowEmptyClose)
+		if err != nil {
+			t.Fatalf("CloseBill() error = %v", err)
+		}
+		return closed
+	}
+
+	first := closeNew()
+	second := closeNew()
+	if first.InvoiceNumber != "INV-2024-000001" || second.InvoiceNumber != "INV-2024-000002" {
+		t.Errorf("expected INV-2024-000001 and INV-2024-000002, got %q and %q", first.InvoiceNumber, second.InvoiceNumber)
+	}
+	if receipt, _ := svc.GetReceipt(ctx, first.ID); receipt == nil || receipt.InvoiceNumber != first.InvoiceNumber {
+		t.Errorf("expected the receipt to carry %s, got %+v", first.InvoiceNumber, receipt)
+	}
+
+	// Reopening and closing again keeps the number and doesn't use up another
+	if _, err := svc.ReopenBill(ctx, first.ID); err != nil {
+		t.Fatalf("ReopenBill() error = %v", err)
+	}
+	reopened, _ := svc.GetBill(ctx, first.ID, false)
+	if reopened.InvoiceNumber != first.InvoiceNumber {
+		t.Errorf("expected the reopened bill to keep %s, got %q", first.InvoiceNumber, reopened.InvoiceNumber)
+	}
+	reclosed, err := svc.CloseBill(ctx, first.ID, allowEmptyClose)
+	if err != nil {
+		t.Fatalf("CloseBill() after reopen error = %v", err)
+	}
+	if reclosed.InvoiceNumber != first.InvoiceNumber {
+		t.Errorf("expected the re-closed bill to keep %s, got %q", first.InvoiceNumber, reclosed.InvoiceNumber)
+	}
+	if third := closeNew(); third.InvoiceNumber != "INV-2024-000003" {
+		t.Errorf("expected the next close to get INV-2024-000003, got %q", third.InvoiceNumber)
+	}
+
+	// Drafts and previews get no number
+	draft, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Draft: true})
+	if _, err := svc.CloseBill(ctx, draft.ID, allowEmptyClose); err == nil {
+		t.Fatal("expected closing a draft to fail")
+	}
+	if stored, _ := svc.GetBill(ctx, draft.ID, false); stored.InvoiceNumber != "" {
+		t.Errorf("expected a draft to have no invoice number, got %q", stored.InvoiceNumber)
+	}
+	open, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if preview, err := svc.PreviewClose(ctx, open.ID, allowEmptyClose); err != nil || preview.InvoiceNumber != "" {
+		t.Errorf("expected a preview without an invoice number, got %+v, %v", preview, err)
+	}
+
+	// The sequence starts again each year
+	clock.Advance(24 * time.Hour)
+	if next := closeNew(); next.InvoiceNumber != "INV-2025-000001" {
+		t.Errorf("expected the first close of 2025 to get INV-2025-000001, got %q", next.InvoiceNumber)
+	}
+}
+
+func TestInvoiceNumberReleasedWhenCloseFails(t *testing.T) {
+	ctx := testContext()
+	repo := newMockBillRepository()
+	invoices := repository.NewInMemoryInvoiceSequence()
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	svc := newTestBillingService(t, repo, WithClock(clock), WithInvoiceSequence(invoices))
+	errWrite := errors.New("disk full")
+	failing := newTestBillingService(t, failingWriteRepository{mockBillRepository: repo, err: errWrite},
+		WithClock(clock), WithInvoiceSequence(invoices))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if _, err := failing.CloseBill(ctx, bill.ID, allowEmptyClose); !errors.Is(err, errWrite) {
+		t.Fatalf("expected the write error, got %v", err)
+	}
+
+	closed, err := svc.CloseBill(ctx, bill.ID, allowEmptyClose)
+	if err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+	if closed.InvoiceNumber != "INV-2024-000001" {
+		t.Errorf("expected the failed close's number to be reused, got %q", closed.InvoiceNumber)
+	}
+}
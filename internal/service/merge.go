@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	"fees-api/internal/validation"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// MergeBills combines open bills of the same currency into the target: the sources'
+// line items are appended to it in order, its total is recomputed from their frozen
+// conversions, and the sources are soft-deleted, all in one transaction. The target
+// gets a merged event naming the sources, and each source one naming the target.
+func (s *BillingService) MergeBills(ctx context.Context, targetID string, sourceIDs []string) (*model.Bill, error) {
+	if err := (model.MergeBillsRequest{SourceBillIDs: sourceIDs}).Validate(); err != nil {
+		return nil, err
+	}
+	for i, id := range sourceIDs {
+		if id == targetID {
+			return nil, billingerrors.InvalidArgument([]billingerrors.FieldViolation{{
+				Field:       validation.Index("sourceBillIds", i),
+				Description: "cannot be the target bill",
+			}})
+		}
+	}
+
+	var target *model.Bill
+	sources := make([]*model.Bill, len(sourceIDs))
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		if target, err = loadBillFor(ctx, tx, targetID, OpMerge); err != nil {
+			return err
+		}
+
+		now := s.clock.Now()
+		for i, id := range sourceIDs {
+			source, err := loadBillFor(ctx, tx, id, OpMerge)
+			if err != nil {
+				return err
+			}
+			if source.Currency != target.Currency {
+				return billingerrors.MergeCurrencyMismatch(target.ID, string(target.Currency), source.ID, string(source.Currency))
+			}
+			if len(target.LineItems)+len(source.LineItems) > s.maxLineItems {
+				return billingerrors.TooManyLineItems(target.ID, s.maxLineItems)
+			}
+			for _, item := range source.LineItems {
+				if existing := findRef(target.LineItems, item.Ref); existing != nil {
+					return billingerrors.DuplicateLineItemRef(target.ID, item.Ref, existing.ID)
+				}
+				target.AppendLineItem(item)
+			}
+
+			source.LineItems = []model.LineItem{}
+			source.TotalAmount = 0
+			source.FXFees = 0
+			source.DeletedAt = &now
+			if err := saveBill(ctx, tx, source); err != nil {
+				return err
+			}
+			sources[i] = source
+		}
+
+		if target.TotalAmount, err = s.sumLineItems(target); err != nil {
+			return err
+		}
+		target.FXFees = sumFXFees(target.LineItems)
+		if s.noNegativeTotal && target.TotalAmount < 0 {
+			return billingerrors.CreditExceedsTotal(target.ID)
+		}
+		return saveBill(ctx, tx, target)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("merge bills: %w", err)
+	}
+
+	s.logger.Info("bills merged", "bill_id", target.ID, "source_bill_ids", sourceIDs, "total", target.TotalAmount,
+		"line_items", len(target.LineItems))
+	s.publishRelated(ctx, events.EventBillsMerged, target, append([]string{}, sourceIDs...)...)
+	for _, source := range sources {
+		s.publishRelated(ctx, events.EventBillsMerged, source, target.ID)
+	}
+
+	return target, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestMergeBills(t *testing.T) {
+	ctx := testContext()
+	topic := events.NewTopic()
+	var published []events.BillEvent
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		published = append(published, event)
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(topic))
+
+	target, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, target.ID, &model.AddLineItemRequest{Description: "Base", Amount: 10.00, Currency: model.CurrencyUSD})
+	first, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, first.ID, &model.AddLineItemRequest{Description: "Usage", Amount: 5.50, Currency: model.CurrencyUSD})
+	second, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, second.ID, &model.AddLineItemRequest{Description: "Overage", Amount: 2.25, Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, second.ID, &model.AddLineItemRequest{Description: "Goodwill", Amount: 1.00, Currency: model.CurrencyUSD, Type: model.LineItemTypeCredit})
+	published = nil
+
+	merged, err := svc.MergeBills(ctx, target.ID, []string{first.ID, second.ID})
+	if err != nil {
+		t.Fatalf("MergeBills() error = %v", err)
+	}
+
+	var descriptions []string
+	var sequences []int
+	for _, item := range merged.LineItems {
+		descriptions = append(descriptions, item.Description)
+		sequences = append(sequences, item.Sequence)
+	}
+	if want := []string{"Base", "Usage", "Overage", "Goodwill"}; !reflect.DeepEqual(descriptions, want) {
+		t.Errorf("expected line items %v, got %v", want, descriptions)
+	}
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(sequences, want) {
+		t.Errorf("expected sequences %v, got %v", want, sequences)
+	}
+	if merged.TotalAmount != 1000+550+225-100 {
+		t.Errorf("expected merged total 1675, got %d", merged.TotalAmount)
+	}
+
+	for _, id := range []string{first.ID, second.ID} {
+		if _, err := svc.GetBill(ctx, id, false); billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+			t.Errorf("expected source %s to be deleted, got %v", id, err)
+		}
+		source, _ := svc.GetBill(ctx, id, true)
+		if len(source.LineItems) != 0 || source.TotalAmount != 0 {
+			t.Errorf("expected source %s to be emptied, got %d items at %d", id, len(source.LineItems), source.TotalAmount)
+		}
+	}
+
+	wantEvents := []struct {
+		billID  string
+		related []string
+	}{
+		{target.ID, []string{first.ID, second.ID}},
+		{first.ID, []string{target.ID}},
+		{second.ID, []string{target.ID}},
+	}
+	if len(published) != len(wantEvents) {
+		t.Fatalf("expected %d merged events, got %d", len(wantEvents), len(published))
+	}
+	for i, want := range wantEvents {
+		event := published[i]
+		if event.Type != events.EventBillsMerged || event.BillID != want.billID || !reflect.DeepEqual(event.RelatedBillIDs, want.related) {
+			t.Errorf("event %d: expected merged on %s related to %v, got %s on %s related to %v",
+				i, want.billID, want.related, event.Type, event.BillID, event.RelatedBillIDs)
+		}
+	}
+}
+
+func TestMergeBillsRejects(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	target, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, target.ID, &model.AddLineItemRequest{Description: "Base", Amount: 10.00, Currency: model.CurrencyUSD, Ref: "ext_1"})
+	gel, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyGEL})
+	svc.AddLineItem(ctx, gel.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyGEL})
+	sameRef, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, sameRef.ID, &model.AddLineItemRequest{Description: "Base", Amount: 10.00, Currency: model.CurrencyUSD, Ref: "ext_1"})
+	closed, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.CloseBill(ctx, closed.ID, allowEmptyClose)
+
+	tests := []struct {
+		name      string
+		targetID  string
+		sourceIDs []string
+		wantCode  billingerrors.Code
+	}{
+		{name: "currency mismatch", targetID: target.ID, sourceIDs: []string{gel.ID}, wantCode: billingerrors.CodeConflict},
+		{name: "duplicate ref", targetID: target.ID, sourceIDs: []string{sameRef.ID}, wantCode: billingerrors.CodeConflict},
+		{name: "no sources", targetID: target.ID, sourceIDs: nil, wantCode: billingerrors.CodeInvalidArgument},
+		{name: "target as a source", targetID: target.ID, sourceIDs: []string{target.ID}, wantCode: billingerrors.CodeInvalidArgument},
+		{name: "unknown source", targetID: target.ID, sourceIDs: []string{"bill_missing"}, wantCode: billingerrors.CodeNotFound},
+		{name: "closed source", targetID: target.ID, sourceIDs: []string{closed.ID}, wantCode: billingerrors.CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.MergeBills(ctx, tt.targetID, tt.sourceIDs)
+			if err == nil || billingerrors.CodeOf(err) != tt.wantCode {
+				t.Errorf("expected a %s error, got %v", tt.wantCode, err)
+			}
+		})
+	}
+
+	if stored, _ := svc.GetBill(ctx, gel.ID, false); stored == nil || len(stored.LineItems) != 1 {
+		t.Error("expected a rejected source to be left as it was")
+	}
+}
+
+func TestMergeBillsRespectsLineItemCap(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository(), WithMaxLineItems(2))
+
+	target, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, target.ID, &model.AddLineItemRequest{Description: "Base", Amount: 10.00, Currency: model.CurrencyUSD})
+	source, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, source.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, source.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 2.00, Currency: model.CurrencyUSD})
+
+	_, err := svc.MergeBills(ctx, target.ID, []string{source.ID})
+	if err == nil || err.Error() != "merge bills: "+billingerrors.TooManyLineItems(target.ID, 2).Error() {
+		t.Fatalf("expected the line item cap error, got %v", err)
+	}
+	if stored, _ := svc.GetBill(ctx, target.ID, false); len(stored.LineItems) != 1 {
+		t.Errorf("expected the target left with its 1 line item, got %d", len(stored.LineItems))
+	}
+	if stored, _ := svc.GetBill(ctx, source.ID, false); stored == nil || len(stored.LineItems) != 2 {
+		t.Error("expected the source left as it was")
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"expvar"
+
+	"fees-api/internal/model"
+)
+
+// Metrics records counters for billing operations
+type Metrics interface {
+	IncBillCreated()
+	IncLineItemAdded(currency model.Currency)
+	ObserveBillTotal(currency model.Currency, amountCents int64)
+}
+
+// NopMetrics discards all measurements
+type NopMetrics struct{}
+
+func (NopMetrics) IncBillCreated()                                             {}
+func (NopMetrics) IncLineItemAdded(currency model.Currency)                    {}
+func (NopMetrics) ObserveBillTotal(currency model.Currency, amountCents int64) {}
+
+// ExpvarMetrics publishes billing counters through the standard expvar registry
+// (served at /debug/vars)
+type ExpvarMetrics struct {
+	vars *expvar.Map
+}
+
+// NewExpvarMetrics registers a metrics map under the given name. The name must be
+// unique for the lifetime of the process.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	vars := expvar.NewMap(name)
+	vars.Set("line_items_added", new(expvar.Map))
+	vars.Set("bills_closed", new(expvar.Map))
+	vars.Set("closed_total_cents", new(expvar.Map))
+	return &ExpvarMetrics{vars: vars}
+}
+
+// IncBillCreated counts a created bill
+func (m *ExpvarMetrics) IncBillCreated() {
+	m.vars.Add("bills_created", 1)
+}
+
+// IncLineItemAdded counts a line item added in the given currency
+func (m *ExpvarMetrics) IncLineItemAdded(currency model.Currency) {
+	m.vars.Get("line_items_added").(*expvar.Map).Add(string(currency), 1)
+}
+
+// ObserveBillTotal records the final total of a closed bill
+func (m *ExpvarMetrics) ObserveBillTotal(currency model.Currency, amountCents int64) {
+	m.vars.Get("bills_closed").(*expvar.Map).Add(string(currency), 1)
+	m.vars.Get("closed_total_cents").(*expvar.Map).Add(string(currency), amountCents)
+}
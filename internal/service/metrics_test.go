@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+
+	"fees-api/internal/model"
+)
+
+// recordingMetrics captures every measurement for assertions
+type recordingMetrics struct {
+	billsCreated   int
+	lineItemsAdded map[model.Currency]int
+	billTotals     map[model.Currency][]int64
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		lineItemsAdded: make(map[model.Currency]int),
+		billTotals:     make(map[model.Currency][]int64),
+	}
+}
+
+func (m *recordingMetrics) IncBillCreated() {
+	m.billsCreated++
+}
+
+func (m *recordingMetrics) IncLineItemAdded(currency model.Currency) {
+	m.lineItemsAdded[currency]++
+}
+
+func (m *recordingMetrics) ObserveBillTotal(currency model.Currency, amountCents int64) {
+	m.billTotals[currency] = append(m.billTotals[currency], amountCents)
+}
+
+func TestMetricsRecorded(t *testing.T) {
+	metrics := newRecordingMetrics()
+	svc := newTestBillingService(t, newMockBillRepository(), WithMetrics(metrics))
+
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if metrics.billsCreated != 1 {
+		t.Errorf("expected 1 bill created, got %d", metrics.billsCreated)
+	}
+
+	svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	svc.AddLineItem(testContext(), bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 100.00, Currency: model.CurrencyGEL})
+	if metrics.lineItemsAdded[model.CurrencyUSD] != 1 || metrics.lineItemsAdded[model.CurrencyGEL] != 1 {
+		t.Errorf("expected one line item per currency, got %v", metrics.lineItemsAdded)
+	}
+
+	svc.CloseBill(testContext(), bill.ID, nil)
+	totals := metrics.billTotals[model.CurrencyUSD]
+	if len(totals) != 1 || totals[0] != 4700 {
+		t.Errorf("expected closed total [4700], got %v", totals)
+	}
+}
+
+func TestMetricsNotRecordedOnFailure(t *testing.T) {
+	metrics := newRecordingMetrics()
+	svc := newTestBillingService(t, newMockBillRepository(), WithMetrics(metrics))
+
+	svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: "EUR"})
+	svc.AddLineItem(testContext(), "nonexistent", &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	svc.CloseBill(testContext(), "nonexistent", nil)
+
+	if metrics.billsCreated != 0 || len(metrics.lineItemsAdded) != 0 || len(metrics.billTotals) != 0 {
+		t.Errorf("expected no metrics for failed operations, got %+v", metrics)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fees-api/internal/logging"
+	"fees-api/internal/model"
+	"fees-api/pkg/money"
+)
+
+// Notifier tells customers about changes to their bills. It's called after the
+// change is committed, so a failure to notify never undoes it.
+type Notifier interface {
+	// NotifyBillClosed tells the bill's customer it has closed
+	NotifyBillClosed(ctx context.Context, bill *model.Bill) error
+}
+
+// NopNotifier sends nothing
+type NopNotifier struct{}
+
+func (NopNotifier) NotifyBillClosed(ctx context.Context, bill *model.Bill) error { return nil }
+
+// notifyClosed tells the customer a bill just closed. The close is already
+// committed, so a failure to notify is logged rather than failing it.
+func (s *BillingService) notifyClosed(ctx context.Context, bill *model.Bill) {
+	if err := s.notifier.NotifyBillClosed(ctx, bill); err != nil {
+		s.logger.Error("notify bill closed failed", "bill_id", bill.ID, "org_id", bill.OrgID, "error", err)
+	}
+}
+
+// EmailMessage is an email ready to send
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers rendered emails, e.g. through an SMTP relay or a provider's API
+type EmailSender interface {
+	SendEmail(ctx context.Context, message EmailMessage) error
+}
+
+// EmailNotifier emails a summary of each closed bill to its org's billing address.
+// Orgs without an address aren't notified.
+type EmailNotifier struct {
+	Addresses map[string]string // billing email address by org ID
+	Sender    EmailSender
+}
+
+// NotifyBillClosed emails the bill's summary to its org, if it has an address
+func (n *EmailNotifier) NotifyBillClosed(ctx context.Context, bill *model.Bill) error {
+	to, ok := n.Addresses[bill.OrgID]
+	if !ok {
+		return nil
+	}
+	message := RenderBillClosedEmail(bill)
+	message.To = to
+	if err := n.Sender.SendEmail(ctx, message); err != nil {
+		return fmt.Errorf("email bill %s to %s: %w", bill.ID, to, err)
+	}
+	return nil
+}
+
+// RenderBillClosedEmail renders the subject and plain-text body summarizing a
+// closed bill: its invoice number, line items, total and due date, with amounts in
+// the bill's currency and dates in its time zone
+func RenderBillClosedEmail(bill *model.Bill) EmailMessage {
+	reference := bill.InvoiceNumber
+	if reference == "" {
+		reference = bill.ID
+	}
+	loc := bill.Location()
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Your bill %s has closed.\n\n", reference)
+	for _, item := range bill.LineItems {
+		amount := money.Format(item.NetAmount(), bill.Currency)
+		fmt.Fprintf(&body, "  %s: %s\n", item.Description, amount)
+	}
+	if len(bill.LineItems) > 0 {
+		body.WriteString("\n")
+	}
+	fmt.Fprintf(&body, "Total: %s\n", money.Format(bill.TotalAmount, bill.Currency))
+	if bill.DueDate != nil {
+		fmt.Fprintf(&body, "Due: %s\n", bill.DueDate.In(loc).Format("2 January 2006"))
+	}
+
+	return EmailMessage{
+		Subject: fmt.Sprintf("Bill %s: %s", reference, money.Format(bill.TotalAmount, bill.Currency)),
+		Body:    body.String(),
+	}
+}
+
+// LogEmailSender is an EmailSender stub that logs each email instead of sending it,
+// until a real delivery provider is configured
+type LogEmailSender struct {
+	Logger logging.Logger
+}
+
+// SendEmail logs the email
+func (s LogEmailSender) SendEmail(ctx context.Context, message EmailMessage) error {
+	s.Logger.Info("email not sent: no delivery provider configured", "to", message.To, "subject", message.Subject)
+	return nil
+}
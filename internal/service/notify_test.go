@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+)
+
+// recordingNotifier records the bills it's told closed, failing with err if set
+type recordingNotifier struct {
+	closed []string
+	err    error
+}
+
+func (n *recordingNotifier) NotifyBillClosed(ctx context.Context, bill *model.Bill) error {
+	n.closed = append(n.closed, bill.ID)
+	return n.err
+}
+
+// recordingEmailSender records the emails it's asked to send
+type recordingEmailSender struct {
+	sent []EmailMessage
+}
+
+func (s *recordingEmailSender) SendEmail(ctx context.Context, message EmailMessage) error {
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestNotifyBillClosed(t *testing.T) {
+	ctx := testContext()
+	notifier := &recordingNotifier{}
+	svc := newTestBillingService(t, newMockBillRepository(), WithNotifier(notifier))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if _, err := svc.CloseBill(ctx, bill.ID, nil); err == nil {
+		t.Fatal("expected closing an empty bill to fail")
+	}
+	if len(notifier.closed) != 0 {
+		t.Fatalf("expected no notification for a failed close, got %v", notifier.closed)
+	}
+
+	if _, err := svc.CloseBill(ctx, bill.ID, allowEmptyClose); err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+	// A retried close returns the closed bill without notifying again
+	if _, err := svc.CloseBill(ctx, bill.ID, &model.CloseBillRequest{Idempotent: true}); err != nil {
+		t.Fatalf("idempotent CloseBill() error = %v", err)
+	}
+	if len(notifier.closed) != 1 || notifier.closed[0] != bill.ID {
+		t.Errorf("expected one notification for %s, got %v", bill.ID, notifier.closed)
+	}
+
+	other, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.CloseBills(ctx, []string{other.ID, bill.ID}, allowEmptyClose)
+	if len(notifier.closed) != 2 || notifier.closed[1] != other.ID {
+		t.Errorf("expected a batch close to notify only the bill it closed, got %v", notifier.closed)
+	}
+}
+
+func TestNotifyFailureDoesNotFailClose(t *testing.T) {
+	ctx := testContext()
+	logger := &capturingLogger{}
+	notifier := &recordingNotifier{err: errors.New("smtp unavailable")}
+	svc := newTestBillingService(t, newMockBillRepository(), WithNotifier(notifier), WithLogger(logger))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	closed, err := svc.CloseBill(ctx, bill.ID, allowEmptyClose)
+	if err != nil || closed.Status != model.BillStatusClosed {
+		t.Fatalf("expected the close to succeed, got %v", err)
+	}
+	if _, billID, ok := logger.find("notify bill closed failed", "bill_id"); !ok || billID != bill.ID {
+		t.Errorf("expected the failed notification to be logged for %s, got %v", bill.ID, billID)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	ctx := testContext()
+	sender := &recordingEmailSender{}
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock), WithNotifier(&EmailNotifier{
+		Addresses: map[string]string{testOrgID: "billing@example.com"},
+		Sender:    sender,
+	}))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Service fee", Amount: 1234.50})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Goodwill", Amount: 4.50, Type: model.LineItemTypeCredit})
+	if _, err := svc.CloseBill(ctx, bill.ID, nil); err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected one email, got %d", len(sender.sent))
+	}
+	email := sender.sent[0]
+	if email.To != "billing@example.com" || email.Subject != "Bill INV-2024-000001: $1,230.00" {
+		t.Errorf("unexpected email header %q / %q", email.To, email.Subject)
+	}
+	for _, want := range []string{"Service fee: $1,234.50", "Goodwill: -$4.50", "Total: $1,230.00", "Due: 31 March 2024"} {
+		if !strings.Contains(email.Body, want) {
+			t.Errorf("expected the body to contain %q, got:\n%s", want, email.Body)
+		}
+	}
+
+	// Orgs without an address aren't emailed
+	unknown := &EmailNotifier{Sender: sender}
+	if err := unknown.NotifyBillClosed(ctx, bill); err != nil || len(sender.sent) != 1 {
+		t.Errorf("expected no email for an org without an address, got %v", err)
+	}
+}
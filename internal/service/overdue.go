@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+)
+
+// OverdueSummary totals, across every org, the closed bills still unpaid after their
+// due date as of asOf: one row per customer and currency, counting the bills and
+// summing their balance due. Rows are ordered by customer, then currency.
+func (s *BillingService) OverdueSummary(ctx context.Context, asOf time.Time) ([]model.OverdueSummary, error) {
+	bills, err := s.repo.List(ctx, repository.BillFilter{Statuses: []model.BillStatus{model.BillStatusClosed}})
+	if err != nil {
+		return nil, fmt.Errorf("overdue summary: %w", err)
+	}
+
+	type key struct {
+		customerID string
+		currency   model.Currency
+	}
+	rows := make(map[key]*model.OverdueSummary)
+	for i := range bills {
+		bill := &bills[i]
+		balance := bill.BalanceDue()
+		if bill.DueDate == nil || !bill.DueDate.Before(asOf) || balance <= 0 {
+			continue
+		}
+		k := key{bill.OrgID, bill.Currency}
+		row, ok := rows[k]
+		if !ok {
+			row = &model.OverdueSummary{CustomerID: bill.OrgID, Currency: bill.Currency}
+			rows[k] = row
+		}
+		row.OverdueCount++
+		row.OverdueTotal += balance
+	}
+
+	summary := make([]model.OverdueSummary, 0, len(rows))
+	for _, row := range rows {
+		summary = append(summary, *row)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].CustomerID != summary[j].CustomerID {
+			return summary[i].CustomerID < summary[j].CustomerID
+		}
+		return summary[i].Currency < summary[j].Currency
+	})
+	return summary, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/tenant"
+)
+
+func TestOverdueSummary(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock), WithPaymentTerms(30*24*time.Hour))
+
+	// closeBill closes a bill of amount for the org at the clock's current time,
+	// so it falls due 30 days later
+	closeBill := func(orgID string, currency model.Currency, amount float64) *model.Bill {
+		t.Helper()
+		ctx := tenant.WithOrgID(context.Background(), orgID)
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: currency})
+		svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: amount, Currency: currency})
+		closed, err := svc.CloseBill(ctx, bill.ID, nil)
+		if err != nil {
+			t.Fatalf("CloseBill() error = %v", err)
+		}
+		return closed
+	}
+
+	// Due April 1
+	closeBill("org_a", model.CurrencyUSD, 100.00)
+	partlyPaid := closeBill("org_a", model.CurrencyUSD, 50.00)
+	svc.RecordPayment(tenant.WithOrgID(context.Background(), "org_a"), partlyPaid.ID, &model.RecordPaymentRequest{Amount: 20.00})
+	settled := closeBill("org_a", model.CurrencyUSD, 75.00)
+	svc.RecordPayment(tenant.WithOrgID(context.Background(), "org_a"), settled.ID, &model.RecordPaymentRequest{Amount: 75.00})
+	closeBill("org_a", model.CurrencyGEL, 40.00)
+	closeBill("org_b", model.CurrencyUSD, 10.00)
+
+	// Due May 1
+	clock.Advance(30 * 24 * time.Hour)
+	closeBill("org_b", model.CurrencyUSD, 25.00)
+	closeBill("org_c", model.CurrencyUSD, 60.00)
+
+	// Open bills are never overdue
+	open, _ := svc.CreateBill(tenant.WithOrgID(context.Background(), "org_c"), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(tenant.WithOrgID(context.Background(), "org_c"), open.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 5.00, Currency: model.CurrencyUSD})
+
+	tests := []struct {
+		name string
+		asOf time.Time
+		want []model.OverdueSummary
+	}{
+		{name: "before anything is due", asOf: start.AddDate(0, 0, 29), want: []model.OverdueSummary{}},
+		{name: "on the due date", asOf: start.AddDate(0, 0, 30), want: []model.OverdueSummary{}},
+		{
+			name: "after the first due date",
+			asOf: start.AddDate(0, 0, 31),
+			want: []model.OverdueSummary{
+				{CustomerID: "org_a", Currency: model.CurrencyGEL, OverdueCount: 1, OverdueTotal: 4000},
+				{CustomerID: "org_a", Currency: model.CurrencyUSD, OverdueCount: 2, OverdueTotal: 10000 + 3000},
+				{CustomerID: "org_b", Currency: model.CurrencyUSD, OverdueCount: 1, OverdueTotal: 1000},
+			},
+		},
+		{
+			name: "after both due dates",
+			asOf: start.AddDate(0, 0, 61),
+			want: []model.OverdueSummary{
+				{CustomerID: "org_a", Currency: model.CurrencyGEL, OverdueCount: 1, OverdueTotal: 4000},
+				{CustomerID: "org_a", Currency: model.CurrencyUSD, OverdueCount: 2, OverdueTotal: 13000},
+				{CustomerID: "org_b", Currency: model.CurrencyUSD, OverdueCount: 2, OverdueTotal: 3500},
+				{CustomerID: "org_c", Currency: model.CurrencyUSD, OverdueCount: 1, OverdueTotal: 6000},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := svc.OverdueSummary(context.Background(), tt.asOf)
+			if err != nil {
+				t.Fatalf("OverdueSummary() error = %v", err)
+			}
+			if !reflect.DeepEqual(summary, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestListBillsCursorStableAcrossInserts(t *testing.T) {
+	ctx := testContext()
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock))
+
+	// Pairs created at the same instant, so the ID breaks ties
+	var want []string
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 2; j++ {
+			bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			want = append(want, bill.ID)
+		}
+		clock.Advance(time.Minute)
+	}
+	// Within each pair, list order is by ID
+	for i := 0; i < len(want); i += 2 {
+		if want[i] > want[i+1] {
+			want[i], want[i+1] = want[i+1], want[i]
+		}
+	}
+
+	var seen []string
+	req := &model.ListBillsRequest{Limit: 2}
+	for page := 0; ; page++ {
+		if page > 10 {
+			t.Fatal("pagination did not terminate")
+		}
+		bills, next, err := svc.ListBills(ctx, req)
+		if err != nil {
+			t.Fatalf("page %d: ListBills() error = %v", page, err)
+		}
+		if len(bills) > 2 {
+			t.Fatalf("page %d: expected at most 2 bills, got %d", page, len(bills))
+		}
+		for _, bill := range bills {
+			seen = append(seen, bill.ID)
+		}
+
+		if page == 0 {
+			// A bill created after the first fetch lands at the end of the list, and
+			// one imported with an earlier CreatedAt lands before the cursor
+			latest, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			want = append(want, latest.ID)
+			closedAt := start.Add(-time.Hour)
+			if _, err := svc.ImportBill(ctx, &model.ImportBillRequest{
+				OrgID:     testOrgID,
+				Currency:  model.CurrencyUSD,
+				Status:    model.BillStatusClosed,
+				CreatedAt: start.Add(-2 * time.Hour),
+				ClosedAt:  &closedAt,
+			}); err != nil {
+				t.Fatalf("ImportBill() error = %v", err)
+			}
+		}
+
+		if next == "" {
+			break
+		}
+		req = &model.ListBillsRequest{Limit: 2, Cursor: next}
+	}
+
+	if len(seen) != len(want) {
+		t.Fatalf("expected %d bills across pages, got %d: %v", len(want), len(seen), seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Errorf("position %d: expected %s, got %s", i, want[i], seen[i])
+		}
+	}
+}
+
+func TestListBillsPageBoundaries(t *testing.T) {
+	ctx := testContext()
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock))
+	for i := 0; i < 4; i++ {
+		svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		clock.Advance(time.Second)
+	}
+
+	all, next, err := svc.ListBills(ctx, &model.ListBillsRequest{})
+	if err != nil || len(all) != 4 || next != "" {
+		t.Errorf("expected all 4 bills and no cursor without a limit, got %d, %q, %v", len(all), next, err)
+	}
+
+	// A page ending exactly on the last bill has no next cursor
+	page, next, _ := svc.ListBills(ctx, &model.ListBillsRequest{Limit: 4})
+	if len(page) != 4 || next != "" {
+		t.Errorf("expected 4 bills and no cursor, got %d, %q", len(page), next)
+	}
+
+	summaries, next, _ := svc.ListBillSummaries(ctx, &model.ListBillsRequest{Limit: 3})
+	if len(summaries) != 3 || next == "" {
+		t.Fatalf("expected 3 summaries and a cursor, got %d, %q", len(summaries), next)
+	}
+	summaries, next, _ = svc.ListBillSummaries(ctx, &model.ListBillsRequest{Limit: 3, Cursor: next})
+	if len(summaries) != 1 || summaries[0].ID != all[3].ID || next != "" {
+		t.Errorf("expected the last bill alone, got %d, %q", len(summaries), next)
+	}
+
+	if _, _, err := svc.ListBills(ctx, &model.ListBillsRequest{Cursor: "not a cursor"}); billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+		t.Errorf("expected an invalid argument error for a bad cursor, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+	"fees-api/pkg/money"
+)
+
+// RecordPayment records a payment received against a closed bill, in the bill's
+// currency. A payment may settle the bill in part or in full but not overpay it.
+func (s *BillingService) RecordPayment(ctx context.Context, billID string, req *model.RecordPaymentRequest) (*model.Bill, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var bill *model.Bill
+	var cents int64
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		bill, err = loadBillFor(ctx, tx, billID, OpRecordPayment)
+		if err != nil {
+			return err
+		}
+
+		cents = model.ToCents(req.Amount)
+		if balance := bill.BalanceDue(); cents > balance {
+			return billingerrors.InvalidArgument([]billingerrors.FieldViolation{{
+				Field:       "amount",
+				Description: "exceeds the balance due of " + money.Format(balance, bill.Currency),
+			}})
+		}
+		bill.AmountPaid += cents
+		return saveBill(ctx, tx, bill)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("record payment: %w", err)
+	}
+
+	s.logger.Info("payment recorded", "bill_id", billID, "amount", cents, "balance_due", bill.BalanceDue())
+	s.publish(ctx, events.NewBillEvent(events.EventPaymentRecorded, bill))
+
+	return bill, nil
+}
+
+// Now returns the current time by the service's clock, for callers deriving
+// time-dependent views of a bill
+func (s *BillingService) Now() time.Time {
+	return s.clock.Now()
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestRecordPayment(t *testing.T) {
+	ctx := testContext()
+	closedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(newFakeClock(closedAt)),
+		WithPaymentTerms(14*24*time.Hour))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 50.00, Currency: model.CurrencyUSD})
+
+	if _, err := svc.RecordPayment(ctx, bill.ID, &model.RecordPaymentRequest{Amount: 10}); err == nil {
+		t.Error("expected a payment on an open bill to be rejected")
+	}
+
+	closed, err := svc.CloseBill(ctx, bill.ID, nil)
+	if err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+	if want := closedAt.AddDate(0, 0, 14); closed.DueDate == nil || !closed.DueDate.Equal(want) {
+		t.Errorf("expected due date %s, got %v", want, closed.DueDate)
+	}
+
+	paid, err := svc.RecordPayment(ctx, bill.ID, &model.RecordPaymentRequest{Amount: 20.00})
+	if err != nil {
+		t.Fatalf("RecordPayment() error = %v", err)
+	}
+	if paid.AmountPaid != 2000 || paid.BalanceDue() != 3000 {
+		t.Errorf("expected 2000 paid with 3000 due, got %d paid with %d due", paid.AmountPaid, paid.BalanceDue())
+	}
+
+	_, err = svc.RecordPayment(ctx, bill.ID, &model.RecordPaymentRequest{Amount: 30.01})
+	if billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+		t.Errorf("expected an overpayment to be invalid, got %v", err)
+	}
+	if paid, _ = svc.RecordPayment(ctx, bill.ID, &model.RecordPaymentRequest{Amount: 30.00}); paid == nil || paid.BalanceDue() != 0 {
+		t.Errorf("expected the remaining balance to settle the bill, got %+v", paid)
+	}
+
+	reopened, _ := svc.ReopenBill(ctx, bill.ID)
+	if reopened.DueDate != nil || reopened.AmountPaid != 5000 {
+		t.Errorf("expected reopening to clear the due date and keep payments, got %v and %d", reopened.DueDate, reopened.AmountPaid)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// Operation names a change to a bill whose permission depends on the bill's status
+type Operation string
+
+const (
+	OpAddLineItem      Operation = "add_line_item"
+	OpReplaceLineItems Operation = "replace_line_items"
+	OpUpdateLineItem   Operation = "update_line_item"
+	OpImportLineItems  Operation = "import_line_items"
+	OpChangeCurrency   Operation = "change_currency"
+	OpActivate         Operation = "activate"
+	OpApprove          Operation = "approve"
+	OpClose            Operation = "close"
+	OpReopen           Operation = "reopen"
+	OpSplit            Operation = "split"
+	OpMerge            Operation = "merge"
+	OpUpdateNote       Operation = "update_note"
+	OpRecordPayment    Operation = "record_payment"
+)
+
+// operationStatuses is the policy for modifying bills: the statuses each operation
+// is permitted on. Charges can only change until a bill closes; notes are internal
+// context and payments only make sense once a bill is final, so both are allowed
+// on closed bills. Every status check on a mutation goes through it.
+var operationStatuses = map[Operation][]model.BillStatus{
+	OpAddLineItem:      {model.BillStatusDraft, model.BillStatusOpen},
+	OpReplaceLineItems: {model.BillStatusDraft, model.BillStatusOpen},
+	OpUpdateLineItem:   {model.BillStatusDraft, model.BillStatusOpen},
+	OpImportLineItems:  {model.BillStatusDraft, model.BillStatusOpen},
+	OpChangeCurrency:   {model.BillStatusOpen},
+	OpActivate:         {model.BillStatusDraft},
+	OpApprove:          {model.BillStatusOpen},
+	OpClose:            {model.BillStatusOpen},
+	OpReopen:           {model.BillStatusClosed},
+	OpSplit:            {model.BillStatusOpen},
+	OpMerge:            {model.BillStatusOpen},
+	OpUpdateNote:       {model.BillStatusDraft, model.BillStatusOpen, model.BillStatusClosed},
+	OpRecordPayment:    {model.BillStatusClosed},
+}
+
+// Permits reports whether the policy allows op on a bill in the given status.
+// Unknown operations are never permitted.
+func Permits(op Operation, status model.BillStatus) bool {
+	for _, allowed := range operationStatuses[op] {
+		if status == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOperation returns the error for op not being permitted on the bill, or nil.
+// The error says what the operation needed: a closed bill for reopening or payment,
+// a draft for activation, an activated draft for closing, and otherwise a bill that
+// isn't closed, or is open.
+func checkOperation(bill *model.Bill, op Operation) error {
+	if Permits(op, bill.Status) {
+		return nil
+	}
+	switch {
+	case Permits(op, model.BillStatusClosed):
+		return billingerrors.BillNotClosed(bill.ID)
+	case op == OpActivate:
+		return billingerrors.BillNotDraft(bill.ID)
+	case bill.Status == model.BillStatusClosed:
+		return billingerrors.BillClosed(bill.ID)
+	case op == OpClose && bill.Status == model.BillStatusDraft:
+		return billingerrors.BillIsDraft(bill.ID)
+	default:
+		return billingerrors.BillNotOpen(bill.ID)
+	}
+}
+
+// loadBillFor fetches a bill for the operation, failing if the policy doesn't
+// permit it in the bill's status
+func loadBillFor(ctx context.Context, repo repository.BillRepository, billID string, op Operation) (*model.Bill, error) {
+	bill, err := loadBill(ctx, repo, billID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOperation(bill, op); err != nil {
+		return nil, err
+	}
+	return bill, nil
+}
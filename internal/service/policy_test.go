@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestOperationPolicy(t *testing.T) {
+	const (
+		draft  = model.BillStatusDraft
+		open   = model.BillStatusOpen
+		closed = model.BillStatusClosed
+	)
+	tests := []struct {
+		op      Operation
+		allowed []model.BillStatus
+	}{
+		{OpAddLineItem, []model.BillStatus{draft, open}},
+		{OpReplaceLineItems, []model.BillStatus{draft, open}},
+		{OpUpdateLineItem, []model.BillStatus{draft, open}},
+		{OpImportLineItems, []model.BillStatus{draft, open}},
+		{OpChangeCurrency, []model.BillStatus{open}},
+		{OpActivate, []model.BillStatus{draft}},
+		{OpApprove, []model.BillStatus{open}},
+		{OpClose, []model.BillStatus{open}},
+		{OpReopen, []model.BillStatus{closed}},
+		{OpSplit, []model.BillStatus{open}},
+		{OpMerge, []model.BillStatus{open}},
+		{OpUpdateNote, []model.BillStatus{draft, open, closed}},
+		{OpRecordPayment, []model.BillStatus{closed}},
+	}
+	if len(tests) != len(operationStatuses) {
+		t.Fatalf("expected every operation to be covered, got %d of %d", len(tests), len(operationStatuses))
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.op), func(t *testing.T) {
+			for _, status := range []model.BillStatus{draft, open, closed} {
+				want := false
+				for _, allowed := range tt.allowed {
+					want = want || allowed == status
+				}
+				if got := Permits(tt.op, status); got != want {
+					t.Errorf("Permits(%s, %s) = %v, want %v", tt.op, status, got, want)
+				}
+			}
+		})
+	}
+
+	if Permits("void", open) {
+		t.Error("expected an unknown operation to be refused")
+	}
+}
+
+func TestCheckOperationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		op     Operation
+		status model.BillStatus
+		want   string
+	}{
+		{name: "line item on closed", op: OpAddLineItem, status: model.BillStatusClosed, want: "closed bill"},
+		{name: "reopen open", op: OpReopen, status: model.BillStatusOpen, want: "is not closed"},
+		{name: "payment on draft", op: OpRecordPayment, status: model.BillStatusDraft, want: "is not closed"},
+		{name: "activate open", op: OpActivate, status: model.BillStatusOpen, want: "is not a draft"},
+		{name: "close draft", op: OpClose, status: model.BillStatusDraft, want: "draft"},
+		{name: "split draft", op: OpSplit, status: model.BillStatusDraft, want: "is not open"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOperation(&model.Bill{ID: "bill_1", Status: tt.status}, tt.op)
+			if err == nil {
+				t.Fatalf("expected %s to be refused on a %s bill", tt.op, tt.status)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	err := checkOperation(&model.Bill{ID: "bill_1", Status: model.BillStatusClosed}, OpMerge)
+	if !errors.Is(err, billingerrors.ErrBillClosed) {
+		t.Errorf("expected ErrBillClosed for a closed bill, got %v", err)
+	}
+	if err := checkOperation(&model.Bill{ID: "bill_1", Status: model.BillStatusClosed}, OpUpdateNote); err != nil {
+		t.Errorf("expected a note update on a closed bill to be allowed, got %v", err)
+	}
+}
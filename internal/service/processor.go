@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+const (
+	// ProcessorSignatureHeader carries the processor's signature of a webhook body
+	ProcessorSignatureHeader = "Processor-Signature"
+
+	// processorSignatureTolerance is how far a signature's timestamp may be from now,
+	// so a captured delivery can't be replayed later
+	processorSignatureTolerance = 5 * time.Minute
+)
+
+// ProcessorWebhookService turns fee notifications from the payment processor into
+// line items. Each delivery is signed Stripe-style: the header holds a timestamp and
+// an HMAC-SHA256 of "<timestamp>.<body>" under a shared secret. Fees reach a bill
+// through a link from the processor's external reference to the bill's ID.
+type ProcessorWebhookService struct {
+	refs   repository.ProcessorRefRepository
+	bills  *BillingService
+	secret string
+}
+
+// NewProcessorWebhookService creates a processor webhook service verifying deliveries
+// with secret. With an empty secret every delivery is refused.
+func NewProcessorWebhookService(refs repository.ProcessorRefRepository, bills *BillingService, secret string) *ProcessorWebhookService {
+	return &ProcessorWebhookService{refs: refs, bills: bills, secret: secret}
+}
+
+// LinkRef routes the processor's fees for ref to a bill, replacing any earlier link
+func (s *ProcessorWebhookService) LinkRef(ctx context.Context, ref string, req *model.LinkProcessorRefRequest) (*model.ProcessorRef, error) {
+	if ref == "" {
+		return nil, billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "ref", Description: "is required"}})
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	exists, err := s.bills.BillExists(tenant.WithOrgID(ctx, req.OrgID), req.BillID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, billingerrors.BillNotFound(req.BillID)
+	}
+
+	link := &model.ProcessorRef{Ref: ref, OrgID: req.OrgID, BillID: req.BillID, UpdatedAt: s.bills.clock.Now()}
+	if err := s.refs.Put(ctx, link); err != nil {
+		return nil, fmt.Errorf("link processor ref: %w", err)
+	}
+	return link, nil
+}
+
+// Ingest verifies a processor webhook delivery and applies the fee it carries to the
+// linked bill. Deliveries are retried by the processor, so a fee already on the bill
+// (matched by its ID, stored as the line item's ref) is reported as a duplicate
+// rather than added twice. The bill is returned when a line item was added.
+func (s *ProcessorWebhookService) Ingest(ctx context.Context, body []byte, signature string) (*model.ProcessorWebhookResult, *model.Bill, error) {
+	if err := s.verify(body, signature); err != nil {
+		return nil, nil, err
+	}
+
+	var event model.ProcessorEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, nil, billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "body", Description: "is not a valid processor event: " + err.Error()}})
+	}
+	result := &model.ProcessorWebhookResult{EventID: event.ID}
+	if event.Type != model.ProcessorEventFeeCreated {
+		return result, nil, nil
+	}
+
+	fee := event.Data.Object
+	if fee.ID == "" || fee.Reference == "" {
+		return nil, nil, billingerrors.InvalidArgument([]billingerrors.FieldViolation{{Field: "data.object", Description: "needs an id and a reference"}})
+	}
+	link, err := s.refs.Get(ctx, fee.Reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	if link == nil {
+		return nil, nil, billingerrors.ProcessorRefNotFound(fee.Reference)
+	}
+	result.BillID = link.BillID
+
+	description := fee.Description
+	if description == "" {
+		description = "Processor fee " + fee.ID
+	}
+	bill, err := s.bills.AddLineItem(tenant.WithOrgID(ctx, link.OrgID), link.BillID, &model.AddLineItemRequest{
+		Description: description,
+		Amount:      float64(fee.Amount) / 100,
+		Currency:    model.Currency(strings.ToUpper(fee.Currency)),
+		Category:    model.CategoryProcessing,
+		Ref:         fee.ID,
+		Metadata:    map[string]string{"processor_event_id": event.ID},
+		// The fee's ID guards against repeats; distinct fees may look identical
+		Force: true,
+	})
+	if errors.Is(err, billingerrors.ErrDuplicateLineItemRef) {
+		result.Duplicate = true
+		return result, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result.Applied = true
+	if item := findRef(bill.LineItems, fee.ID); item != nil {
+		result.LineItemID = item.ID
+	}
+	return result, bill, nil
+}
+
+// verify checks a "t=<unix seconds>,v1=<hex HMAC>" signature header against the body
+func (s *ProcessorWebhookService) verify(body []byte, header string) error {
+	if s.secret == "" {
+		return billingerrors.Unavailable("processor webhooks are not configured")
+	}
+	if header == "" {
+		return billingerrors.InvalidProcessorSignature("missing " + ProcessorSignatureHeader + " header")
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return billingerrors.InvalidProcessorSignature("malformed header")
+	}
+	if age := s.bills.clock.Now().Sub(time.Unix(unix, 0)); age > processorSignatureTolerance || age < -processorSignatureTolerance {
+		return billingerrors.InvalidProcessorSignature("timestamp outside the tolerance")
+	}
+
+	expected := processorMAC(s.secret, timestamp, body)
+	for _, signature := range signatures {
+		if got, err := hex.DecodeString(signature); err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return billingerrors.InvalidProcessorSignature("no matching signature")
+}
+
+// SignProcessorPayload builds the signature header the processor sends with a body
+// at the given time
+func SignProcessorPayload(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(processorMAC(secret, timestamp, body))
+}
+
+func processorMAC(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
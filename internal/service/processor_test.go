@@ -0,0 +1,128 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+const testProcessorSecret = "whsec_test"
+
+// processorFeeEvent builds a fee.created delivery body
+func processorFeeEvent(t *testing.T, eventID, feeID, ref string, cents int64) []byte {
+	t.Helper()
+	body, err := json.Marshal(model.ProcessorEvent{
+		ID:   eventID,
+		Type: model.ProcessorEventFeeCreated,
+		Data: model.ProcessorEventData{Object: model.ProcessorFee{
+			ID: feeID, Amount: cents, Currency: "usd", Description: "Card fee", Reference: ref,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return body
+}
+
+func newTestProcessorWebhookService(t *testing.T) (*ProcessorWebhookService, *BillingService, *fakeClock) {
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	bills := newTestBillingService(t, newMockBillRepository(), WithClock(clock))
+	return NewProcessorWebhookService(repository.NewInMemoryProcessorRefRepository(), bills, testProcessorSecret), bills, clock
+}
+
+func TestProcessorWebhookIngest(t *testing.T) {
+	ctx := testContext()
+	processor, bills, clock := newTestProcessorWebhookService(t)
+
+	bill, _ := bills.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if _, err := processor.LinkRef(ctx, "cus_123", &model.LinkProcessorRefRequest{OrgID: testOrgID, BillID: bill.ID}); err != nil {
+		t.Fatalf("LinkRef() error = %v", err)
+	}
+
+	body := processorFeeEvent(t, "evt_1", "fee_1", "cus_123", 290)
+	result, updated, err := processor.Ingest(ctx, body, SignProcessorPayload(testProcessorSecret, clock.Now(), body))
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if !result.Applied || result.Duplicate || result.BillID != bill.ID || result.LineItemID == "" {
+		t.Errorf("expected the fee applied to %s, got %+v", bill.ID, result)
+	}
+	if updated == nil || len(updated.LineItems) != 1 {
+		t.Fatalf("expected one line item on the bill, got %+v", updated)
+	}
+	item := updated.LineItems[0]
+	if item.Amount != 290 || item.Ref != "fee_1" || item.Category != model.CategoryProcessing || item.Metadata["processor_event_id"] != "evt_1" {
+		t.Errorf("unexpected line item %+v", item)
+	}
+
+	// The processor redelivers the same event
+	clock.Advance(time.Minute)
+	result, updated, err = processor.Ingest(ctx, body, SignProcessorPayload(testProcessorSecret, clock.Now(), body))
+	if err != nil {
+		t.Fatalf("redelivery: Ingest() error = %v", err)
+	}
+	if !result.Duplicate || result.Applied || updated != nil {
+		t.Errorf("expected the redelivery reported as a duplicate, got %+v", result)
+	}
+	if stored, _ := bills.GetBill(ctx, bill.ID, false); len(stored.LineItems) != 1 || stored.TotalAmount != 290 {
+		t.Errorf("expected the fee added once, got %d items at %d", len(stored.LineItems), stored.TotalAmount)
+	}
+
+	// Other event types are acknowledged and ignored
+	other, _ := json.Marshal(model.ProcessorEvent{ID: "evt_2", Type: "payout.paid"})
+	result, updated, err = processor.Ingest(ctx, other, SignProcessorPayload(testProcessorSecret, clock.Now(), other))
+	if err != nil || result.Applied || updated != nil {
+		t.Errorf("expected an ignored event, got %+v, %v", result, err)
+	}
+}
+
+func TestProcessorWebhookRejects(t *testing.T) {
+	ctx := testContext()
+	processor, bills, clock := newTestProcessorWebhookService(t)
+	bill, _ := bills.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	processor.LinkRef(ctx, "cus_123", &model.LinkProcessorRefRequest{OrgID: testOrgID, BillID: bill.ID})
+
+	body := processorFeeEvent(t, "evt_1", "fee_1", "cus_123", 290)
+	unlinked := processorFeeEvent(t, "evt_2", "fee_2", "cus_unknown", 290)
+
+	tests := []struct {
+		name      string
+		body      []byte
+		signature string
+		wantCode  billingerrors.Code
+	}{
+		{name: "unsigned", body: body, signature: "", wantCode: billingerrors.CodeUnauthenticated},
+		{name: "wrong secret", body: body, signature: SignProcessorPayload("whsec_other", clock.Now(), body), wantCode: billingerrors.CodeUnauthenticated},
+		{name: "tampered body", body: unlinked, signature: SignProcessorPayload(testProcessorSecret, clock.Now(), body), wantCode: billingerrors.CodeUnauthenticated},
+		{name: "stale timestamp", body: body, signature: SignProcessorPayload(testProcessorSecret, clock.Now().Add(-10*time.Minute), body), wantCode: billingerrors.CodeUnauthenticated},
+		{name: "malformed header", body: body, signature: "v1=abc", wantCode: billingerrors.CodeUnauthenticated},
+		{name: "unlinked ref", body: unlinked, signature: SignProcessorPayload(testProcessorSecret, clock.Now(), unlinked), wantCode: billingerrors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := processor.Ingest(ctx, tt.body, tt.signature)
+			if billingerrors.CodeOf(err) != tt.wantCode {
+				t.Errorf("expected a %s error, got %v", tt.wantCode, err)
+			}
+		})
+	}
+
+	if stored, _ := bills.GetBill(ctx, bill.ID, false); len(stored.LineItems) != 0 {
+		t.Errorf("expected no line items from rejected deliveries, got %d", len(stored.LineItems))
+	}
+}
+
+func TestProcessorLinkRefRequiresBill(t *testing.T) {
+	ctx := testContext()
+	processor, _, _ := newTestProcessorWebhookService(t)
+
+	_, err := processor.LinkRef(ctx, "cus_123", &model.LinkProcessorRefRequest{OrgID: testOrgID, BillID: "bill_missing"})
+	if billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// RateQuote is a conversion rate along with when it was last known to be accurate
+type RateQuote struct {
+	Rate float64   `json:"rate"`
+	AsOf time.Time `json:"asOf"`
+}
+
+// ExchangeRateProvider supplies conversion rates between currencies
+type ExchangeRateProvider interface {
+	// Quote returns how many units of `to` one unit of `from` is worth, and as of when
+	Quote(from, to model.Currency) (RateQuote, error)
+}
+
+// RateSetter is implemented by providers whose rates can be overridden at runtime
+type RateSetter interface {
+	// SetRate updates the USD value of one unit of the currency
+	SetRate(currency model.Currency, rateToUSD float64)
+}
+
+// StaleRatePolicy decides what happens when a quote is older than the allowed age
+type StaleRatePolicy int
+
+const (
+	// StaleRateReject fails the conversion
+	StaleRateReject StaleRatePolicy = iota
+	// StaleRateFallback converts at the stale quote, the last known rate
+	StaleRateFallback
+)
+
+// StaticRateProvider serves rates from an in-memory table of USD values, plus any
+// direct pair rates, which take precedence over pivoting through USD
+type StaticRateProvider struct {
+	mu         sync.RWMutex
+	ratesToUSD map[model.Currency]float64
+	asOf       map[model.Currency]time.Time
+	pairs      map[currencyPair]RateQuote
+}
+
+// currencyPair keys a direct rate; the rate converts From into To only
+type currencyPair struct {
+	From, To model.Currency
+}
+
+// NewStaticRateProvider creates a provider from rates expressed as the USD value of one unit.
+// The rates are considered current as of construction.
+func NewStaticRateProvider(ratesToUSD map[model.Currency]float64) *StaticRateProvider {
+	now := time.Now().UTC()
+	rates := make(map[model.Currency]float64, len(ratesToUSD))
+	asOf := make(map[model.Currency]time.Time, len(ratesToUSD))
+	for currency, rate := range ratesToUSD {
+		rates[currency] = rate
+		asOf[currency] = now
+	}
+	return &StaticRateProvider{ratesToUSD: rates, asOf: asOf, pairs: make(map[currencyPair]RateQuote)}
+}
+
+// Quote returns the conversion rate from one currency to another: the direct pair
+// rate if one is set, otherwise pivoting through USD. A pivoted quote is as old as
+// the older of the two rates it's derived from.
+func (p *StaticRateProvider) Quote(from, to model.Currency) (RateQuote, error) {
+	if from == to {
+		return RateQuote{Rate: 1.0, AsOf: time.Now().UTC()}, nil
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if quote, ok := p.pairs[currencyPair{From: from, To: to}]; ok {
+		if !validRate(quote.Rate) {
+			return RateQuote{}, billingerrors.InvalidRate(string(from), string(to), quote.Rate)
+		}
+		return quote, nil
+	}
+
+	fromUSD, ok := p.ratesToUSD[from]
+	if !ok {
+		return RateQuote{}, billingerrors.UnsupportedCurrency(string(from))
+	}
+	toUSD, ok := p.ratesToUSD[to]
+	if !ok {
+		return RateQuote{}, billingerrors.UnsupportedCurrency(string(to))
+	}
+	// Rates set at runtime bypass Validate; never divide by a zero or negative one
+	if !validRate(fromUSD) {
+		return RateQuote{}, billingerrors.InvalidRate(string(from), string(model.CurrencyUSD), fromUSD)
+	}
+	if !validRate(toUSD) {
+		return RateQuote{}, billingerrors.InvalidRate(string(to), string(model.CurrencyUSD), toUSD)
+	}
+
+	asOf := p.asOf[from]
+	if p.asOf[to].Before(asOf) {
+		asOf = p.asOf[to]
+	}
+	return RateQuote{Rate: fromUSD / toUSD, AsOf: asOf}, nil
+}
+
+// SetRate updates the USD value of one unit of the currency, current as of now
+func (p *StaticRateProvider) SetRate(currency model.Currency, rateToUSD float64) {
+	p.SetQuote(currency, rateToUSD, time.Now().UTC())
+}
+
+// SetQuote updates the USD value of one unit of the currency along with when it was observed
+func (p *StaticRateProvider) SetQuote(currency model.Currency, rateToUSD float64, asOf time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ratesToUSD[currency] = rateToUSD
+	p.asOf[currency] = asOf
+}
+
+// SetPairRate sets a direct rate, e.g. a negotiated one, for converting from into
+// to, current as of now. It applies in that direction only; set the reverse pair
+// too for conversions the other way, or they keep pivoting through USD.
+func (p *StaticRateProvider) SetPairRate(from, to model.Currency, rate float64) {
+	p.SetPairQuote(from, to, rate, time.Now().UTC())
+}
+
+// SetPairQuote sets a direct rate for converting from into to along with when it was observed
+func (p *StaticRateProvider) SetPairQuote(from, to model.Currency, rate float64, asOf time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pairs[currencyPair{From: from, To: to}] = RateQuote{Rate: rate, AsOf: asOf}
+}
+
+// Validate checks the provider's table against the supported currencies, and that
+// every pair rate is positive
+func (p *StaticRateProvider) Validate(supported []model.Currency) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if err := ValidateRates(supported, p.ratesToUSD); err != nil {
+		return err
+	}
+	for pair, quote := range p.pairs {
+		if quote.Rate <= 0 {
+			return fmt.Errorf("exchange rate for %s->%s must be positive, got %v", pair.From, pair.To, quote.Rate)
+		}
+	}
+	return nil
+}
+
+// ValidateRates checks that every supported currency has a positive USD rate and
+// that USD, the pivot currency, is exactly 1.0
+func ValidateRates(supported []model.Currency, ratesToUSD map[model.Currency]float64) error {
+	for _, currency := range supported {
+		rate, ok := ratesToUSD[currency]
+		if !ok {
+			return fmt.Errorf("missing exchange rate for %s", currency)
+		}
+		if rate <= 0 {
+			return fmt.Errorf("exchange rate for %s must be positive, got %v", currency, rate)
+		}
+	}
+	if rate, ok := ratesToUSD[model.CurrencyUSD]; ok && rate != 1.0 {
+		return fmt.Errorf("exchange rate for USD must be 1.0, got %v", rate)
+	}
+	return nil
+}
+
+// validRate reports whether a rate can be converted at: positive and finite
+func validRate(rate float64) bool {
+	return rate > 0 && !math.IsInf(rate, 0) && !math.IsNaN(rate)
+}
+
+// validateRateProvider checks that the provider can convert every supported
+// currency. Static tables are validated directly; other providers are probed.
+func validateRateProvider(provider ExchangeRateProvider, supported []model.Currency) error {
+	if static, ok := provider.(*StaticRateProvider); ok {
+		return static.Validate(supported)
+	}
+	for _, currency := range supported {
+		quote, err := provider.Quote(currency, model.CurrencyUSD)
+		if err != nil {
+			return fmt.Errorf("exchange rate for %s: %v", currency, err)
+		}
+		if quote.Rate <= 0 {
+			return fmt.Errorf("exchange rate for %s must be positive, got %v", currency, quote.Rate)
+		}
+	}
+	return nil
+}
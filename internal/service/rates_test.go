@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// fixedRateProvider quotes the same rate for every conversion
+type fixedRateProvider struct {
+	rate float64
+}
+
+func (p fixedRateProvider) Quote(from, to model.Currency) (RateQuote, error) {
+	if from == to {
+		return RateQuote{Rate: 1.0, AsOf: time.Now()}, nil
+	}
+	return RateQuote{Rate: p.rate, AsOf: time.Now()}, nil
+}
+
+func TestMisconfiguredRatesAreRefused(t *testing.T) {
+	for _, rate := range []float64{0, -0.37} {
+		t.Run(fmt.Sprintf("static table at %v", rate), func(t *testing.T) {
+			ctx := testContext()
+			rates := accumulationRates()
+			svc := newTestBillingService(t, newMockBillRepository(), WithRateProvider(rates))
+			// A runtime override that skipped validation, as a bad config push might
+			rates.SetQuote(model.CurrencyGEL, rate, time.Now())
+
+			bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyGEL})
+			_, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+			if !errors.Is(err, billingerrors.ErrInvalidRate) {
+				t.Fatalf("expected ErrInvalidRate converting USD into GEL at %v, got %v", rate, err)
+			}
+			if stored, _ := svc.GetBill(ctx, bill.ID, false); stored.TotalAmount != 0 || len(stored.LineItems) != 0 {
+				t.Errorf("expected the bill untouched, got total %d with %d items", stored.TotalAmount, len(stored.LineItems))
+			}
+
+			if _, err := svc.ConvertToUSD(1000, model.CurrencyGEL); !errors.Is(err, billingerrors.ErrInvalidRate) {
+				t.Errorf("expected ErrInvalidRate converting GEL to USD at %v, got %v", rate, err)
+			}
+			if cents, err := svc.ConvertToUSD(1000, model.CurrencyUSD); err != nil || cents != 1000 {
+				t.Errorf("expected USD to convert unaffected, got %d, %v", cents, err)
+			}
+		})
+	}
+
+	for _, rate := range []float64{0, math.Inf(1), math.NaN()} {
+		t.Run(fmt.Sprintf("custom provider at %v", rate), func(t *testing.T) {
+			ctx := testContext()
+			svc, err := NewBillingService(newMockBillRepository(), WithRateProvider(fixedRateProvider{rate: 1.0}))
+			if err != nil {
+				t.Fatalf("NewBillingService() error = %v", err)
+			}
+			svc.rates = fixedRateProvider{rate: rate}
+
+			bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+			_, err = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyGEL})
+			if !errors.Is(err, billingerrors.ErrInvalidRate) {
+				t.Errorf("expected ErrInvalidRate for a rate of %v, got %v", rate, err)
+			}
+			if _, err := svc.ConvertToUSD(1000, model.CurrencyGEL); !errors.Is(err, billingerrors.ErrInvalidRate) {
+				t.Errorf("expected ErrInvalidRate converting at %v, got %v", rate, err)
+			}
+		})
+	}
+}
+
+// flatRateProvider quotes every pair at the same rate, its own currency included
+type flatRateProvider struct {
+	rate float64
+}
+
+func (p flatRateProvider) Quote(from, to model.Currency) (RateQuote, error) {
+	return RateQuote{Rate: p.rate, AsOf: time.Now()}, nil
+}
+
+func TestSameCurrencyItemsRecordUnitRate(t *testing.T) {
+	ctx := testContext()
+	// A provider that would misquote a currency against itself, and an FX markup
+	// that must not apply to unconverted items
+	svc := newTestBillingService(t, newMockBillRepository(), WithRateProvider(flatRateProvider{rate: 0.5}), WithFXMarkup(0.02))
+
+	assertUnitRate := func(t *testing.T, item model.LineItem) {
+		t.Helper()
+		if item.AppliedRate != 1.0 || item.ConvertedAmount != item.Amount || item.FXFee != 0 || item.RateAsOf == nil {
+			t.Errorf("expected %s to record a rate of 1.0 and its own amount, got rate %v, converted %d of %d, fee %d",
+				item.Description, item.AppliedRate, item.ConvertedAmount, item.Amount, item.FXFee)
+		}
+	}
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Explicit", Amount: 12.34, Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Implicit", Amount: 5.00})
+	bill, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Converted", Amount: 10.00, Currency: model.CurrencyGEL})
+	if err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+	assertUnitRate(t, bill.LineItems[0])
+	assertUnitRate(t, bill.LineItems[1])
+	if converted := bill.LineItems[2]; converted.AppliedRate != 0.5 || converted.ConvertedAmount != 500 {
+		t.Errorf("expected the GEL item converted at 0.5, got rate %v and %d", converted.AppliedRate, converted.ConvertedAmount)
+	}
+	if want := int64(1234 + 500 + 500 + 10); bill.TotalAmount != want {
+		t.Errorf("expected total %d, got %d", want, bill.TotalAmount)
+	}
+
+	amount := 20.00
+	bill, err = svc.UpdateLineItem(ctx, bill.ID, bill.LineItems[0].ID, &model.UpdateLineItemRequest{Amount: &amount})
+	if err != nil {
+		t.Fatalf("UpdateLineItem() error = %v", err)
+	}
+	assertUnitRate(t, bill.LineItems[0])
+
+	bill, err = svc.ReplaceLineItems(ctx, bill.ID, []model.AddLineItemRequest{{Description: "Replacement", Amount: 7.50}})
+	if err != nil {
+		t.Fatalf("ReplaceLineItems() error = %v", err)
+	}
+	assertUnitRate(t, bill.LineItems[0])
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// issueReceipt records the receipt for a bill that just closed. The close is already
+// committed, so a failure to store the receipt is logged rather than failing it.
+func (s *BillingService) issueReceipt(ctx context.Context, bill *model.Bill) {
+	issued, err := s.receipts.ListByBill(ctx, bill.OrgID, bill.ID)
+	if err == nil {
+		err = s.receipts.Create(ctx, NewReceipt(bill, len(issued)+1))
+	}
+	if err != nil {
+		s.logger.Error("issue receipt failed", "bill_id", bill.ID, "error", err)
+	}
+}
+
+// NewReceipt snapshots a closed bill as its sequence-th receipt, with the totals of
+// its breakdown
+func NewReceipt(bill *model.Bill, sequence int) *model.Receipt {
+	breakdown := Breakdown(bill)
+	receipt := &model.Receipt{
+		ID:            fmt.Sprintf("rcpt_%s_%d", bill.ID, sequence),
+		BillID:        bill.ID,
+		OrgID:         bill.OrgID,
+		Sequence:      sequence,
+		InvoiceNumber: bill.InvoiceNumber,
+		Currency:      bill.Currency,
+		Timezone:      bill.Timezone,
+		Subtotal:      breakdown.Subtotal,
+		CreditTotal:   breakdown.CreditTotal,
+		FXFees:        breakdown.FXFees,
+		DiscountTotal: breakdown.DiscountTotal,
+		TaxTotal:      breakdown.TaxTotal,
+		Total:         bill.TotalAmount,
+		LineItems:     make([]model.LineItem, len(bill.LineItems)),
+	}
+	for i := range bill.LineItems {
+		receipt.LineItems[i] = bill.LineItems[i].Clone()
+	}
+	if bill.ClosedAt != nil {
+		receipt.ClosedAt = *bill.ClosedAt
+	}
+	if bill.CloseApproval != nil {
+		receipt.ApprovedBy = bill.CloseApproval.ApprovedBy
+	}
+	return receipt
+}
+
+// GetReceipt returns the receipt issued by the bill's most recent close
+func (s *BillingService) GetReceipt(ctx context.Context, billID string) (*model.Receipt, error) {
+	receipts, err := s.ListReceipts(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) == 0 {
+		return nil, billingerrors.ReceiptNotFound(billID)
+	}
+	return &receipts[len(receipts)-1], nil
+}
+
+// ListReceipts returns every receipt issued for the bill, oldest first
+func (s *BillingService) ListReceipts(ctx context.Context, billID string) ([]model.Receipt, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := s.receipts.ListByBill(ctx, orgID, billID)
+	if err != nil {
+		return nil, fmt.Errorf("list receipts: %w", err)
+	}
+	return receipts, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/tenant"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestReceiptIssuedOnClose(t *testing.T) {
+	ctx := testContext()
+	clock := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	svc := newTestBillingService(t, newMockBillRepository(), WithClock(clock), WithFXMarkup(0.02))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Service fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Usage", Amount: 100.00, Currency: model.CurrencyGEL})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Goodwill", Amount: 2.00, Currency: model.CurrencyUSD, Type: model.LineItemTypeCredit})
+
+	if _, err := svc.GetReceipt(ctx, bill.ID); billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+		t.Errorf("expected no receipt before the bill closes, got %v", err)
+	}
+
+	closed, err := svc.CloseBill(ctx, bill.ID, nil)
+	if err != nil {
+		t.Fatalf("CloseBill() error = %v", err)
+	}
+	receipt, err := svc.GetReceipt(ctx, bill.ID)
+	if err != nil {
+		t.Fatalf("GetReceipt() error = %v", err)
+	}
+
+	// 10.00 USD + 100.00 GEL at 0.37 with a 2% FX fee, less a 2.00 credit
+	if receipt.BillID != bill.ID || receipt.OrgID != testOrgID || receipt.Sequence != 1 || receipt.Currency != model.CurrencyUSD {
+		t.Errorf("unexpected receipt header %+v", receipt)
+	}
+	if receipt.Subtotal != 1000+3700 || receipt.CreditTotal != 200 || receipt.FXFees != 74 || receipt.Total != closed.TotalAmount || receipt.Total != 4574 {
+		t.Errorf("expected subtotal 4700, credits 200, fx fees 74 and total 4574, got %+v", receipt)
+	}
+	if !receipt.ClosedAt.Equal(*closed.ClosedAt) || len(receipt.LineItems) != len(closed.LineItems) {
+		t.Errorf("expected the receipt to match the closed bill, got %+v", receipt)
+	}
+
+	// Reopening and closing again issues a second receipt and leaves the first alone
+	svc.ReopenBill(ctx, bill.ID)
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Late fee", Amount: 5.00, Currency: model.CurrencyUSD})
+	clock.Advance(time.Hour)
+	reclosed, err := svc.CloseBill(ctx, bill.ID, nil)
+	if err != nil {
+		t.Fatalf("CloseBill() after reopen error = %v", err)
+	}
+
+	latest, _ := svc.GetReceipt(ctx, bill.ID)
+	if latest.Sequence != 2 || latest.Total != reclosed.TotalAmount || len(latest.LineItems) != 4 || latest.ID == receipt.ID {
+		t.Errorf("expected a second receipt for the new close, got %+v", latest)
+	}
+	receipts, err := svc.ListReceipts(ctx, bill.ID)
+	if err != nil || len(receipts) != 2 {
+		t.Fatalf("expected 2 receipts, got %d, %v", len(receipts), err)
+	}
+	first := receipts[0]
+	if first.ID != receipt.ID || first.Total != 4574 || len(first.LineItems) != 3 || !first.ClosedAt.Equal(receipt.ClosedAt) {
+		t.Errorf("expected the first receipt unchanged by the reopen, got %+v", first)
+	}
+
+	if _, err := svc.GetReceipt(tenant.WithOrgID(context.Background(), "org_other"), bill.ID); billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+		t.Errorf("expected another org not to see the receipt, got %v", err)
+	}
+}
+
+func TestReceiptIsImmutable(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD, Metadata: map[string]string{"orderId": "ord_1"}})
+	svc.CloseBill(ctx, bill.ID, nil)
+
+	receipt, _ := svc.GetReceipt(ctx, bill.ID)
+	receipt.Total = 0
+	receipt.LineItems[0].Description = "Changed"
+	receipt.LineItems[0].Metadata["orderId"] = "changed"
+
+	stored, _ := svc.GetReceipt(ctx, bill.ID)
+	if stored.Total != 1000 || stored.LineItems[0].Description != "Fee" || stored.LineItems[0].Metadata["orderId"] != "ord_1" {
+		t.Errorf("expected the stored receipt unaffected by changes to a copy, got %+v", stored)
+	}
+	if err := svc.receipts.Create(ctx, stored); err == nil {
+		t.Error("expected storing a receipt with an existing ID to fail")
+	}
+}
+
+func TestCloseBillsIssuesReceipts(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	if _, err := svc.CloseBills(ctx, []string{bill.ID}, nil); err != nil {
+		t.Fatalf("CloseBills() error = %v", err)
+	}
+	if receipt, err := svc.GetReceipt(ctx, bill.ID); err != nil || receipt.Total != 1000 {
+		t.Errorf("expected a receipt for the bulk close, got %+v, %v", receipt, err)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"strconv"
+
+	"fees-api/internal/model"
+)
+
+// Reconcile compares a stored bill with the state its billing period workflow
+// accumulated from signals. The two are updated independently, so a missed
+// signal or a change the workflow isn't told about (replacing line items,
+// reopening) shows up as a discrepancy. The stored bill is authoritative.
+func Reconcile(bill *model.Bill, workflowState model.WorkflowBillState) model.ReconciliationReport {
+	billState := model.WorkflowBillState{
+		Status:        string(bill.Status),
+		TotalAmount:   bill.TotalAmount,
+		LineItemCount: len(bill.LineItems),
+	}
+
+	var discrepancies []model.Discrepancy
+	if billState.Status != workflowState.Status {
+		discrepancies = append(discrepancies, model.Discrepancy{
+			Field:    "status",
+			Bill:     billState.Status,
+			Workflow: workflowState.Status,
+		})
+	}
+	if billState.TotalAmount != workflowState.TotalAmount {
+		discrepancies = append(discrepancies, model.Discrepancy{
+			Field:    "totalAmount",
+			Bill:     strconv.FormatInt(billState.TotalAmount, 10),
+			Workflow: strconv.FormatInt(workflowState.TotalAmount, 10),
+		})
+	}
+	if billState.LineItemCount != workflowState.LineItemCount {
+		discrepancies = append(discrepancies, model.Discrepancy{
+			Field:    "lineItemCount",
+			Bill:     strconv.Itoa(billState.LineItemCount),
+			Workflow: strconv.Itoa(workflowState.LineItemCount),
+		})
+	}
+
+	return model.ReconciliationReport{
+		BillID:        bill.ID,
+		InSync:        len(discrepancies) == 0,
+		Bill:          billState,
+		Workflow:      workflowState,
+		Discrepancies: discrepancies,
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"fees-api/internal/model"
+)
+
+func TestReconcile(t *testing.T) {
+	bill := &model.Bill{
+		ID:          "bill_1",
+		Status:      model.BillStatusOpen,
+		Currency:    model.CurrencyUSD,
+		TotalAmount: 4700,
+		LineItems:   []model.LineItem{{ID: "li_1"}, {ID: "li_2"}},
+	}
+
+	tests := []struct {
+		name       string
+		state      model.WorkflowBillState
+		wantFields []string
+	}{
+		{
+			name:  "in sync",
+			state: model.WorkflowBillState{Status: "open", TotalAmount: 4700, LineItemCount: 2},
+		},
+		{
+			name:       "missed add signal",
+			state:      model.WorkflowBillState{Status: "open", TotalAmount: 1000, LineItemCount: 1},
+			wantFields: []string{"totalAmount", "lineItemCount"},
+		},
+		{
+			name:       "workflow failed to close",
+			state:      model.WorkflowBillState{Status: "close-failed", TotalAmount: 4700, LineItemCount: 2},
+			wantFields: []string{"status"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Reconcile(bill, tt.state)
+
+			var fields []string
+			for _, d := range report.Discrepancies {
+				fields = append(fields, d.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("expected discrepancies in %v, got %v", tt.wantFields, fields)
+			}
+			if report.InSync != (len(tt.wantFields) == 0) {
+				t.Errorf("expected InSync %v, got %v", len(tt.wantFields) == 0, report.InSync)
+			}
+		})
+	}
+
+	report := Reconcile(bill, model.WorkflowBillState{Status: "open", TotalAmount: 1000, LineItemCount: 2})
+	want := model.Discrepancy{Field: "totalAmount", Bill: "4700", Workflow: "1000"}
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0] != want {
+		t.Errorf("expected %+v, got %+v", want, report.Discrepancies)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// defaultRecurringIntervalDays is used when a template doesn't specify an interval
+const defaultRecurringIntervalDays = 30
+
+// RecurringService manages recurring bill templates and materializes bills from them
+type RecurringService struct {
+	templates repository.RecurringTemplateRepository
+	bills     *BillingService
+
+	// mu serializes status changes and renewals, which read-modify-write a template
+	mu sync.Mutex
+}
+
+// NewRecurringService creates a new recurring bill service
+func NewRecurringService(templates repository.RecurringTemplateRepository, bills *BillingService) *RecurringService {
+	return &RecurringService{templates: templates, bills: bills}
+}
+
+// CreateTemplate validates and registers a recurring bill template
+func (s *RecurringService) CreateTemplate(ctx context.Context, req *model.CreateRecurringTemplateRequest) (*model.RecurringBillTemplate, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.CustomerID == "" {
+		return nil, fmt.Errorf("customerId is required")
+	}
+	if req.Currency == "" {
+		req.Currency = model.CurrencyUSD
+	}
+	if !req.Currency.IsSupported() {
+		return nil, billingerrors.UnsupportedCurrency(string(req.Currency))
+	}
+	if req.IntervalDays < 0 {
+		return nil, fmt.Errorf("intervalDays must be positive")
+	}
+	if req.IntervalDays == 0 {
+		req.IntervalDays = defaultRecurringIntervalDays
+	}
+	if len(req.LineItems) == 0 {
+		return nil, fmt.Errorf("at least one line item is required")
+	}
+	now := s.bills.clock.Now()
+	for i := range req.LineItems {
+		if _, err := newLineItem(&req.LineItems[i], now); err != nil {
+			return nil, fmt.Errorf("line item %d: %w", i, err)
+		}
+	}
+
+	template := &model.RecurringBillTemplate{
+		ID:           fmt.Sprintf("rec_%d", now.UnixNano()),
+		OrgID:        orgID,
+		CustomerID:   req.CustomerID,
+		Currency:     req.Currency,
+		LineItems:    append([]model.AddLineItemRequest{}, req.LineItems...),
+		IntervalDays: req.IntervalDays,
+		Status:       model.RecurringTemplateActive,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.templates.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// GetTemplate retrieves one of the caller org's recurring templates by ID
+func (s *RecurringService) GetTemplate(ctx context.Context, templateID string) (*model.RecurringBillTemplate, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.templates.Get(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil || template.OrgID != orgID {
+		return nil, billingerrors.RecurringTemplateNotFound(templateID)
+	}
+	return template, nil
+}
+
+// PauseTemplate stops renewals until the template is resumed
+func (s *RecurringService) PauseTemplate(ctx context.Context, templateID string) (*model.RecurringBillTemplate, error) {
+	return s.setStatus(ctx, templateID, model.RecurringTemplatePaused)
+}
+
+// ResumeTemplate restarts renewals for a paused template
+func (s *RecurringService) ResumeTemplate(ctx context.Context, templateID string) (*model.RecurringBillTemplate, error) {
+	return s.setStatus(ctx, templateID, model.RecurringTemplateActive)
+}
+
+// CancelTemplate permanently stops a template. Bills already materialized are unaffected.
+func (s *RecurringService) CancelTemplate(ctx context.Context, templateID string) (*model.RecurringBillTemplate, error) {
+	return s.setStatus(ctx, templateID, model.RecurringTemplateCancelled)
+}
+
+func (s *RecurringService) setStatus(ctx context.Context, templateID string, status model.RecurringTemplateStatus) (*model.RecurringBillTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.Status == model.RecurringTemplateCancelled {
+		return nil, billingerrors.RecurringTemplateCancelled(templateID)
+	}
+
+	template.Status = status
+	template.UpdatedAt = s.bills.clock.Now()
+	if err := s.templates.Update(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Materialize creates the next bill from an active template, with the template's
+// line items. Paused templates are skipped and return a nil bill; cancelled
+// templates return an error.
+func (s *RecurringService) Materialize(ctx context.Context, templateID string) (*model.Bill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	switch template.Status {
+	case model.RecurringTemplateCancelled:
+		return nil, billingerrors.RecurringTemplateCancelled(templateID)
+	case model.RecurringTemplatePaused:
+		return nil, nil
+	}
+
+	bill, err := s.bills.CreateBill(ctx, &model.CreateBillRequest{Currency: template.Currency, BillingPeriodDays: template.IntervalDays})
+	if err != nil {
+		return nil, err
+	}
+	bill, err = s.bills.ReplaceLineItems(ctx, bill.ID, template.LineItems)
+	if err != nil {
+		return nil, err
+	}
+
+	template.LastBillID = bill.ID
+	template.BillCount++
+	template.UpdatedAt = s.bills.clock.Now()
+	if err := s.templates.Update(ctx, template); err != nil {
+		return nil, err
+	}
+	return bill, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+)
+
+func newTestRecurringService(t *testing.T) *RecurringService {
+	return NewRecurringService(repository.NewInMemoryRecurringTemplateRepository(), newTestBillingService(t, newMockBillRepository()))
+}
+
+func TestMaterializeRecurringTemplate(t *testing.T) {
+	ctx := testContext()
+	svc := newTestRecurringService(t)
+
+	template, err := svc.CreateTemplate(ctx, &model.CreateRecurringTemplateRequest{
+		CustomerID: "cust_1",
+		Currency:   model.CurrencyGEL,
+		LineItems: []model.AddLineItemRequest{
+			{Description: "Subscription", Amount: 100.00, Currency: model.CurrencyGEL, Category: model.CategorySubscription},
+			{Description: "Support", Amount: 37.00, Currency: model.CurrencyUSD},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+	if template.IntervalDays != defaultRecurringIntervalDays {
+		t.Errorf("expected default interval %d, got %d", defaultRecurringIntervalDays, template.IntervalDays)
+	}
+
+	bill, err := svc.Materialize(ctx, template.ID)
+	if err != nil {
+		t.Fatalf("Materialize() error = %v", err)
+	}
+	if bill.Currency != model.CurrencyGEL || bill.Status != model.BillStatusOpen {
+		t.Errorf("expected open GEL bill, got %s %s", bill.Status, bill.Currency)
+	}
+	if len(bill.LineItems) != 2 || bill.TotalAmount != 20000 {
+		t.Errorf("expected 2 line items totalling 20000, got %d totalling %d", len(bill.LineItems), bill.TotalAmount)
+	}
+
+	next, _ := svc.Materialize(ctx, template.ID)
+	if next.ID == bill.ID {
+		t.Error("expected each renewal to create a new bill")
+	}
+
+	template, _ = svc.GetTemplate(ctx, template.ID)
+	if template.BillCount != 2 || template.LastBillID != next.ID {
+		t.Errorf("expected 2 bills with last %s, got %d with last %s", next.ID, template.BillCount, template.LastBillID)
+	}
+}
+
+func TestRecurringTemplatePauseAndCancel(t *testing.T) {
+	ctx := testContext()
+	svc := newTestRecurringService(t)
+
+	template, _ := svc.CreateTemplate(ctx, &model.CreateRecurringTemplateRequest{
+		CustomerID: "cust_1",
+		LineItems:  []model.AddLineItemRequest{{Description: "Subscription", Amount: 10.00, Currency: model.CurrencyUSD}},
+	})
+
+	svc.PauseTemplate(ctx, template.ID)
+	if bill, err := svc.Materialize(ctx, template.ID); err != nil || bill != nil {
+		t.Errorf("expected paused template to be skipped, got bill %v err %v", bill, err)
+	}
+
+	svc.ResumeTemplate(ctx, template.ID)
+	if bill, err := svc.Materialize(ctx, template.ID); err != nil || bill == nil {
+		t.Errorf("expected resumed template to materialize, got bill %v err %v", bill, err)
+	}
+
+	svc.CancelTemplate(ctx, template.ID)
+	if _, err := svc.Materialize(ctx, template.ID); err == nil {
+		t.Error("expected error materializing a cancelled template")
+	}
+	if _, err := svc.ResumeTemplate(ctx, template.ID); err == nil {
+		t.Error("expected error resuming a cancelled template")
+	}
+}
+
+func TestCreateRecurringTemplateValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *model.CreateRecurringTemplateRequest
+	}{
+		{name: "missing customer", req: &model.CreateRecurringTemplateRequest{LineItems: []model.AddLineItemRequest{{Description: "Fee", Amount: 1, Currency: model.CurrencyUSD}}}},
+		{name: "no line items", req: &model.CreateRecurringTemplateRequest{CustomerID: "cust_1"}},
+		{name: "invalid line item", req: &model.CreateRecurringTemplateRequest{CustomerID: "cust_1", LineItems: []model.AddLineItemRequest{{Description: "Fee", Amount: -1, Currency: model.CurrencyUSD}}}},
+		{name: "negative interval", req: &model.CreateRecurringTemplateRequest{CustomerID: "cust_1", IntervalDays: -1, LineItems: []model.AddLineItemRequest{{Description: "Fee", Amount: 1, Currency: model.CurrencyUSD}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newTestRecurringService(t).CreateTemplate(testContext(), tt.req); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// BillingScheduleService manages per-customer schedules that open a billing period
+// on the 1st of every month. The schedule itself runs in Temporal; this service
+// owns its state and starts each period when the schedule fires.
+type BillingScheduleService struct {
+	schedules repository.BillingScheduleRepository
+	bills     *BillingService
+
+	// mu serializes status changes and period starts, which read-modify-write a schedule
+	mu sync.Mutex
+}
+
+// NewBillingScheduleService creates a new billing schedule service
+func NewBillingScheduleService(schedules repository.BillingScheduleRepository, bills *BillingService) *BillingScheduleService {
+	return &BillingScheduleService{schedules: schedules, bills: bills}
+}
+
+// CreateSchedule registers a monthly billing schedule for a customer. Each customer
+// of an org may have only one.
+func (s *BillingScheduleService) CreateSchedule(ctx context.Context, req *model.CreateBillingScheduleRequest) (*model.BillingSchedule, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.CustomerID == "" {
+		return nil, fmt.Errorf("customerId is required")
+	}
+	if req.Currency == "" {
+		req.Currency = model.CurrencyUSD
+	}
+	if !req.Currency.IsSupported() {
+		return nil, billingerrors.UnsupportedCurrency(string(req.Currency))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.schedules.GetByCustomer(ctx, orgID, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, billingerrors.BillingScheduleExists(req.CustomerID, existing.ID)
+	}
+
+	now := s.bills.clock.Now()
+	schedule := &model.BillingSchedule{
+		ID:         fmt.Sprintf("sch_%d", now.UnixNano()),
+		OrgID:      orgID,
+		CustomerID: req.CustomerID,
+		Currency:   req.Currency,
+		Status:     model.BillingScheduleActive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.schedules.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetSchedule retrieves one of the caller org's billing schedules by ID
+func (s *BillingScheduleService) GetSchedule(ctx context.Context, scheduleID string) (*model.BillingSchedule, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := s.schedules.Get(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil || schedule.OrgID != orgID {
+		return nil, billingerrors.BillingScheduleNotFound(scheduleID)
+	}
+	return schedule, nil
+}
+
+// PauseSchedule stops period starts until the schedule is resumed
+func (s *BillingScheduleService) PauseSchedule(ctx context.Context, scheduleID string) (*model.BillingSchedule, error) {
+	return s.setStatus(ctx, scheduleID, model.BillingSchedulePaused)
+}
+
+// ResumeSchedule restarts period starts for a paused schedule
+func (s *BillingScheduleService) ResumeSchedule(ctx context.Context, scheduleID string) (*model.BillingSchedule, error) {
+	return s.setStatus(ctx, scheduleID, model.BillingScheduleActive)
+}
+
+// DeleteSchedule removes a schedule. Bills it already opened are unaffected.
+func (s *BillingScheduleService) DeleteSchedule(ctx context.Context, scheduleID string) (*model.BillingSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, err := s.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.schedules.Delete(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *BillingScheduleService) setStatus(ctx context.Context, scheduleID string, status model.BillingScheduleStatus) (*model.BillingSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, err := s.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Status = status
+	schedule.UpdatedAt = s.bills.clock.Now()
+	if err := s.schedules.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// StartPeriod opens the customer's bill for the current calendar month (UTC), with
+// its billing period ending on the 1st of the next. The previous period ends at
+// the same instant, so its bill is usually still closing (or waiting on approval)
+// when the schedule fires; that doesn't hold up the new period. Paused schedules
+// are skipped, and so is a start for a month that already has its bill, e.g. a
+// retried run, or one the previous bill's period still covers. Both return a nil
+// bill.
+func (s *BillingScheduleService) StartPeriod(ctx context.Context, scheduleID string) (*model.Bill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, err := s.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status == model.BillingSchedulePaused {
+		return nil, nil
+	}
+
+	now := s.bills.clock.Now().UTC()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	if schedule.LastBillID != "" {
+		previous, err := s.bills.GetBill(ctx, schedule.LastBillID, false)
+		if err != nil && billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+			return nil, err
+		}
+		if previous != nil && previous.PeriodEnd != nil && previous.PeriodEnd.After(periodStart) {
+			s.bills.logger.Info("scheduled period skipped, the month already has its bill",
+				"schedule_id", scheduleID, "bill_id", previous.ID)
+			return nil, nil
+		}
+	}
+
+	bill, err := s.bills.CreateBill(ctx, &model.CreateBillRequest{
+		Currency:    schedule.Currency,
+		PeriodStart: &periodStart,
+		PeriodEnd:   &periodEnd,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.LastBillID = bill.ID
+	schedule.BillCount++
+	schedule.UpdatedAt = now
+	if err := s.schedules.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return bill, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestScheduledBillingPeriods(t *testing.T) {
+	ctx := testContext()
+	clock := newFakeClock(time.Date(2024, 3, 1, 0, 0, 5, 0, time.UTC))
+	bills := newTestBillingService(t, newMockBillRepository(), WithClock(clock))
+	svc := NewBillingScheduleService(repository.NewInMemoryBillingScheduleRepository(), bills)
+
+	schedule, err := svc.CreateSchedule(ctx, &model.CreateBillingScheduleRequest{CustomerID: "cust_1", Currency: model.CurrencyGEL})
+	if err != nil {
+		t.Fatalf("CreateSchedule() error = %v", err)
+	}
+
+	march, err := svc.StartPeriod(ctx, schedule.ID)
+	if err != nil {
+		t.Fatalf("StartPeriod() error = %v", err)
+	}
+	wantStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	if march.Currency != model.CurrencyGEL || !march.PeriodStart.Equal(wantStart) || !march.PeriodEnd.Equal(wantEnd) {
+		t.Errorf("expected a GEL bill for %s..%s, got %s %v..%v", wantStart, wantEnd, march.Currency, march.PeriodStart, march.PeriodEnd)
+	}
+
+	// A retried run in the same month doesn't open a second bill
+	if bill, err := svc.StartPeriod(ctx, schedule.ID); err != nil || bill != nil {
+		t.Fatalf("expected a repeated start in March to be skipped, got %v, %v", bill, err)
+	}
+
+	// The schedule fires as March's period ends, while its bill is still closing
+	clock.Advance(31 * 24 * time.Hour)
+	april, err := svc.StartPeriod(ctx, schedule.ID)
+	if err != nil || april == nil {
+		t.Fatalf("expected April's period to start while %s is closing, got %v, %v", march.ID, april, err)
+	}
+	if !april.PeriodStart.Equal(wantEnd) {
+		t.Errorf("expected the April period to start %s, got %v", wantEnd, april.PeriodStart)
+	}
+	if previous, _ := bills.GetBill(ctx, march.ID, false); previous.Status != model.BillStatusOpen {
+		t.Errorf("expected March's bill left to its own close, got %s", previous.Status)
+	}
+
+	schedule, _ = svc.GetSchedule(ctx, schedule.ID)
+	if schedule.BillCount != 2 || schedule.LastBillID != april.ID {
+		t.Errorf("expected 2 periods with last bill %s, got %d with %s", april.ID, schedule.BillCount, schedule.LastBillID)
+	}
+}
+
+func TestBillingScheduleLifecycle(t *testing.T) {
+	ctx := testContext()
+	svc := NewBillingScheduleService(repository.NewInMemoryBillingScheduleRepository(), newTestBillingService(t, newMockBillRepository()))
+
+	schedule, _ := svc.CreateSchedule(ctx, &model.CreateBillingScheduleRequest{CustomerID: "cust_1"})
+	if _, err := svc.CreateSchedule(ctx, &model.CreateBillingScheduleRequest{CustomerID: "cust_1"}); billingerrors.CodeOf(err) != billingerrors.CodeConflict {
+		t.Errorf("expected a conflict scheduling the same customer twice, got %v", err)
+	}
+
+	svc.PauseSchedule(ctx, schedule.ID)
+	if bill, err := svc.StartPeriod(ctx, schedule.ID); err != nil || bill != nil {
+		t.Errorf("expected a paused schedule to skip, got %v, %v", bill, err)
+	}
+	svc.ResumeSchedule(ctx, schedule.ID)
+	if bill, err := svc.StartPeriod(ctx, schedule.ID); err != nil || bill == nil {
+		t.Errorf("expected a resumed schedule to start a period, got %v, %v", bill, err)
+	}
+
+	if _, err := svc.DeleteSchedule(ctx, schedule.ID); err != nil {
+		t.Fatalf("DeleteSchedule() error = %v", err)
+	}
+	if _, err := svc.GetSchedule(ctx, schedule.ID); billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+		t.Errorf("expected the deleted schedule to be gone, got %v", err)
+	}
+}
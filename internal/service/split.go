@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// SplitBill moves the listed line items off an open bill onto a new open bill with
+// the same currency, time zone and billing period, e.g. to invoice disputed charges
+// separately. Both totals are recomputed from the items' frozen conversions, and
+// both bills are written in one transaction. It returns the source bill and the new
+// one; each gets a split event naming the other.
+func (s *BillingService) SplitBill(ctx context.Context, billID string, lineItemIDs []string) (*model.Bill, *model.Bill, error) {
+	if err := (model.SplitBillRequest{LineItemIDs: lineItemIDs}).Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	var source, split *model.Bill
+	err := s.repo.WithTransaction(ctx, func(tx repository.BillRepository) error {
+		var err error
+		if source, err = loadBillFor(ctx, tx, billID, OpSplit); err != nil {
+			return err
+		}
+
+		moving := make(map[string]bool, len(lineItemIDs))
+		for _, id := range lineItemIDs {
+			moving[id] = true
+		}
+		kept := make([]model.LineItem, 0, len(source.LineItems))
+		var moved []model.LineItem
+		for _, item := range source.LineItems {
+			if moving[item.ID] {
+				moved = append(moved, item)
+				delete(moving, item.ID)
+			} else {
+				kept = append(kept, item)
+			}
+		}
+		for _, id := range lineItemIDs {
+			if moving[id] {
+				return billingerrors.LineItemNotFound(billID, id)
+			}
+		}
+
+		split = &model.Bill{
+			ID:        s.ids.NewBillID(),
+			OrgID:     source.OrgID,
+			Status:    model.BillStatusOpen,
+			Currency:  source.Currency,
+			Timezone:  source.Timezone,
+			LineItems: []model.LineItem{},
+			CreatedAt: s.clock.Now(),
+			Version:   1,
+		}
+		if source.PeriodStart != nil && source.PeriodEnd != nil {
+			start, end := *source.PeriodStart, *source.PeriodEnd
+			split.PeriodStart, split.PeriodEnd = &start, &end
+		}
+		for _, item := range moved {
+			split.AppendLineItem(item)
+		}
+		source.LineItems = kept
+
+		for _, bill := range []*model.Bill{source, split} {
+			if bill.TotalAmount, err = s.sumLineItems(bill); err != nil {
+				return err
+			}
+			bill.FXFees = sumFXFees(bill.LineItems)
+			if s.noNegativeTotal && bill.TotalAmount < 0 {
+				return billingerrors.CreditExceedsTotal(bill.ID)
+			}
+		}
+
+		if err := saveBill(ctx, tx, source); err != nil {
+			return err
+		}
+		return tx.Create(ctx, split)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("split bill: %w", err)
+	}
+
+	s.logger.Info("bill split", "bill_id", source.ID, "split_bill_id", split.ID, "line_items", len(split.LineItems),
+		"total", source.TotalAmount, "split_total", split.TotalAmount)
+	s.metrics.IncBillCreated()
+	s.publishRelated(ctx, events.EventBillSplit, source, split.ID)
+	s.publishRelated(ctx, events.EventBillSplit, split, source.ID)
+
+	return source, split, nil
+}
+
+// publishRelated publishes an event for a change that spanned several bills,
+// naming the others
+func (s *BillingService) publishRelated(ctx context.Context, eventType events.EventType, bill *model.Bill, related ...string) {
+	event := events.NewBillEvent(eventType, bill)
+	event.RelatedBillIDs = related
+	s.publish(ctx, event)
+}
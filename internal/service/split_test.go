@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func TestSplitBill(t *testing.T) {
+	ctx := testContext()
+	topic := events.NewTopic()
+	var published []events.BillEvent
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		published = append(published, event)
+		return nil
+	})
+	rates := NewStaticRateProvider(map[model.Currency]float64{
+		model.CurrencyGEL: 0.37,
+		model.CurrencyUSD: 1.0,
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithRateProvider(rates), WithPublisher(topic))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD, Timezone: "Asia/Tbilisi"})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Setup", Amount: 10.00, Currency: model.CurrencyUSD})
+	svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Disputed", Amount: 25.00, Currency: model.CurrencyUSD})
+	bill, _ = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Disputed FX", Amount: 100.00, Currency: model.CurrencyGEL})
+	disputed := []string{bill.LineItems[1].ID, bill.LineItems[2].ID}
+
+	// The rate moves after the items were added; moved items keep their frozen conversion
+	rates.SetRate(model.CurrencyGEL, 0.5)
+	published = nil
+
+	source, split, err := svc.SplitBill(ctx, bill.ID, disputed)
+	if err != nil {
+		t.Fatalf("SplitBill() error = %v", err)
+	}
+
+	if len(source.LineItems) != 1 || source.LineItems[0].Description != "Setup" || source.TotalAmount != 1000 {
+		t.Errorf("expected the source to keep only Setup at 1000, got %d items at %d", len(source.LineItems), source.TotalAmount)
+	}
+	var movedIDs []string
+	for _, item := range split.LineItems {
+		movedIDs = append(movedIDs, item.ID)
+	}
+	if !reflect.DeepEqual(movedIDs, disputed) {
+		t.Errorf("expected the new bill to hold %v, got %v", disputed, movedIDs)
+	}
+	if split.TotalAmount != 2500+3700 {
+		t.Errorf("expected the new bill's total 6200, got %d", split.TotalAmount)
+	}
+	if split.ID == bill.ID || split.Status != model.BillStatusOpen || split.Currency != model.CurrencyUSD || split.Timezone != "Asia/Tbilisi" || split.OrgID != testOrgID {
+		t.Errorf("expected a new open USD bill in the same org and zone, got %+v", split)
+	}
+	if split.PeriodEnd == nil || !split.PeriodEnd.Equal(*source.PeriodEnd) {
+		t.Errorf("expected the new bill to share the billing period, got %v", split.PeriodEnd)
+	}
+
+	stored, _ := svc.GetBill(ctx, bill.ID, false)
+	if !reflect.DeepEqual(stored, source) {
+		t.Errorf("expected the stored source to match the returned one")
+	}
+	if storedSplit, err := svc.GetBill(ctx, split.ID, false); err != nil || storedSplit.TotalAmount != split.TotalAmount {
+		t.Errorf("expected the new bill to be stored, got %v", err)
+	}
+
+	if len(published) != 2 {
+		t.Fatalf("expected 2 split events, got %d", len(published))
+	}
+	for i, want := range [][2]string{{bill.ID, split.ID}, {split.ID, bill.ID}} {
+		event := published[i]
+		if event.Type != events.EventBillSplit || event.BillID != want[0] || !reflect.DeepEqual(event.RelatedBillIDs, []string{want[1]}) {
+			t.Errorf("event %d: expected split of %s related to %s, got %s of %s related to %v",
+				i, want[0], want[1], event.Type, event.BillID, event.RelatedBillIDs)
+		}
+	}
+}
+
+func TestSplitBillRejects(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	open, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	open, _ = svc.AddLineItem(ctx, open.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	closed, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	closed, _ = svc.AddLineItem(ctx, closed.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD})
+	svc.CloseBill(ctx, closed.ID, nil)
+
+	tests := []struct {
+		name     string
+		billID   string
+		itemIDs  []string
+		wantCode billingerrors.Code
+	}{
+		{name: "no line items", billID: open.ID, itemIDs: nil, wantCode: billingerrors.CodeInvalidArgument},
+		{name: "duplicate line item", billID: open.ID, itemIDs: []string{open.LineItems[0].ID, open.LineItems[0].ID}, wantCode: billingerrors.CodeInvalidArgument},
+		{name: "unknown line item", billID: open.ID, itemIDs: []string{"item_missing"}, wantCode: billingerrors.CodeNotFound},
+		{name: "unknown bill", billID: "bill_missing", itemIDs: []string{"item_1"}, wantCode: billingerrors.CodeNotFound},
+		{name: "closed bill", billID: closed.ID, itemIDs: []string{closed.LineItems[0].ID}, wantCode: billingerrors.CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := svc.SplitBill(ctx, tt.billID, tt.itemIDs)
+			if err == nil || billingerrors.CodeOf(err) != tt.wantCode {
+				t.Errorf("expected a %s error, got %v", tt.wantCode, err)
+			}
+		})
+	}
+
+	// A rejected split leaves the source as it was
+	if stored, _ := svc.GetBill(ctx, open.ID, false); len(stored.LineItems) != 1 || stored.TotalAmount != 1000 {
+		t.Errorf("expected the source to be unchanged, got %d items at %d", len(stored.LineItems), stored.TotalAmount)
+	}
+}
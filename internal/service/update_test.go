@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestUpdateLineItem(t *testing.T) {
+	ctx := testContext()
+	svc := newTestBillingService(t, newMockBillRepository())
+
+	newBill := func(t *testing.T) (*model.Bill, model.LineItem) {
+		t.Helper()
+		bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Base", Amount: 5.00, Currency: model.CurrencyUSD})
+		bill, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{
+			Description: "Service fee",
+			Amount:      10.00,
+			Currency:    model.CurrencyUSD,
+			Category:    model.CategoryProcessing,
+			Metadata:    map[string]string{"orderId": "ord_1"},
+		})
+		if err != nil {
+			t.Fatalf("AddLineItem() error = %v", err)
+		}
+		return bill, bill.LineItems[1]
+	}
+
+	t.Run("description only leaves the total", func(t *testing.T) {
+		bill, item := newBill(t)
+		updated, err := svc.UpdateLineItem(ctx, bill.ID, item.ID, &model.UpdateLineItemRequest{Description: ptr("Card processing")})
+		if err != nil {
+			t.Fatalf("UpdateLineItem() error = %v", err)
+		}
+		got := updated.LineItems[1]
+		if got.Description != "Card processing" {
+			t.Errorf("expected the new description, got %q", got.Description)
+		}
+		if got.Amount != 1000 || got.Category != model.CategoryProcessing || got.Metadata["orderId"] != "ord_1" {
+			t.Errorf("expected omitted fields unchanged, got %+v", got)
+		}
+		if updated.TotalAmount != bill.TotalAmount || got.RateAsOf == nil || !got.RateAsOf.Equal(*item.RateAsOf) {
+			t.Errorf("expected the total and frozen rate unchanged, got total %d", updated.TotalAmount)
+		}
+	})
+
+	t.Run("amount only recomputes the total", func(t *testing.T) {
+		bill, item := newBill(t)
+		updated, err := svc.UpdateLineItem(ctx, bill.ID, item.ID, &model.UpdateLineItemRequest{Amount: ptr(12.50)})
+		if err != nil {
+			t.Fatalf("UpdateLineItem() error = %v", err)
+		}
+		got := updated.LineItems[1]
+		if got.Amount != 1250 || got.ConvertedAmount != 1250 || got.Description != "Service fee" {
+			t.Errorf("expected the amount changed alone, got %+v", got)
+		}
+		if updated.TotalAmount != 500+1250 {
+			t.Errorf("expected total 1750, got %d", updated.TotalAmount)
+		}
+	})
+
+	t.Run("repricing drops an approval", func(t *testing.T) {
+		// The in-memory repository hands out copies, so a rejected update leaves
+		// the stored item alone
+		capped := newTestBillingService(t, repository.NewInMemoryBillRepository(), WithMaxLineItemAmount(100000))
+		bill, _ := capped.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		bill, err := capped.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{
+			Description: "Setup", Amount: 5000.00, Currency: model.CurrencyUSD, Approved: true, ApproverID: "mgr_1",
+		})
+		if err != nil || bill.LineItems[0].ApprovedBy != "mgr_1" {
+			t.Fatalf("expected an approved item over the cap, got %v", err)
+		}
+		item := bill.LineItems[0]
+
+		renamed, _ := capped.UpdateLineItem(ctx, bill.ID, item.ID, &model.UpdateLineItemRequest{Description: ptr("Onboarding")})
+		if renamed.LineItems[0].ApprovedBy != "mgr_1" {
+			t.Errorf("expected the approval kept when the amount is unchanged, got %q", renamed.LineItems[0].ApprovedBy)
+		}
+		updated, err := capped.UpdateLineItem(ctx, bill.ID, item.ID, &model.UpdateLineItemRequest{Amount: ptr(50.00)})
+		if err != nil {
+			t.Fatalf("UpdateLineItem() error = %v", err)
+		}
+		if got := updated.LineItems[0].ApprovedBy; got != "" {
+			t.Errorf("expected the approval cleared for the new amount, got %q", got)
+		}
+
+		if _, err := capped.UpdateLineItem(ctx, bill.ID, item.ID, &model.UpdateLineItemRequest{Amount: ptr(6000.00)}); billingerrors.CodeOf(err) != billingerrors.CodeInvalidArgument {
+			t.Errorf("expected a new amount over the cap rejected without approval, got %v", err)
+		}
+		repriced, err := capped.UpdateLineItem(ctx, bill.ID, item.ID, &model.UpdateLineItemRequest{Amount: ptr(6000.00), Approved: true, ApproverID: "mgr_2"})
+		if err != nil {
+			t.Fatalf("UpdateLineItem() with approval error = %v", err)
+		}
+		if got := repriced.LineItems[0].ApprovedBy; got != "mgr_2" {
+			t.Errorf("expected the new amount approved by mgr_2, got %q", got)
+		}
+	})
+
+	t.Run("currency converts again", func(t *testing.T) {
+		bill, item := newBill(t)
+		updated, err := svc.UpdateLineItem(ctx, bill.ID, item.ID, &model.UpdateLineItemRequest{Currency: ptr(model.CurrencyGEL)})
+		if err != nil {
+			t.Fatalf("UpdateLineItem() error = %v", err)
+		}
+		got := updated.LineItems[1]
+		if got.Currency != model.CurrencyGEL || got.ConvertedAmount != 370 {
+			t.Errorf("expected 10.00 GEL converted to 370 cents, got %s at %d", got.Currency, got.ConvertedAmount)
+		}
+		if updated.TotalAmount != 500+370 {
+			t.Errorf("expected total 870, got %d", updated.TotalAmount)
+		}
+	})
+
+	t.Run("empty metadata clears it", func(t *testing.T) {
+		bill, item := newBill(t)
+		updated, err := svc.UpdateLineItem(ctx, bill.ID, item.ID, &model.UpdateLineItemRequest{Metadata: ptr(map[string]string{})})
+		if err != nil {
+			t.Fatalf("UpdateLineItem() error = %v", err)
+		}
+		if updated.LineItems[1].Metadata != nil {
+			t.Errorf("expected metadata cleared, got %v", updated.LineItems[1].Metadata)
+		}
+	})
+
+	t.Run("rejects", func(t *testing.T) {
+		bill, item := newBill(t)
+		closed, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+		closed, _ = svc.AddLineItem(ctx, closed.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: model.CurrencyUSD})
+		svc.CloseBill(ctx, closed.ID, nil)
+
+		tests := []struct {
+			name       string
+			billID     string
+			lineItemID string
+			req        *model.UpdateLineItemRequest
+			wantCode   billingerrors.Code
+		}{
+			{name: "unknown line item", billID: bill.ID, lineItemID: "li_missing", req: &model.UpdateLineItemRequest{Description: ptr("x")}, wantCode: billingerrors.CodeNotFound},
+			{name: "empty description", billID: bill.ID, lineItemID: item.ID, req: &model.UpdateLineItemRequest{Description: ptr("")}, wantCode: billingerrors.CodeInvalidArgument},
+			{name: "zero amount", billID: bill.ID, lineItemID: item.ID, req: &model.UpdateLineItemRequest{Amount: ptr(0.0)}, wantCode: billingerrors.CodeInvalidArgument},
+			{name: "closed bill", billID: closed.ID, lineItemID: closed.LineItems[0].ID, req: &model.UpdateLineItemRequest{Amount: ptr(2.0)}, wantCode: billingerrors.CodeUnknown},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := svc.UpdateLineItem(ctx, tt.billID, tt.lineItemID, tt.req)
+				if err == nil || billingerrors.CodeOf(err) != tt.wantCode {
+					t.Errorf("expected a %s error, got %v", tt.wantCode, err)
+				}
+			})
+		}
+	})
+}
+
+func TestUpdateLineItemPublishesEvent(t *testing.T) {
+	ctx := testContext()
+	topic := events.NewTopic()
+	var published []events.BillEvent
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		published = append(published, event)
+		return nil
+	})
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(topic))
+
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	bill, _ = svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 1.00, Currency: model.CurrencyUSD})
+	published = nil
+
+	if _, err := svc.UpdateLineItem(ctx, bill.ID, bill.LineItems[0].ID, &model.UpdateLineItemRequest{Amount: ptr(2.00)}); err != nil {
+		t.Fatalf("UpdateLineItem() error = %v", err)
+	}
+	if len(published) != 1 || published[0].Type != events.EventLineItemUpdated || published[0].LineItemID != bill.LineItems[0].ID {
+		t.Errorf("expected one line_item_updated event for %s, got %+v", bill.LineItems[0].ID, published)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// Bounds on how long WatchBill waits for a change
+const (
+	defaultWatchTimeout = 30 * time.Second
+	maxWatchTimeout     = 60 * time.Second
+)
+
+// BillWatcher wakes WatchBill calls waiting on a bill when it changes. It's a bill
+// event subscriber: it must be subscribed to the topic the service publishes to.
+type BillWatcher struct {
+	mu      sync.Mutex
+	changed map[string]chan struct{} // closed on the bill's next event
+}
+
+// NewBillWatcher creates a watcher with no one waiting
+func NewBillWatcher() *BillWatcher {
+	return &BillWatcher{changed: make(map[string]chan struct{})}
+}
+
+// HandleEvent is a bill event subscriber that wakes everyone waiting on the bill
+func (w *BillWatcher) HandleEvent(ctx context.Context, event events.BillEvent) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if changed, ok := w.changed[event.BillID]; ok {
+		close(changed)
+		delete(w.changed, event.BillID)
+	}
+	return nil
+}
+
+// next returns a channel closed on the bill's next event
+func (w *BillWatcher) next(billID string) <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	changed, ok := w.changed[billID]
+	if !ok {
+		changed = make(chan struct{})
+		w.changed[billID] = changed
+	}
+	return changed
+}
+
+// WatchBill waits for the bill to move past sinceVersion and returns it as it then
+// is. It returns at once if the bill already has, and nil once timeout passes with
+// no change. A timeout of zero or less means the default of 30s; longer than 60s is
+// capped at 60s. It wakes on the bill's events, so needs WithBillWatcher.
+func (s *BillingService) WatchBill(ctx context.Context, billID string, sinceVersion int64, timeout time.Duration) (*model.Bill, error) {
+	if s.watcher == nil {
+		return nil, billingerrors.Unavailable("watching bills isn't configured")
+	}
+	if timeout <= 0 {
+		timeout = defaultWatchTimeout
+	}
+	if timeout > maxWatchTimeout {
+		timeout = maxWatchTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		// Wait on the next event before reading, so a change committed in between
+		// still wakes us
+		changed := s.watcher.next(billID)
+		bill, err := s.GetBill(ctx, billID, false)
+		if err != nil {
+			return nil, err
+		}
+		if bill.Version > sinceVersion {
+			return bill, nil
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// newWatchedBillingService creates a service whose watcher is subscribed to the
+// topic it publishes to
+func newWatchedBillingService(t *testing.T) *BillingService {
+	t.Helper()
+	topic := events.NewTopic()
+	watcher := NewBillWatcher()
+	topic.Subscribe(watcher.HandleEvent)
+	return newTestBillingService(t, repository.NewInMemoryBillRepository(), WithPublisher(topic), WithBillWatcher(watcher))
+}
+
+func TestWatchBillWakesOnChange(t *testing.T) {
+	ctx := testContext()
+	svc := newWatchedBillingService(t)
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if bill.Version != 1 {
+		t.Fatalf("expected a new bill at version 1, got %d", bill.Version)
+	}
+
+	type watchResult struct {
+		bill *model.Bill
+		err  error
+	}
+	done := make(chan watchResult, 1)
+	go func() {
+		changed, err := svc.WatchBill(ctx, bill.ID, bill.Version, 5*time.Second)
+		done <- watchResult{changed, err}
+	}()
+
+	// Let the watcher start waiting; it catches the change either way
+	time.Sleep(20 * time.Millisecond)
+	if _, err := svc.AddLineItem(ctx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00}); err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+
+	select {
+	case result := <-done:
+		if result.err != nil {
+			t.Fatalf("WatchBill() error = %v", result.err)
+		}
+		if result.bill == nil || result.bill.Version != 2 || len(result.bill.LineItems) != 1 {
+			t.Errorf("expected the bill at version 2 with its line item, got %+v", result.bill)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the change to wake the watcher before its timeout")
+	}
+
+	// Watching from an older version returns the current bill at once
+	current, err := svc.WatchBill(ctx, bill.ID, 1, time.Minute)
+	if err != nil || current == nil || current.Version != 2 {
+		t.Errorf("expected the bill at version 2 straight away, got %+v, %v", current, err)
+	}
+}
+
+func TestWatchBillTimesOut(t *testing.T) {
+	ctx := testContext()
+	svc := newWatchedBillingService(t)
+	bill, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+
+	// Another bill changing doesn't wake the watch
+	other, _ := svc.CreateBill(ctx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	go svc.AddLineItem(ctx, other.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00})
+
+	start := time.Now()
+	changed, err := svc.WatchBill(ctx, bill.ID, bill.Version, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchBill() error = %v", err)
+	}
+	if changed != nil {
+		t.Errorf("expected no change, got version %d", changed.Version)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the watch to wait out its timeout, returned after %v", elapsed)
+	}
+
+	if _, err := svc.WatchBill(ctx, "bill_missing", 0, time.Second); billingerrors.CodeOf(err) != billingerrors.CodeNotFound {
+		t.Errorf("expected not found watching a missing bill, got %v", err)
+	}
+
+	unwatched := newTestBillingService(t, newMockBillRepository())
+	if _, err := unwatched.WatchBill(ctx, bill.ID, 0, time.Second); billingerrors.CodeOf(err) != billingerrors.CodeUnavailable {
+		t.Errorf("expected unavailable without a watcher, got %v", err)
+	}
+}
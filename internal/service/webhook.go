@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/lifecycle"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+	billingerrors "fees-api/pkg/errors"
+)
+
+// Webhook delivery headers
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+)
+
+// WebhookConfig configures webhook delivery retries
+type WebhookConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	Client         *http.Client
+}
+
+// DefaultWebhookConfig returns the delivery settings used in production
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		Client:         &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WebhookService manages webhook endpoints and delivers bill events to them
+type WebhookService struct {
+	repo   repository.WebhookRepository
+	config WebhookConfig
+
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+	deadLetters []model.WebhookDeadLetter
+	closed      bool // set by Shutdown; no new deliveries start after it
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo repository.WebhookRepository, config WebhookConfig) *WebhookService {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	return &WebhookService{repo: repo, config: config}
+}
+
+// Register registers a new webhook endpoint
+func (s *WebhookService) Register(ctx context.Context, req *model.RegisterWebhookRequest) (*model.WebhookEndpoint, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	if req.Secret == "" {
+		return nil, fmt.Errorf("secret is required")
+	}
+	for _, eventType := range req.EventTypes {
+		if !events.EventType(eventType).IsKnown() {
+			return nil, billingerrors.UnsupportedEventType(eventType)
+		}
+	}
+
+	endpoint := &model.WebhookEndpoint{
+		ID:         fmt.Sprintf("wh_%d", time.Now().UnixNano()),
+		OrgID:      orgID,
+		URL:        req.URL,
+		EventTypes: append([]string{}, req.EventTypes...),
+		Secret:     req.Secret,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err := s.repo.Create(endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// List lists the caller org's webhook endpoints
+func (s *WebhookService) List(ctx context.Context) ([]model.WebhookEndpoint, error) {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.WebhookEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if endpoint.OrgID == orgID {
+			result = append(result, endpoint)
+		}
+	}
+	return result, nil
+}
+
+// Delete removes one of the caller org's webhook endpoints
+func (s *WebhookService) Delete(ctx context.Context, webhookID string) error {
+	orgID, err := callerOrg(ctx)
+	if err != nil {
+		return err
+	}
+	endpoint, err := s.repo.Get(webhookID)
+	if err != nil {
+		return err
+	}
+	if endpoint == nil || endpoint.OrgID != orgID {
+		return billingerrors.WebhookNotFound(webhookID)
+	}
+
+	deleted, err := s.repo.Delete(webhookID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return billingerrors.WebhookNotFound(webhookID)
+	}
+	return nil
+}
+
+// HandleEvent is a bill event subscriber that delivers the event to every
+// matching endpoint of the bill's org. Deliveries run in the background; use Wait to block
+// until they finish. Events arriving after Shutdown are rejected permanently.
+func (s *WebhookService) HandleEvent(ctx context.Context, event events.BillEvent) error {
+	endpoints, err := s.repo.List()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %v", err)
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.OrgID != event.Bill.OrgID || !subscribesTo(endpoint, event.Type) {
+			continue
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return events.Permanent(fmt.Errorf("webhook service is shut down"))
+		}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go func(endpoint model.WebhookEndpoint) {
+			defer s.wg.Done()
+			s.deliver(endpoint, event, body)
+		}(endpoint)
+	}
+
+	return nil
+}
+
+// Wait blocks until all in-flight deliveries have finished
+func (s *WebhookService) Wait() {
+	s.wg.Wait()
+}
+
+// Shutdown stops new deliveries from starting and waits, up to ctx's deadline, for
+// in-flight deliveries and their retries to finish
+func (s *WebhookService) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	return lifecycle.Wait(ctx, &s.wg)
+}
+
+// DeadLetters returns the deliveries that permanently failed
+func (s *WebhookService) DeadLetters() []model.WebhookDeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]model.WebhookDeadLetter{}, s.deadLetters...)
+}
+
+// deliver posts the payload, retrying with exponential backoff until it succeeds
+// or the maximum number of attempts is reached
+func (s *WebhookService) deliver(endpoint model.WebhookEndpoint, event events.BillEvent, body []byte) {
+	backoff := s.config.InitialBackoff
+	var lastErr error
+
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		lastErr = s.post(endpoint, event, body)
+		if lastErr == nil {
+			return
+		}
+		if attempt < s.config.MaxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters = append(s.deadLetters, model.WebhookDeadLetter{
+		WebhookID: endpoint.ID,
+		EventType: string(event.Type),
+		BillID:    event.BillID,
+		Attempts:  s.config.MaxAttempts,
+		LastError: lastErr.Error(),
+		FailedAt:  time.Now().UTC(),
+	})
+}
+
+// post performs a single signed delivery attempt
+func (s *WebhookService) post(endpoint model.WebhookEndpoint, event events.BillEvent, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(event.Type))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, body))
+
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookPayload computes the hex-encoded HMAC-SHA256 signature of the body
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// subscribesTo reports whether the endpoint wants events of the given type
+func subscribesTo(endpoint model.WebhookEndpoint, eventType events.EventType) bool {
+	if len(endpoint.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range endpoint.EventTypes {
+		if events.EventType(t) == eventType {
+			return true
+		}
+	}
+	return false
+}
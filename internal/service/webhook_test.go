@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/lifecycle"
+	"fees-api/internal/model"
+	"fees-api/internal/repository"
+)
+
+func newTestWebhookService(maxAttempts int) *WebhookService {
+	return NewWebhookService(repository.NewInMemoryWebhookRepository(), WebhookConfig{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+	})
+}
+
+func TestRegisterWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *model.RegisterWebhookRequest
+		wantErr bool
+	}{
+		{
+			name:    "registers valid webhook",
+			req:     &model.RegisterWebhookRequest{URL: "https://example.com/hook", Secret: "s3cret", EventTypes: []string{"closed"}},
+			wantErr: false,
+		},
+		{
+			name:    "rejects relative url",
+			req:     &model.RegisterWebhookRequest{URL: "/hook", Secret: "s3cret"},
+			wantErr: true,
+		},
+		{
+			name:    "rejects missing secret",
+			req:     &model.RegisterWebhookRequest{URL: "https://example.com/hook"},
+			wantErr: true,
+		},
+		{
+			name:    "rejects unknown event type",
+			req:     &model.RegisterWebhookRequest{URL: "https://example.com/hook", Secret: "s3cret", EventTypes: []string{"exploded"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestWebhookService(1)
+			_, err := svc.Register(testContext(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListAndDeleteWebhooks(t *testing.T) {
+	svc := newTestWebhookService(1)
+	endpoint, err := svc.Register(testContext(), &model.RegisterWebhookRequest{URL: "https://example.com/hook", Secret: "s3cret"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	endpoints, _ := svc.List(testContext())
+	if len(endpoints) != 1 {
+		t.Fatalf("expected 1 webhook, got %d", len(endpoints))
+	}
+
+	if err := svc.Delete(testContext(), endpoint.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := svc.Delete(testContext(), endpoint.ID); err == nil {
+		t.Error("expected error deleting missing webhook")
+	}
+
+	endpoints, _ = svc.List(testContext())
+	if len(endpoints) != 0 {
+		t.Errorf("expected 0 webhooks, got %d", len(endpoints))
+	}
+}
+
+func TestWebhookDeliversSignedPayload(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		received  []events.BillEvent
+		signature string
+		body      []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(WebhookSignatureHeader)
+		var event events.BillEvent
+		json.Unmarshal(body, &event)
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	webhooks := newTestWebhookService(1)
+	webhooks.Register(testContext(), &model.RegisterWebhookRequest{URL: server.URL, Secret: "s3cret", EventTypes: []string{"closed"}})
+
+	topic := events.NewTopic()
+	topic.Subscribe(webhooks.HandleEvent)
+	svc := newTestBillingService(t, newMockBillRepository(), WithPublisher(topic))
+
+	bill, _ := svc.CreateBill(testContext(), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	svc.CloseBill(testContext(), bill.ID, allowEmptyClose)
+	webhooks.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 {
+		t.Fatalf("expected only the closed event, got %d deliveries", len(received))
+	}
+	if received[0].Type != events.EventBillClosed || received[0].BillID != bill.ID {
+		t.Errorf("unexpected event %+v", received[0])
+	}
+	if signature != SignWebhookPayload("s3cret", body) {
+		t.Errorf("signature %q does not match payload", signature)
+	}
+}
+
+func TestWebhookRetriesThenSucceeds(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	webhooks := newTestWebhookService(5)
+	webhooks.Register(testContext(), &model.RegisterWebhookRequest{URL: server.URL, Secret: "s3cret"})
+
+	webhooks.HandleEvent(testContext(), events.BillEvent{Type: events.EventBillCreated, BillID: "bill_1", Bill: model.Bill{ID: "bill_1", OrgID: testOrgID}})
+	webhooks.Wait()
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+	if len(webhooks.DeadLetters()) != 0 {
+		t.Error("expected no dead letters after eventual success")
+	}
+}
+
+func TestWebhookDeadLettersAfterMaxAttempts(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhooks := newTestWebhookService(3)
+	endpoint, _ := webhooks.Register(testContext(), &model.RegisterWebhookRequest{URL: server.URL, Secret: "s3cret"})
+
+	webhooks.HandleEvent(testContext(), events.BillEvent{Type: events.EventBillCreated, BillID: "bill_1", Bill: model.Bill{ID: "bill_1", OrgID: testOrgID}})
+	webhooks.Wait()
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+	deadLetters := webhooks.DeadLetters()
+	if len(deadLetters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(deadLetters))
+	}
+	if deadLetters[0].WebhookID != endpoint.ID || deadLetters[0].BillID != "bill_1" {
+		t.Errorf("unexpected dead letter %+v", deadLetters[0])
+	}
+}
+
+func TestShutdownDrainsInFlightDeliveries(t *testing.T) {
+	var attempts, delivered int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		if atomic.AddInt32(&attempts, 1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable) // every delivery needs a retry
+			return
+		}
+		atomic.AddInt32(&delivered, 1)
+	}))
+	defer server.Close()
+
+	webhooks := NewWebhookService(repository.NewInMemoryWebhookRepository(), WebhookConfig{MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond})
+	webhooks.Register(testContext(), &model.RegisterWebhookRequest{URL: server.URL, Secret: "s3cret"})
+	topic := events.NewTopic()
+	topic.Subscribe(webhooks.HandleEvent)
+
+	lc := lifecycle.New()
+	lc.OnShutdown("event topic", topic.Close)
+	lc.OnShutdown("webhooks", webhooks.Shutdown)
+
+	event := events.BillEvent{Type: events.EventBillCreated, BillID: "bill_1", Bill: model.Bill{ID: "bill_1", OrgID: testOrgID}}
+	if err := topic.Publish(testContext(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := lc.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&delivered); got != 1 {
+		t.Errorf("expected the pending delivery to finish before Shutdown returned, got %d deliveries", got)
+	}
+
+	if err := topic.Publish(testContext(), event); !errors.Is(err, events.ErrTopicClosed) {
+		t.Errorf("expected publishing after shutdown to fail with ErrTopicClosed, got %v", err)
+	}
+}
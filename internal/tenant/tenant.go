@@ -0,0 +1,32 @@
+// Package tenant carries the calling organization through a request's context
+// so every bill operation can be scoped to it. Admin requests carry the operator
+// making them instead, for audit logs.
+package tenant
+
+import "context"
+
+type orgIDKey struct{}
+
+type actorKey struct{}
+
+// WithOrgID returns a copy of ctx carrying the caller's organization
+func WithOrgID(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, orgIDKey{}, orgID)
+}
+
+// OrgIDFromContext returns the caller's organization, if one was set
+func OrgIDFromContext(ctx context.Context) (string, bool) {
+	orgID, ok := ctx.Value(orgIDKey{}).(string)
+	return orgID, ok && orgID != ""
+}
+
+// WithActor returns a copy of ctx carrying the operator making an admin request
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the operator making an admin request, if one was set
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
+	billingerrors "fees-api/pkg/errors"
+)
+
+// Validatable is implemented by request types that check their own fields.
+// Encore calls Validate on request payloads before the endpoint runs.
+type Validatable interface {
+	Validate() error
+}
+
+// Validator collects field violations while checking a request
+type Validator struct {
+	violations []billingerrors.FieldViolation
+}
+
+// Check records a violation for the field unless ok holds
+func (v *Validator) Check(ok bool, field, description string) {
+	if !ok {
+		v.violations = append(v.violations, billingerrors.FieldViolation{Field: field, Description: description})
+	}
+}
+
+// Required checks that a string field is set
+func (v *Validator) Required(field, value string) {
+	v.Check(value != "", field, "is required")
+}
+
+// Positive checks that a number is greater than zero
+func (v *Validator) Positive(field string, value float64) {
+	v.Check(value > 0, field, "must be positive")
+}
+
+// NonNegative checks that a number isn't below zero
+func (v *Validator) NonNegative(field string, value float64) {
+	v.Check(value >= 0, field, "must not be negative")
+}
+
+// MaxLength checks that a string has at most max characters
+func (v *Validator) MaxLength(field, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max, field, fmt.Sprintf("must be at most %d characters", max))
+}
+
+// OneOf checks that a set string is one of the allowed values. Empty values pass;
+// combine with Required for mandatory fields.
+func OneOf[T ~string](v *Validator, field string, value T, allowed []T) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.Check(false, field, fmt.Sprintf("must be one of %v", allowed))
+}
+
+// Err returns an invalid_argument error listing every violation, or nil
+func (v *Validator) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return billingerrors.InvalidArgument(v.violations)
+}
+
+// Index builds the path of an element in a list field, e.g. "lineItems[2]"
+func Index(field string, i int) string {
+	return fmt.Sprintf("%s[%d]", field, i)
+}
+
+// Path joins a parent path and a field name
+func Path(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
+
+// DecodeJSON decodes a request body into dst and validates it. Values of the
+// wrong JSON type and malformed bodies become field violations instead of
+// decoder errors, and dst's own Validate runs if it has one.
+func DecodeJSON(data []byte, dst any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return billingerrors.InvalidArgument([]billingerrors.FieldViolation{{
+				Field:       typeErr.Field,
+				Description: "must be " + jsonKind(typeErr.Type),
+			}})
+		}
+		return billingerrors.InvalidArgument([]billingerrors.FieldViolation{{
+			Field:       "",
+			Description: "malformed JSON: " + err.Error(),
+		}})
+	}
+	if validatable, ok := dst.(Validatable); ok {
+		return validatable.Validate()
+	}
+	return nil
+}
+
+// jsonKind names the JSON type a Go type decodes from
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -1,24 +1,225 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Bill errors
 var (
 	ErrBillNotFound = fmt.Errorf("bill not found")
 	ErrBillClosed   = fmt.Errorf("bill is closed")
+	// ErrPendingApproval matches errors for closing a bill that needs approval first
+	ErrPendingApproval = fmt.Errorf("bill close pending approval")
+	// ErrDuplicateLineItemRef matches errors for a line item ref already on the bill
+	ErrDuplicateLineItemRef = fmt.Errorf("duplicate line item ref")
+	// ErrInvalidRate matches InvalidRate errors
+	ErrInvalidRate = fmt.Errorf("invalid exchange rate")
 )
 
-// BillNotFoundError returns an error for bill not found
+// BillNotFoundError returns an error for bill not found, matching ErrBillNotFound
 func BillNotFound(billID string) error {
-	return fmt.Errorf("bill not found: %s", billID)
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf("bill not found: %s", billID), Err: ErrBillNotFound}
 }
 
-// BillClosedError returns an error for closed bill
+// BillClosedError returns an error for closed bill, matching ErrBillClosed
 func BillClosed(billID string) error {
-	return fmt.Errorf("cannot modify closed bill: %s", billID)
+	return &Error{Code: CodeUnknown, Message: fmt.Sprintf("cannot modify closed bill: %s", billID), Err: ErrBillClosed}
+}
+
+// PendingApproval returns an error for closing a bill whose total is above the
+// auto-close threshold without an approval, matching ErrPendingApproval
+func PendingApproval(billID, total, threshold string) error {
+	return &Error{
+		Code:    CodeConflict,
+		Message: fmt.Sprintf("bill %s total %s is above the %s auto-close limit and needs approval to close", billID, total, threshold),
+		Err:     ErrPendingApproval,
+	}
+}
+
+// BillIsDraft returns an error for an operation that requires an activated bill
+func BillIsDraft(billID string) error {
+	return fmt.Errorf("bill is a draft and must be activated first: %s", billID)
+}
+
+// BillEmpty returns an error for closing a bill that has no line items
+func BillEmpty(billID string) error {
+	return fmt.Errorf("cannot close bill without line items: %s", billID)
+}
+
+// TooManyLineItems returns an error for exceeding the per-bill line item cap
+func TooManyLineItems(billID string, max int) error {
+	return fmt.Errorf("bill %s cannot hold more than %d line items", billID, max)
+}
+
+// NoBillingPeriod returns an error for prorating on a bill whose billing period hasn't started
+func NoBillingPeriod(billID string) error {
+	return fmt.Errorf("bill has no billing period to prorate against: %s", billID)
+}
+
+// BillHasLineItems returns an error for changing the currency of a bill that already has line items
+func BillHasLineItems(billID string) error {
+	return fmt.Errorf("bill %s already has line items; create a new bill in the right currency instead", billID)
+}
+
+// BillNotOpen returns an error for an operation that requires an open bill
+func BillNotOpen(billID string) error {
+	return fmt.Errorf("bill is not open: %s", billID)
+}
+
+// BillNotDraft returns an error for activating a bill that isn't a draft
+func BillNotDraft(billID string) error {
+	return fmt.Errorf("bill is not a draft: %s", billID)
+}
+
+// BillNotClosed returns an error for an operation that requires a closed bill
+func BillNotClosed(billID string) error {
+	return fmt.Errorf("bill is not closed: %s", billID)
+}
+
+// BillNotDeleted returns an error for restoring a bill that isn't deleted
+func BillNotDeleted(billID string) error {
+	return fmt.Errorf("bill is not deleted: %s", billID)
 }
 
 // UnsupportedCurrencyError returns an error for unsupported currency
 func UnsupportedCurrency(currency string) error {
 	return fmt.Errorf("unsupported currency: %s", currency)
 }
+
+// InvalidTimezone returns an error for a time zone name that isn't a known IANA zone
+func InvalidTimezone(name string) error {
+	return InvalidArgument([]FieldViolation{{Field: "timezone", Description: fmt.Sprintf("unknown time zone %q", name)}})
+}
+
+// StaleRate returns an error for an exchange rate older than the allowed age
+func StaleRate(from, to string, asOf time.Time) error {
+	return fmt.Errorf("exchange rate %s->%s is stale (as of %s)", from, to, asOf.Format(time.RFC3339))
+}
+
+// InvalidRate returns an error for a configured exchange rate that can't be converted
+// at: zero, negative or not a finite number. It's a configuration problem on the
+// server's side rather than anything the caller did.
+func InvalidRate(from, to string, rate float64) error {
+	return &Error{Code: CodeUnknown, Message: fmt.Sprintf("exchange rate %s->%s is misconfigured: %v is not a positive rate", from, to, rate), Err: ErrInvalidRate}
+}
+
+// LineItemNotFound returns an error for a line item that isn't on the bill
+func LineItemNotFound(billID, lineItemID string) error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf("bill %s has no line item %s", billID, lineItemID)}
+}
+
+// ReceiptNotFound returns an error for a bill that has no receipt, because it never closed
+func ReceiptNotFound(billID string) error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf("bill %s has no receipt", billID)}
+}
+
+// MergeCurrencyMismatch returns an error for merging bills billed in different currencies
+func MergeCurrencyMismatch(targetID, targetCurrency, sourceID, sourceCurrency string) error {
+	return &Error{
+		Code:    CodeConflict,
+		Message: fmt.Sprintf("cannot merge %s bill %s into %s bill %s", sourceCurrency, sourceID, targetCurrency, targetID),
+	}
+}
+
+// ProcessorRefNotFound returns an error for a processor reference not linked to a bill
+func ProcessorRefNotFound(ref string) error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf("no bill is linked to processor reference %q", ref)}
+}
+
+// InvalidProcessorSignature returns an error for a processor webhook whose signature
+// doesn't verify
+func InvalidProcessorSignature(reason string) error {
+	return Unauthenticated("invalid processor signature: %s", reason)
+}
+
+// UnsupportedLineItemType returns an error for a line item type outside the allowed set
+func UnsupportedLineItemType(itemType string) error {
+	return fmt.Errorf("unsupported line item type: %s", itemType)
+}
+
+// DuplicateLineItem returns an error for a line item matching one added moments ago
+func DuplicateLineItem(billID, existingID string) error {
+	return &Error{
+		Code:    CodeConflict,
+		Message: fmt.Sprintf("bill %s already has an identical line item %s added moments ago; set force to add it anyway", billID, existingID),
+	}
+}
+
+// DuplicateLineItemRef returns an error for a line item whose external reference is
+// already used on the bill
+func DuplicateLineItemRef(billID, ref, existingID string) error {
+	return &Error{
+		Code:    CodeConflict,
+		Message: fmt.Sprintf("bill %s already has line item %s with ref %q", billID, existingID, ref),
+		Err:     ErrDuplicateLineItemRef,
+	}
+}
+
+// AmountOverCap returns a validation error for a line item above the per-item
+// amount cap, formatted for display
+func AmountOverCap(cap string) error {
+	return InvalidArgument([]FieldViolation{{
+		Field:       "amount",
+		Description: fmt.Sprintf("exceeds the %s cap per line item; set approved with an approverId for a manager override", cap),
+	}})
+}
+
+// ImportTotalMismatch returns an error for an imported bill whose stated total
+// doesn't match its line items
+func ImportTotalMismatch(stated, computed int64) error {
+	return fmt.Errorf("imported total %d does not match the line items' total %d (cents)", stated, computed)
+}
+
+// InvalidCSV returns a validation error for an uploaded CSV that can't be read as a whole
+func InvalidCSV(format string, args ...interface{}) error {
+	return InvalidArgument([]FieldViolation{{Field: "csv", Description: fmt.Sprintf(format, args...)}})
+}
+
+// CreditExceedsTotal returns an error for a credit that would take a bill's total below zero
+func CreditExceedsTotal(billID string) error {
+	return fmt.Errorf("credit exceeds the current total of bill %s", billID)
+}
+
+// UnsupportedCategory returns an error for an unknown line item category
+func UnsupportedCategory(category string) error {
+	return fmt.Errorf("unsupported category: %s", category)
+}
+
+// UnsupportedStatus returns an error for an unknown bill status filter
+func UnsupportedStatus(status string) error {
+	return fmt.Errorf("unsupported status: %q", status)
+}
+
+// RecurringTemplateNotFound returns an error for recurring template not found
+func RecurringTemplateNotFound(templateID string) error {
+	return fmt.Errorf("recurring template not found: %s", templateID)
+}
+
+// BillTemplateNotFound returns an error for bill template not found
+func BillTemplateNotFound(templateID string) error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf("bill template not found: %s", templateID)}
+}
+
+// TemplateCurrencyMismatch returns an error for a bill whose currency differs from its template's
+func TemplateCurrencyMismatch(templateID, templateCurrency, billCurrency string) error {
+	return fmt.Errorf("template %s is in %s and can't be used for a %s bill", templateID, templateCurrency, billCurrency)
+}
+
+// BillingScheduleNotFound returns an error for billing schedule not found
+func BillingScheduleNotFound(scheduleID string) error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf("billing schedule not found: %s", scheduleID)}
+}
+
+// BillingScheduleExists returns an error for scheduling a customer that already has a schedule
+func BillingScheduleExists(customerID, scheduleID string) error {
+	return &Error{
+		Code:    CodeConflict,
+		Message: fmt.Sprintf("customer %s already has billing schedule %s", customerID, scheduleID),
+	}
+}
+
+// RecurringTemplateCancelled returns an error for changing a cancelled recurring template
+func RecurringTemplateCancelled(templateID string) error {
+	return fmt.Errorf("recurring template is cancelled: %s", templateID)
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Code classifies an error so the API layer can map it to a response status
+type Code string
+
+const (
+	CodeUnknown           Code = "unknown"
+	CodeInvalidArgument   Code = "invalid_argument"
+	CodeNotFound          Code = "not_found"
+	CodeUnauthenticated   Code = "unauthenticated"
+	CodeResourceExhausted Code = "resource_exhausted"
+	CodeUnavailable       Code = "unavailable"
+	CodeConflict          Code = "conflict"
+)
+
+// HTTPStatus returns the HTTP status code equivalent of the error code
+func (c Code) HTTPStatus() int {
+	switch c {
+	case CodeInvalidArgument:
+		return http.StatusBadRequest
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeUnauthenticated:
+		return http.StatusUnauthorized
+	case CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case CodeConflict:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FieldViolation describes why one field of a request is invalid
+type FieldViolation struct {
+	Field       string `json:"field"` // JSON path, e.g. "lineItems[0].amount"
+	Description string `json:"description"`
+}
+
+// Error is an error with an attached Code
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldViolation // set for invalid_argument errors
+	Err     error            // sentinel the error matches with errors.Is, if any
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// CodeOf returns the code of the first coded error in err's chain
+func CodeOf(err error) Code {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return CodeUnknown
+}
+
+// FieldsOf returns the field violations of the first coded error in err's chain
+func FieldsOf(err error) []FieldViolation {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Fields
+	}
+	return nil
+}
+
+// InvalidArgument returns an error for a request with invalid fields
+func InvalidArgument(violations []FieldViolation) error {
+	parts := make([]string, len(violations))
+	for i, violation := range violations {
+		parts[i] = violation.Field + ": " + violation.Description
+	}
+	return &Error{
+		Code:    CodeInvalidArgument,
+		Message: "invalid request: " + strings.Join(parts, "; "),
+		Fields:  violations,
+	}
+}
+
+// Unavailable returns an error for a dependency that cannot be reached
+func Unavailable(format string, args ...interface{}) error {
+	return &Error{Code: CodeUnavailable, Message: fmt.Sprintf(format, args...)}
+}
+
+// RateLimited returns an error for a caller that exceeded its request rate
+func RateLimited(key string) error {
+	return &Error{Code: CodeResourceExhausted, Message: fmt.Sprintf("rate limit exceeded for %s", key)}
+}
+
+// Unauthenticated returns an error for a caller whose organization can't be determined
+func Unauthenticated(format string, args ...interface{}) error {
+	return &Error{Code: CodeUnauthenticated, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,13 @@
+package errors
+
+import "fmt"
+
+// WebhookNotFound returns an error for webhook not found
+func WebhookNotFound(webhookID string) error {
+	return fmt.Errorf("webhook not found: %s", webhookID)
+}
+
+// UnsupportedEventType returns an error for an unknown webhook event type
+func UnsupportedEventType(eventType string) error {
+	return fmt.Errorf("unsupported event type: %s", eventType)
+}
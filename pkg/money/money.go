@@ -0,0 +1,82 @@
+// Package money renders monetary amounts for display so every output format
+// (JSON, CSV, PDF) formats money the same way.
+package money
+
+import (
+	"strconv"
+	"strings"
+
+	"fees-api/internal/model"
+)
+
+// symbol describes how a currency's symbol is written next to an amount
+type symbol struct {
+	text   string
+	suffix bool // written after the amount instead of before it
+}
+
+// symbols holds the display symbol per currency. Currencies missing from the
+// table are rendered with their ISO code after the amount, e.g. "1,500 JPY".
+var symbols = map[model.Currency]symbol{
+	model.CurrencyUSD: {text: "$"},
+	model.CurrencyGEL: {text: "₾"},
+}
+
+// Symbol returns the currency's display symbol, or its ISO code if it has none
+func Symbol(currency model.Currency) string {
+	if sym, ok := symbols[currency]; ok {
+		return sym.text
+	}
+	return string(currency)
+}
+
+// Format renders an amount in minor units using the currency's symbol, decimal
+// places and thousands separators, e.g. "$1,234.56" or "₾37.00"
+func Format(amountMinor int64, currency model.Currency) string {
+	sign := ""
+	if amountMinor < 0 {
+		sign = "-"
+		amountMinor = -amountMinor
+	}
+
+	places := currency.DecimalPlaces()
+	scale := int64(1)
+	for i := 0; i < places; i++ {
+		scale *= 10
+	}
+
+	number := groupThousands(strconv.FormatInt(amountMinor/scale, 10))
+	if places > 0 {
+		fraction := strconv.FormatInt(amountMinor%scale, 10)
+		number += "." + strings.Repeat("0", places-len(fraction)) + fraction
+	}
+
+	sym, ok := symbols[currency]
+	if !ok {
+		return sign + number + " " + string(currency)
+	}
+	if sym.suffix {
+		return sign + number + " " + sym.text
+	}
+	return sign + sym.text + number
+}
+
+// groupThousands inserts a comma between every group of three digits
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package money
+
+import (
+	"testing"
+
+	"fees-api/internal/model"
+)
+
+func TestFormat(t *testing.T) {
+	// Register hypothetical currencies for the duration of the test
+	model.CurrencyDecimalPlaces["JPY"] = 0
+	model.CurrencyDecimalPlaces["KWD"] = 3
+	defer delete(model.CurrencyDecimalPlaces, "JPY")
+	defer delete(model.CurrencyDecimalPlaces, "KWD")
+
+	tests := []struct {
+		name     string
+		amount   int64
+		currency model.Currency
+		want     string
+	}{
+		{name: "USD", amount: 1000, currency: model.CurrencyUSD, want: "$10.00"},
+		{name: "USD cents", amount: 1005, currency: model.CurrencyUSD, want: "$10.05"},
+		{name: "USD thousands", amount: 123456789, currency: model.CurrencyUSD, want: "$1,234,567.89"},
+		{name: "GEL", amount: 3700, currency: model.CurrencyGEL, want: "₾37.00"},
+		{name: "GEL thousands", amount: 100000000, currency: model.CurrencyGEL, want: "₾1,000,000.00"},
+		{name: "zero", amount: 0, currency: model.CurrencyUSD, want: "$0.00"},
+		{name: "negative", amount: -250050, currency: model.CurrencyGEL, want: "-₾2,500.50"},
+		{name: "zero decimals without symbol", amount: 1500, currency: "JPY", want: "1,500 JPY"},
+		{name: "three decimals without symbol", amount: 1234567, currency: "KWD", want: "1,234.567 KWD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.amount, tt.currency); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
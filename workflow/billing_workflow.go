@@ -3,17 +3,61 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 )
 
 // BillingPeriodInput is the input for starting the billing period workflow
 type BillingPeriodInput struct {
-	BillID            string `json:"billId"`
-	Currency          string `json:"currency"`
-	BillingPeriodDays int    `json:"billingPeriodDays"`
+	BillID   string `json:"billId"`
+	OrgID    string `json:"orgId"`
+	Currency string `json:"currency"`
+	// PeriodEnd is the bill's stored period end, when the timer fires. Workflows
+	// started without one run for BillingPeriodDays instead.
+	PeriodEnd         *time.Time `json:"periodEnd,omitempty"`
+	BillingPeriodDays int        `json:"billingPeriodDays"`
+}
+
+// BillingPeriodGrace is how long a billing period workflow may run past its period
+// end, for the close activity and its retries
+const BillingPeriodGrace = 7 * 24 * time.Hour
+
+// closeRetryWindow is how long the period-end close keeps retrying. It ends a day
+// inside BillingPeriodGrace, so a close that never succeeds leaves the workflow
+// close-failed instead of timing it out.
+const closeRetryWindow = BillingPeriodGrace - 24*time.Hour
+
+// Validate checks the input has a period to time: a period end, or a positive
+// number of days
+func (input BillingPeriodInput) Validate() error {
+	if input.PeriodEnd == nil && input.BillingPeriodDays <= 0 {
+		return fmt.Errorf("billing period of bill %s must be at least 1 day, got %d", input.BillID, input.BillingPeriodDays)
+	}
+	return nil
+}
+
+// TimerDuration returns how long after now the period ends, zero if it already has
+func (input BillingPeriodInput) TimerDuration(now time.Time) time.Duration {
+	duration := time.Duration(input.BillingPeriodDays) * 24 * time.Hour
+	if input.PeriodEnd != nil {
+		duration = input.PeriodEnd.Sub(now)
+	}
+	if duration < 0 {
+		duration = 0
+	}
+	return duration
+}
+
+// ExecutionTimeout returns how long a workflow started now may run: until the
+// period ends, plus BillingPeriodGrace
+func (input BillingPeriodInput) ExecutionTimeout(now time.Time) time.Duration {
+	return input.TimerDuration(now) + BillingPeriodGrace
 }
 
 // BillState represents the current state of a bill in the workflow
@@ -25,16 +69,47 @@ type BillState struct {
 	LineItemCount int        `json:"lineItemCount"`
 	StartedAt     time.Time  `json:"startedAt"`
 	ClosedAt      *time.Time `json:"closedAt,omitempty"`
+	CloseReason   string     `json:"closeReason,omitempty"`
+}
+
+// BillStateQuery is the query name that returns the workflow's BillState
+const BillStateQuery = "bill-state"
+
+// CloseReasonPeriodEnded is the close reason recorded when the period timer fires
+const CloseReasonPeriodEnded = "period_ended"
+
+// CloseBillSignal is the input for the close bill signal
+type CloseBillSignal struct {
+	Reason string `json:"reason"` // why the bill closed early, e.g. churn, dispute or manual
+	// Force closes the workflow even while its period-end close is pending, instead
+	// of leaving the close to it
+	Force bool `json:"force,omitempty"`
 }
 
 // AddLineItemSignalInput is the input for adding a line item signal
 type AddLineItemSignalInput struct {
-	Amount   float64 `json:"amount"`
-	Currency string  `json:"currency"`
+	LineItemID string  `json:"lineItemId,omitempty"` // lets a later update find the item
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`
+}
+
+// UpdateLineItemSignalName is the signal that reports a corrected line item
+const UpdateLineItemSignalName = "update-line-item"
+
+// UpdateLineItemSignalInput is the input for the update line item signal. Amount is
+// the item's new net amount in the bill's currency.
+type UpdateLineItemSignalInput struct {
+	LineItemID string  `json:"lineItemId"`
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`
 }
 
 // BillingPeriodWorkflow manages the lifecycle of a billing period
 func BillingPeriodWorkflow(ctx workflow.Context, input BillingPeriodInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
 	// Initialize state
 	state := BillState{
 		BillID:        input.BillID,
@@ -50,29 +125,52 @@ func BillingPeriodWorkflow(ctx workflow.Context, input BillingPeriodInput) error
 	}
 	ctx = workflow.WithActivityOptions(ctx, ao)
 
-	// Set up timer for billing period end
-	timerDuration := time.Duration(input.BillingPeriodDays) * 24 * time.Hour
-	timerFuture := workflow.NewTimer(ctx, timerDuration)
+	// Set up timer for billing period end; a period that already ended closes at once
+	timerFuture := workflow.NewTimer(ctx, input.TimerDuration(workflow.Now(ctx)))
+
+	// Net amount of each signalled line item, so an update can move the total by
+	// the difference
+	amounts := make(map[string]float64)
 
 	// Set up signal channels
 	addLineItemChan := workflow.GetSignalChannel(ctx, "add-line-item")
+	updateLineItemChan := workflow.GetSignalChannel(ctx, UpdateLineItemSignalName)
 	closeBillChan := workflow.GetSignalChannel(ctx, "close-bill")
 
+	// The period-end close retries for closeRetryWindow; a forced close cancels it
+	// if it's still retrying
+	closeCtx, cancelClose := workflow.WithCancel(workflow.WithScheduleToCloseTimeout(workflow.WithRetryPolicy(ctx, temporal.RetryPolicy{
+		InitialInterval:    time.Second,
+		BackoffCoefficient: 2,
+		MaximumInterval:    time.Hour,
+	}), closeRetryWindow))
+
 	// Selector for handling events
 	selector := workflow.NewSelector(ctx)
 	selector.AddFuture(timerFuture, func(f workflow.Future) {
-		// Timer fired - period ended, auto-close the bill
-		state.Status = "closed"
-		now := workflow.Now(ctx)
-		state.ClosedAt = &now
+		// Timer fired - period ended, auto-close the bill. Until the close API call
+		// succeeds the workflow is closing: the activity retries, e.g. while the
+		// bill awaits approval, for closeRetryWindow.
+		state.Status = "closing"
+		periodEnd := workflow.Now(ctx)
 
 		// Call activity to close the bill via HTTP API (needed for auto-close)
-		err := workflow.ExecuteActivity(ctx, CloseBillActivity, CloseBillActivityInput{
+		closeFuture := workflow.ExecuteActivity(closeCtx, CloseBillActivity, CloseBillActivityInput{
 			BillID: input.BillID,
-		}).Get(ctx, nil)
-		if err != nil {
-			state.Status = "close-failed"
-		}
+			OrgID:  input.OrgID,
+		})
+		selector.AddFuture(closeFuture, func(f workflow.Future) {
+			if state.Status != "closing" {
+				return // a forced close got there first
+			}
+			if err := f.Get(ctx, nil); err != nil {
+				state.Status = "close-failed"
+				return
+			}
+			state.Status = "closed"
+			state.ClosedAt = &periodEnd
+			state.CloseReason = CloseReasonPeriodEnded
+		})
 	})
 	selector.AddReceive(addLineItemChan, func(c workflow.ReceiveChannel, more bool) {
 		var signalInput AddLineItemSignalInput
@@ -81,20 +179,51 @@ func BillingPeriodWorkflow(ctx workflow.Context, input BillingPeriodInput) error
 		// Update workflow state - no need to call API since it was already processed
 		state.LineItemCount++
 		state.TotalAmount += signalInput.Amount
+		if signalInput.LineItemID != "" {
+			amounts[signalInput.LineItemID] = signalInput.Amount
+		}
+	})
+	selector.AddReceive(updateLineItemChan, func(c workflow.ReceiveChannel, more bool) {
+		var signalInput UpdateLineItemSignalInput
+		c.Receive(ctx, &signalInput)
+
+		// An item the workflow never saw added has no amount to correct; the bill
+		// record stays authoritative
+		previous, ok := amounts[signalInput.LineItemID]
+		if !ok {
+			workflow.GetLogger(ctx).Warn("update for an unknown line item ignored", "lineItemId", signalInput.LineItemID)
+			return
+		}
+		state.TotalAmount += signalInput.Amount - previous
+		amounts[signalInput.LineItemID] = signalInput.Amount
 	})
 	selector.AddReceive(closeBillChan, func(c workflow.ReceiveChannel, more bool) {
+		var signal CloseBillSignal
+		c.Receive(ctx, &signal)
+
+		// While the period-end close is pending it closes the workflow, as it does
+		// for a bill that was already closed; only a forced close cuts it short
+		if state.Status == "closing" {
+			if !signal.Force {
+				workflow.GetLogger(ctx).Info("close left to the pending period-end close", "billId", input.BillID)
+				return
+			}
+			cancelClose()
+		}
+
 		state.Status = "closed"
 		now := workflow.Now(ctx)
 		state.ClosedAt = &now
+		state.CloseReason = signal.Reason
 	})
 
 	// Register query handler
-	workflow.SetQueryHandler(ctx, "bill-state", func() (BillState, error) {
+	workflow.SetQueryHandler(ctx, BillStateQuery, func() (BillState, error) {
 		return state, nil
 	})
 
 	// Wait until closed
-	for state.Status == "open" {
+	for state.Status == "open" || state.Status == "closing" {
 		selector.Select(ctx)
 	}
 
@@ -106,16 +235,42 @@ func BillingPeriodWorkflow(ctx workflow.Context, input BillingPeriodInput) error
 // CloseBillActivityInput represents input for closing a bill
 type CloseBillActivityInput struct {
 	BillID string `json:"billId"`
+	OrgID  string `json:"orgId"`
+}
+
+// defaultAPIBaseURL is where activities reach the API unless BILLING_API_BASE_URL
+// says otherwise
+const defaultAPIBaseURL = "http://127.0.0.1:4000"
+
+// apiBaseURL returns the base URL activities call the API at
+func apiBaseURL() string {
+	if base := os.Getenv("BILLING_API_BASE_URL"); base != "" {
+		return strings.TrimSuffix(base, "/")
+	}
+	return defaultAPIBaseURL
 }
 
-// API base URL - in production this would be configurable
-const apiBaseURL = "http://127.0.0.1:4000"
+// postAsOrg POSTs to the API on behalf of an org, authenticating with the internal
+// token from BILLING_INTERNAL_TOKEN
+func postAsOrg(ctx context.Context, url, orgID string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("BILLING_INTERNAL_TOKEN"))
+	req.Header.Set("X-Org-ID", orgID)
+	return http.DefaultClient.Do(req)
+}
 
 // CloseBillActivity closes a bill via HTTP API (used for auto-close timer)
 func CloseBillActivity(ctx context.Context, input CloseBillActivityInput) error {
-	url := fmt.Sprintf("%s/bills/%s/close", apiBaseURL, input.BillID)
+	url := fmt.Sprintf("%s/bills/%s/close", apiBaseURL(), input.BillID)
 
-	resp, err := http.Post(url, "application/json", nil)
+	// A bill that saw no usage during its period still closes at period end; the
+	// empty-bill guard is for accidental manual closes. A bill closed while the
+	// activity retried counts as closed.
+	resp, err := postAsOrg(ctx, url, input.OrgID, strings.NewReader(`{"allowEmpty":true,"idempotent":true}`))
 	if err != nil {
 		return err
 	}
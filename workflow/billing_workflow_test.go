@@ -0,0 +1,328 @@
+package workflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fees-api/internal/events"
+	"fees-api/internal/handlers"
+	"fees-api/internal/model"
+	"fees-api/internal/presentation"
+	"fees-api/internal/repository"
+	"fees-api/internal/service"
+	"fees-api/internal/tenant"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestBillingPeriodWorkflowRecordsCloseReason(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("close-bill", CloseBillSignal{Reason: "dispute"})
+	}, time.Hour)
+
+	env.ExecuteWorkflow(BillingPeriodWorkflow, BillingPeriodInput{BillID: "bill_1", Currency: "USD", BillingPeriodDays: 30})
+
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("expected the workflow to complete after the close signal")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("workflow error = %v", err)
+	}
+
+	value, err := env.QueryWorkflow(BillStateQuery)
+	if err != nil {
+		t.Fatalf("QueryWorkflow() error = %v", err)
+	}
+	var state BillState
+	if err := value.Get(&state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	if state.Status != "closed" || state.CloseReason != "dispute" {
+		t.Errorf("expected closed with reason dispute, got %q with %q", state.Status, state.CloseReason)
+	}
+}
+
+func TestBillingPeriodWorkflowPeriodEndReason(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(CloseBillActivity)
+	env.OnActivity(CloseBillActivity, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(BillingPeriodWorkflow, BillingPeriodInput{BillID: "bill_1", Currency: "USD", BillingPeriodDays: 1})
+
+	value, err := env.QueryWorkflow(BillStateQuery)
+	if err != nil {
+		t.Fatalf("QueryWorkflow() error = %v", err)
+	}
+	var state BillState
+	value.Get(&state)
+	if state.CloseReason != CloseReasonPeriodEnded {
+		t.Errorf("expected reason %q, got %q", CloseReasonPeriodEnded, state.CloseReason)
+	}
+}
+
+func TestBillingPeriodWorkflowClosesAtPeriodEnd(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(CloseBillActivity)
+	env.OnActivity(CloseBillActivity, mock.Anything, mock.Anything).Return(nil)
+
+	start := env.Now()
+	periodEnd := start.Add(36 * time.Hour)
+	env.ExecuteWorkflow(BillingPeriodWorkflow, BillingPeriodInput{BillID: "bill_1", Currency: "USD", PeriodEnd: &periodEnd, BillingPeriodDays: 30})
+
+	value, err := env.QueryWorkflow(BillStateQuery)
+	if err != nil {
+		t.Fatalf("QueryWorkflow() error = %v", err)
+	}
+	var state BillState
+	value.Get(&state)
+	if state.ClosedAt == nil || !state.ClosedAt.Equal(periodEnd) {
+		t.Errorf("expected the bill to close at the period end %s, got %v", periodEnd, state.ClosedAt)
+	}
+}
+
+func TestBillingPeriodWorkflowForceCloseDuringGrace(t *testing.T) {
+	// The bill is over the auto-close threshold and awaits approval, so the
+	// period-end close keeps failing
+	topic := events.NewTopic()
+	var closedEvents atomic.Int32
+	topic.Subscribe(func(ctx context.Context, event events.BillEvent) error {
+		if event.Type == events.EventBillClosed {
+			closedEvents.Add(1)
+		}
+		return nil
+	})
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository(), service.WithMaxAutoCloseTotal(500), service.WithPublisher(topic))
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	orgCtx := tenant.WithOrgID(context.Background(), "org_a")
+	bill, err := svc.CreateBill(orgCtx, &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	if _, err := svc.AddLineItem(orgCtx, bill.ID, &model.AddLineItemRequest{Description: "Fee", Amount: 10.00, Currency: model.CurrencyUSD}); err != nil {
+		t.Fatalf("AddLineItem() error = %v", err)
+	}
+
+	h := handlers.NewBillingHandler(svc)
+	auth := handlers.NewAuthenticator(nil, nil, "internal_token")
+	var closeAttempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		closeAttempts.Add(1)
+		billID := handlers.PathParams(r.URL.Path, "/bills/:billID/close")["billID"]
+		auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CloseBillRequest) (*presentation.CloseBillResponse, error) {
+			return h.CloseBill(ctx, billID, req)
+		})).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("BILLING_API_BASE_URL", server.URL)
+	t.Setenv("BILLING_INTERNAL_TOKEN", "internal_token")
+
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(CloseBillActivity)
+
+	queryState := func() BillState {
+		value, err := env.QueryWorkflow(BillStateQuery)
+		if err != nil {
+			t.Fatalf("QueryWorkflow() error = %v", err)
+		}
+		var state BillState
+		value.Get(&state)
+		return state
+	}
+
+	var forcedAt time.Time
+	env.RegisterDelayedCallback(func() {
+		if state := queryState(); state.Status != "closing" {
+			t.Errorf("expected the workflow closing after its period ended, got %q", state.Status)
+		}
+		env.SignalWorkflow("close-bill", CloseBillSignal{Reason: "manual"})
+	}, 24*time.Hour+5*time.Second)
+	env.RegisterDelayedCallback(func() {
+		if state := queryState(); state.Status != "closing" || state.ClosedAt != nil {
+			t.Errorf("expected a normal close to leave the workflow closing, got %q", state.Status)
+		}
+		// What the admin force close endpoint does: close the bill, then the workflow
+		if _, err := svc.ForceCloseBill(tenant.WithActor(orgCtx, "ops_1"), bill.ID); err != nil {
+			t.Errorf("ForceCloseBill() error = %v", err)
+		}
+		forcedAt = env.Now()
+		env.SignalWorkflow("close-bill", CloseBillSignal{Reason: "ops_override", Force: true})
+	}, 24*time.Hour+10*time.Second)
+
+	env.ExecuteWorkflow(BillingPeriodWorkflow, BillingPeriodInput{BillID: bill.ID, OrgID: "org_a", Currency: "USD", BillingPeriodDays: 1})
+
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("expected the forced close to complete the workflow")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("workflow error = %v", err)
+	}
+	state := queryState()
+	if state.Status != "closed" || state.CloseReason != "ops_override" {
+		t.Errorf("expected closed with reason ops_override, got %q with %q", state.Status, state.CloseReason)
+	}
+	if state.ClosedAt == nil || !state.ClosedAt.Equal(forcedAt) {
+		t.Errorf("expected a single close at the forced close %s, got %v", forcedAt, state.ClosedAt)
+	}
+	if closeAttempts.Load() < 2 {
+		t.Errorf("expected the period-end close retried during grace, got %d attempts", closeAttempts.Load())
+	}
+
+	closed, err := svc.GetBill(orgCtx, bill.ID, false)
+	if err != nil {
+		t.Fatalf("GetBill() error = %v", err)
+	}
+	if closed.Status != model.BillStatusClosed || closed.CloseApproval == nil || closed.CloseApproval.ApprovedBy != "ops_1" {
+		t.Errorf("expected the bill closed with ops_1's approval, got %s with %+v", closed.Status, closed.CloseApproval)
+	}
+	if got := closedEvents.Load(); got != 1 {
+		t.Errorf("expected exactly one closed event, got %d", got)
+	}
+}
+
+func TestBillingPeriodWorkflowUpdatesLineItem(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("add-line-item", AddLineItemSignalInput{LineItemID: "li_1", Amount: 10, Currency: "USD"})
+		env.SignalWorkflow("add-line-item", AddLineItemSignalInput{LineItemID: "li_2", Amount: 5, Currency: "USD"})
+	}, time.Hour)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(UpdateLineItemSignalName, UpdateLineItemSignalInput{LineItemID: "li_1", Amount: 12.5, Currency: "USD"})
+		// Never added, so there's nothing to correct
+		env.SignalWorkflow(UpdateLineItemSignalName, UpdateLineItemSignalInput{LineItemID: "li_unknown", Amount: 100, Currency: "USD"})
+	}, 2*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		value, err := env.QueryWorkflow(BillStateQuery)
+		if err != nil {
+			t.Fatalf("QueryWorkflow() error = %v", err)
+		}
+		var state BillState
+		value.Get(&state)
+		if state.TotalAmount != 17.5 || state.LineItemCount != 2 {
+			t.Errorf("expected 2 items totalling 17.5, got %d totalling %v", state.LineItemCount, state.TotalAmount)
+		}
+		env.SignalWorkflow("close-bill", CloseBillSignal{Reason: "manual"})
+	}, 3*time.Hour)
+
+	env.ExecuteWorkflow(BillingPeriodWorkflow, BillingPeriodInput{BillID: "bill_1", Currency: "USD", BillingPeriodDays: 30})
+
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("workflow error = %v", err)
+	}
+}
+
+func TestBillingPeriodInputValidate(t *testing.T) {
+	periodEnd := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		input   BillingPeriodInput
+		wantErr bool
+	}{
+		{name: "zero days", input: BillingPeriodInput{BillID: "bill_1", BillingPeriodDays: 0}, wantErr: true},
+		{name: "negative days", input: BillingPeriodInput{BillID: "bill_1", BillingPeriodDays: -3}, wantErr: true},
+		{name: "positive days", input: BillingPeriodInput{BillID: "bill_1", BillingPeriodDays: 30}},
+		{name: "period end", input: BillingPeriodInput{BillID: "bill_1", PeriodEnd: &periodEnd}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.input.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBillingPeriodWorkflowRejectsEmptyPeriod(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(CloseBillActivity)
+
+	env.ExecuteWorkflow(BillingPeriodWorkflow, BillingPeriodInput{BillID: "bill_1", Currency: "USD"})
+
+	if err := env.GetWorkflowError(); err == nil {
+		t.Fatal("expected a workflow without a period to fail rather than close the bill at once")
+	}
+	env.AssertNotCalled(t, "CloseBillActivity", mock.Anything, mock.Anything)
+}
+
+func TestBillingPeriodTimerDuration(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	periodEnd := now.Add(36 * time.Hour)
+	ended := now.Add(-time.Hour)
+	tests := []struct {
+		name  string
+		input BillingPeriodInput
+		want  time.Duration
+	}{
+		{name: "days", input: BillingPeriodInput{BillingPeriodDays: 30}, want: 30 * 24 * time.Hour},
+		{name: "period end", input: BillingPeriodInput{PeriodEnd: &periodEnd, BillingPeriodDays: 30}, want: 36 * time.Hour},
+		{name: "period already ended", input: BillingPeriodInput{PeriodEnd: &ended}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.input.TimerDuration(now); got != tt.want {
+				t.Errorf("TimerDuration() = %v, want %v", got, tt.want)
+			}
+			if got := tt.input.ExecutionTimeout(now); got != tt.want+BillingPeriodGrace {
+				t.Errorf("ExecutionTimeout() = %v, want %v", got, tt.want+BillingPeriodGrace)
+			}
+		})
+	}
+}
+
+func TestCloseBillActivityAuthenticatesAsOrg(t *testing.T) {
+	svc, err := service.NewBillingService(repository.NewInMemoryBillRepository())
+	if err != nil {
+		t.Fatalf("NewBillingService() error = %v", err)
+	}
+	bill, err := svc.CreateBill(tenant.WithOrgID(context.Background(), "org_a"), &model.CreateBillRequest{Currency: model.CurrencyUSD})
+	if err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+
+	// The close endpoint as the billing service serves it
+	h := handlers.NewBillingHandler(svc)
+	auth := handlers.NewAuthenticator(nil, nil, "internal_token")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		billID := handlers.PathParams(r.URL.Path, "/bills/:billID/close")["billID"]
+		auth.RequireOrg(handlers.Typed(func(ctx context.Context, req *model.CloseBillRequest) (*presentation.CloseBillResponse, error) {
+			return h.CloseBill(ctx, billID, req)
+		})).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("BILLING_API_BASE_URL", server.URL)
+
+	t.Setenv("BILLING_INTERNAL_TOKEN", "wrong_token")
+	if err := CloseBillActivity(context.Background(), CloseBillActivityInput{BillID: bill.ID, OrgID: "org_a"}); err == nil {
+		t.Error("expected the close to fail with the wrong internal token")
+	}
+	if err := CloseBillActivity(context.Background(), CloseBillActivityInput{BillID: bill.ID, OrgID: "org_b"}); err == nil {
+		t.Error("expected the close to fail for another org's bill")
+	}
+
+	t.Setenv("BILLING_INTERNAL_TOKEN", "internal_token")
+	if err := CloseBillActivity(context.Background(), CloseBillActivityInput{BillID: bill.ID, OrgID: "org_a"}); err != nil {
+		t.Fatalf("CloseBillActivity() error = %v", err)
+	}
+	closed, err := svc.GetBill(tenant.WithOrgID(context.Background(), "org_a"), bill.ID, false)
+	if err != nil {
+		t.Fatalf("GetBill() error = %v", err)
+	}
+	if closed.Status != model.BillStatusClosed {
+		t.Errorf("expected the empty bill closed at period end, got %s", closed.Status)
+	}
+}
@@ -0,0 +1,88 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// RecurringBillInput is the input for starting the recurring bill workflow
+type RecurringBillInput struct {
+	TemplateID   string `json:"templateId"`
+	OrgID        string `json:"orgId"`
+	IntervalDays int    `json:"intervalDays"`
+}
+
+// RecurringBillWorkflow materializes a bill from a recurring template every interval.
+// Each materialized bill gets its own billing period workflow lasting the interval,
+// so a bill's period closes as the next renewal opens. Paused templates are skipped
+// on schedule; cancelling the template ends the workflow.
+func RecurringBillWorkflow(ctx workflow.Context, input RecurringBillInput) error {
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+	}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	var result MaterializeRecurringBillResult
+	err := workflow.ExecuteActivity(ctx, MaterializeRecurringBillActivity, MaterializeRecurringBillInput{
+		TemplateID: input.TemplateID,
+		OrgID:      input.OrgID,
+	}).Get(ctx, &result)
+	if err != nil {
+		return err
+	}
+	if result.Cancelled {
+		return nil
+	}
+
+	// Wait for the next renewal, or stop early if the template is cancelled
+	cancelled := false
+	selector := workflow.NewSelector(ctx)
+	selector.AddFuture(workflow.NewTimer(ctx, time.Duration(input.IntervalDays)*24*time.Hour), func(f workflow.Future) {})
+	selector.AddReceive(workflow.GetSignalChannel(ctx, "cancel-template"), func(c workflow.ReceiveChannel, more bool) {
+		c.Receive(ctx, nil)
+		cancelled = true
+	})
+	selector.Select(ctx)
+	if cancelled {
+		return nil
+	}
+
+	// Start a fresh run per renewal so history doesn't grow without bound
+	return workflow.NewContinueAsNewError(ctx, RecurringBillWorkflow, input)
+}
+
+// MaterializeRecurringBillInput represents input for materializing a recurring bill
+type MaterializeRecurringBillInput struct {
+	TemplateID string `json:"templateId"`
+	OrgID      string `json:"orgId"`
+}
+
+// MaterializeRecurringBillResult reports the outcome of a renewal
+type MaterializeRecurringBillResult struct {
+	Cancelled bool `json:"cancelled"`
+}
+
+// MaterializeRecurringBillActivity creates the next bill for a template via HTTP API
+func MaterializeRecurringBillActivity(ctx context.Context, input MaterializeRecurringBillInput) (MaterializeRecurringBillResult, error) {
+	url := fmt.Sprintf("%s/internal/recurring-templates/%s/materialize", apiBaseURL(), input.TemplateID)
+
+	var result MaterializeRecurringBillResult
+	resp, err := postAsOrg(ctx, url, input.OrgID, nil)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return result, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("decode materialize response: %v", err)
+	}
+	return result, nil
+}
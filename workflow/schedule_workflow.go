@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// ScheduledBillingInput is the input the billing schedule starts each run with
+type ScheduledBillingInput struct {
+	ScheduleID string `json:"scheduleId"`
+	OrgID      string `json:"orgId"`
+}
+
+// ScheduledBillingWorkflow is run by a customer's Temporal schedule on the 1st of
+// every month. It asks the API to open the month's bill, which also starts the
+// bill's billing period workflow. The previous period's bill may still be closing;
+// the API opens the new one regardless, and skips a month that already has its bill.
+func ScheduledBillingWorkflow(ctx workflow.Context, input ScheduledBillingInput) error {
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+	}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	var result StartScheduledPeriodResult
+	err := workflow.ExecuteActivity(ctx, StartScheduledPeriodActivity, input).Get(ctx, &result)
+	if err != nil {
+		return err
+	}
+	if result.Skipped {
+		workflow.GetLogger(ctx).Info("scheduled billing period skipped", "scheduleId", input.ScheduleID)
+	}
+	return nil
+}
+
+// StartScheduledPeriodResult reports the outcome of a scheduled period start
+type StartScheduledPeriodResult struct {
+	Skipped bool `json:"skipped"`
+}
+
+// StartScheduledPeriodActivity opens the month's bill for a schedule via HTTP API
+func StartScheduledPeriodActivity(ctx context.Context, input ScheduledBillingInput) (StartScheduledPeriodResult, error) {
+	url := fmt.Sprintf("%s/internal/billing-schedules/%s/start", apiBaseURL(), input.ScheduleID)
+
+	var result StartScheduledPeriodResult
+	resp, err := postAsOrg(ctx, url, input.OrgID, nil)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return result, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("decode start period response: %v", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,26 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestScheduledBillingWorkflowStartsPeriodOnce(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(StartScheduledPeriodActivity)
+	input := ScheduledBillingInput{ScheduleID: "sch_1", OrgID: "org_1"}
+	env.OnActivity(StartScheduledPeriodActivity, mock.Anything, input).Return(StartScheduledPeriodResult{}, nil).Once()
+
+	env.ExecuteWorkflow(ScheduledBillingWorkflow, input)
+
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("expected the scheduled run to complete")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("workflow error = %v", err)
+	}
+	env.AssertExpectations(t)
+}